@@ -0,0 +1,92 @@
+// Package api serves notes over HTTP as JSON, and talks to such a server.
+//
+// While 'cli-note serve' runs it is the only process with the database open,
+// since bolt allows a single writer. It records its address in a server file
+// in the notes directory (see Info), which the CLI reads to send its requests
+// through the server with a Client instead of opening the database.
+//
+// Endpoints:
+//
+//	GET    /notes              list notes (?notebook= limits them to a notebook)
+//	POST   /notes              create a note from a NoteInput
+//	GET    /notes/{id}         get a note by ID, ID prefix or title (?notebook=)
+//	GET    /notes/{id}/html    get a note's content rendered as HTML
+//	PUT    /notes/{id}         update the fields set in a NoteInput
+//	DELETE /notes/{id}         delete a note
+//	POST   /notes/delete       delete a list of Deletions, all or none
+//	GET    /search?q=          search titles and content (?notebook=)
+//	GET    /tags               list tags with their note counts (?notebook=)
+//	GET    /status             report the server's process ID
+//
+// Responses with a single note carry an ETag derived from its modification
+// time. PUT and DELETE honor If-Match, answering 412 Precondition Failed if
+// the note changed since it was read. Request bodies must be sent as
+// application/json. Errors are returned as {"error": "..."}.
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+var (
+	// ErrConflict is returned when a note with the same title already exists.
+	ErrConflict = errors.New("note already exists")
+	// ErrPreconditionFailed is returned when If-Match doesn't match a note's ETag.
+	ErrPreconditionFailed = errors.New("note was modified since it was read")
+	// ErrInvalidRequest is returned for malformed requests and notes that fail validation.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrUnauthorized is returned when the bearer token is missing or wrong.
+	ErrUnauthorized = errors.New("missing or invalid token")
+)
+
+// Store is the note storage served by the API. Handles identify notes by
+// title within a notebook or by a unique ID prefix, as on the command line.
+// An empty notebook means every notebook when listing, searching and
+// counting tags, and the store's default notebook otherwise.
+type Store interface {
+	Notes(notebook string) ([]models.Note, error)
+	Note(notebook, handle string) (models.Note, error)
+	CreateNote(input NoteInput) (models.Note, error)
+	// UpdateNote and DeleteNote fail with ErrPreconditionFailed unless
+	// ifMatch is empty or matches the note's ETag.
+	UpdateNote(notebook, handle, ifMatch string, input NoteInput) (models.Note, error)
+	DeleteNote(notebook, handle, ifMatch string) (models.Note, error)
+	// DeleteNotes deletes several notes at once: if any of them is missing
+	// or fails its If-Match, none are deleted.
+	DeleteNotes(deletions []Deletion) ([]models.Note, error)
+	Search(query, notebook string) ([]models.Note, error)
+	Tags(notebook string) ([]Tag, error)
+}
+
+// NoteInput holds the fields of a note to create or update.
+// Fields left nil are not changed, or take their default when creating.
+type NoteInput struct {
+	Title    *string    `json:"title,omitempty"`
+	Notebook *string    `json:"notebook,omitempty"`
+	Content  *string    `json:"content,omitempty"`
+	Tags     *[]string  `json:"tags,omitempty"`
+	Pinned   *bool      `json:"pinned,omitempty"`
+	Archived *bool      `json:"archived,omitempty"`
+	Due      *time.Time `json:"due,omitempty"`
+}
+
+// Deletion identifies a note to delete with DeleteNotes by its full ID,
+// with the ETag it must still have unless IfMatch is empty.
+type Deletion struct {
+	ID      string `json:"id"`
+	IfMatch string `json:"if_match,omitempty"`
+}
+
+// Tag is a tag and the number of notes with it.
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Status describes a running server.
+type Status struct {
+	PID int `json:"pid"`
+}
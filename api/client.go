@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+const (
+	// requestTimeout bounds every request to the server.
+	requestTimeout = 10 * time.Second
+	// pingTimeout bounds the request checking whether a server is running,
+	// so a stale server file doesn't hold up commands.
+	pingTimeout = time.Second
+)
+
+// Client is a Store backed by a running server.
+type Client struct {
+	info Info
+	http *http.Client
+}
+
+// NewClient returns a client for the server described by info.
+func NewClient(info Info) *Client {
+	return &Client{info: info, http: &http.Client{Timeout: requestTimeout}}
+}
+
+// Addr returns the address of the server.
+func (c *Client) Addr() string {
+	return c.info.Addr
+}
+
+// Error is an error response from the server. It wraps the error the
+// status code stands for, such as db.ErrNoteNotFound or ErrConflict.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return db.ErrNoteNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusBadRequest:
+		return ErrInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	default:
+		return nil
+	}
+}
+
+// Ping checks that the server answers and is the process that wrote the server file.
+func (c *Client) Ping() error {
+	client := *c.http
+	client.Timeout = pingTimeout
+	var status Status
+	if err := c.doWith(&client, http.MethodGet, "/status", nil, "", nil, &status); err != nil {
+		return err
+	}
+	if c.info.PID != 0 && status.PID != c.info.PID {
+		return fmt.Errorf("server at %s has process ID %d, expected %d", c.info.Addr, status.PID, c.info.PID)
+	}
+	return nil
+}
+
+func (c *Client) Notes(notebook string) ([]models.Note, error) {
+	var notes []models.Note
+	err := c.do(http.MethodGet, "/notes", url.Values{"notebook": {notebook}}, "", nil, &notes)
+	return notes, err
+}
+
+func (c *Client) Note(notebook, handle string) (models.Note, error) {
+	var note models.Note
+	err := c.do(http.MethodGet, "/notes/"+handle, url.Values{"notebook": {notebook}}, "", nil, &note)
+	return note, err
+}
+
+func (c *Client) CreateNote(input NoteInput) (models.Note, error) {
+	var note models.Note
+	err := c.do(http.MethodPost, "/notes", nil, "", input, &note)
+	return note, err
+}
+
+func (c *Client) UpdateNote(notebook, handle, ifMatch string, input NoteInput) (models.Note, error) {
+	var note models.Note
+	err := c.do(http.MethodPut, "/notes/"+handle, url.Values{"notebook": {notebook}}, ifMatch, input, &note)
+	return note, err
+}
+
+// DeleteNote deletes a note. The server doesn't return the deleted note,
+// so the returned note is empty.
+func (c *Client) DeleteNote(notebook, handle, ifMatch string) (models.Note, error) {
+	err := c.do(http.MethodDelete, "/notes/"+handle, url.Values{"notebook": {notebook}}, ifMatch, nil, nil)
+	return models.Note{}, err
+}
+
+// DeleteNotes deletes several notes at once. The server doesn't return the
+// deleted notes, so none are returned.
+func (c *Client) DeleteNotes(deletions []Deletion) ([]models.Note, error) {
+	err := c.do(http.MethodPost, "/notes/delete", nil, "", deletions, nil)
+	return nil, err
+}
+
+func (c *Client) Search(query, notebook string) ([]models.Note, error) {
+	var notes []models.Note
+	err := c.do(http.MethodGet, "/search", url.Values{"q": {query}, "notebook": {notebook}}, "", nil, &notes)
+	return notes, err
+}
+
+func (c *Client) Tags(notebook string) ([]Tag, error) {
+	var tags []Tag
+	err := c.do(http.MethodGet, "/tags", url.Values{"notebook": {notebook}}, "", nil, &tags)
+	return tags, err
+}
+
+func (c *Client) do(method, path string, query url.Values, ifMatch string, body, result any) error {
+	return c.doWith(c.http, method, path, query, ifMatch, body, result)
+}
+
+// doWith sends a request with an optional JSON body and decodes the JSON
+// response into result, if it isn't nil. The path is escaped here, so it
+// must not be escaped already. Empty query values are left out.
+func (c *Client) doWith(client *http.Client, method, path string, query url.Values, ifMatch string, body, result any) error {
+	target := url.URL{Scheme: "http", Host: c.info.Addr, Path: path}
+	values := url.Values{}
+	for key, value := range query {
+		if len(value) > 0 && value[0] != "" {
+			values[key] = value
+		}
+	}
+	target.RawQuery = values.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequest(method, target.String(), reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if ifMatch != "" {
+		request.Header.Set("If-Match", ifMatch)
+	}
+	if c.info.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.info.Token)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error contacting server at %s: %w", c.info.Addr, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		var failure errorResponse
+		if err := json.NewDecoder(response.Body).Decode(&failure); err != nil || failure.Error == "" {
+			failure.Error = response.Status
+		}
+		return &Error{StatusCode: response.StatusCode, Message: failure.Error}
+	}
+	if result == nil || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("error decoding response from server: %w", err)
+	}
+	return nil
+}
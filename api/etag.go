@@ -0,0 +1,30 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// ETag returns the entity tag of a note, which changes whenever the note is modified.
+func ETag(note models.Note) string {
+	return `"` + strconv.FormatInt(note.ModifiedAt.UnixNano(), 36) + `"`
+}
+
+// CheckIfMatch returns ErrPreconditionFailed unless ifMatch, the value of an
+// If-Match header, is empty, "*", or lists the note's ETag.
+// Weak tags (W/"...") are compared by their value.
+func CheckIfMatch(ifMatch string, note models.Note) error {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return nil
+	}
+	etag := ETag(note)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+func TestCheckIfMatch(t *testing.T) {
+	note := models.Note{ModifiedAt: time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC)}
+	etag := ETag(note)
+
+	tests := []struct {
+		ifMatch string
+		ok      bool
+	}{
+		{"", true},
+		{"*", true},
+		{etag, true},
+		{"W/" + etag, true},
+		{`"other", ` + etag, true},
+		{`"other"`, false},
+		{ETag(models.Note{ModifiedAt: note.ModifiedAt.Add(time.Nanosecond)}), false},
+	}
+
+	for _, tt := range tests {
+		err := CheckIfMatch(tt.ifMatch, note)
+		if tt.ok && err != nil {
+			t.Errorf("CheckIfMatch(%q) error = %v; want nil", tt.ifMatch, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrPreconditionFailed) {
+			t.Errorf("CheckIfMatch(%q) error = %v; want ErrPreconditionFailed", tt.ifMatch, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/markdown"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// maxBodySize limits the size of request bodies.
+const maxBodySize = 4 << 20
+
+// NewHandler returns an http.Handler serving the API over store.
+// If token is not empty, requests must carry it as "Authorization: Bearer <token>".
+func NewHandler(store Store, token string) http.Handler {
+	h := handler{store: store}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /notes", h.listNotes)
+	mux.HandleFunc("POST /notes", h.createNote)
+	mux.HandleFunc("GET /notes/{id}", h.getNote)
+	mux.HandleFunc("GET /notes/{id}/html", h.renderNote)
+	mux.HandleFunc("PUT /notes/{id}", h.updateNote)
+	mux.HandleFunc("DELETE /notes/{id}", h.deleteNote)
+	mux.HandleFunc("POST /notes/delete", h.deleteNotes)
+	mux.HandleFunc("GET /search", h.search)
+	mux.HandleFunc("GET /tags", h.tags)
+	mux.HandleFunc("GET /status", h.status)
+	return requireToken(token, mux)
+}
+
+type handler struct {
+	store Store
+}
+
+func (h handler) listNotes(w http.ResponseWriter, r *http.Request) {
+	notes, err := h.store.Notes(r.URL.Query().Get("notebook"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(notes))
+}
+
+func (h handler) createNote(w http.ResponseWriter, r *http.Request) {
+	var input NoteInput
+	if err := readJSON(w, r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	note, err := h.store.CreateNote(input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/notes/"+url.PathEscape(note.ID))
+	writeNote(w, http.StatusCreated, note)
+}
+
+func (h handler) getNote(w http.ResponseWriter, r *http.Request) {
+	note, err := h.store.Note(r.URL.Query().Get("notebook"), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeNote(w, http.StatusOK, note)
+}
+
+// renderNote writes a note's Markdown content as an HTML fragment. [[Links]]
+// to existing notes point at "#/notes/<id>", for the web UI to follow.
+func (h handler) renderNote(w http.ResponseWriter, r *http.Request) {
+	note, err := h.store.Note(r.URL.Query().Get("notebook"), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	opts := markdown.HTMLOptions{
+		NoteLinkURL: func(link markdown.NoteLink) (string, bool) {
+			notebook := link.Notebook
+			if notebook == "" {
+				notebook = note.Notebook
+			}
+			target, err := h.store.Note(notebook, link.Title)
+			if err != nil {
+				return "", false
+			}
+			return "#/notes/" + url.PathEscape(target.ID), true
+		},
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("ETag", ETag(note))
+	io.WriteString(w, markdown.RenderHTML(markdown.Parse(note.Content), opts))
+}
+
+func (h handler) updateNote(w http.ResponseWriter, r *http.Request) {
+	var input NoteInput
+	if err := readJSON(w, r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	note, err := h.store.UpdateNote(r.URL.Query().Get("notebook"), r.PathValue("id"), r.Header.Get("If-Match"), input)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeNote(w, http.StatusOK, note)
+}
+
+func (h handler) deleteNote(w http.ResponseWriter, r *http.Request) {
+	_, err := h.store.DeleteNote(r.URL.Query().Get("notebook"), r.PathValue("id"), r.Header.Get("If-Match"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h handler) deleteNotes(w http.ResponseWriter, r *http.Request) {
+	var deletions []Deletion
+	if err := readJSON(w, r, &deletions); err != nil {
+		writeError(w, err)
+		return
+	}
+	if _, err := h.store.DeleteNotes(deletions); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h handler) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, fmt.Errorf("%w: missing query parameter q", ErrInvalidRequest))
+		return
+	}
+	notes, err := h.store.Search(query, r.URL.Query().Get("notebook"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, nonNil(notes))
+}
+
+func (h handler) tags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.store.Tags(r.URL.Query().Get("notebook"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if tags == nil {
+		tags = []Tag{}
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+func (h handler) status(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Status{PID: os.Getpid()})
+}
+
+// requireToken rejects requests without the bearer token, unless token is empty.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cli-note"`)
+			writeError(w, ErrUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// readJSON decodes the request body into v, rejecting unknown fields.
+// The body must be sent as application/json, which browsers only allow
+// other sites to do after a CORS preflight that the API doesn't answer.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return fmt.Errorf("%w: the request body must be sent as application/json", ErrInvalidRequest)
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: error parsing request body: %v", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+func writeNote(w http.ResponseWriter, status int, note models.Note) {
+	w.Header().Set("ETag", ETag(note))
+	writeJSON(w, status, note)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorResponse is the body of error responses.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusOf(err), errorResponse{Error: err.Error()})
+}
+
+// statusOf returns the HTTP status code for an error from a Store.
+func statusOf(err error) int {
+	var ambiguous *db.AmbiguousPrefixError
+	switch {
+	case errors.Is(err, db.ErrNoteNotFound), errors.Is(err, db.ErrNotebookNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrInvalidRequest), errors.As(err, &ambiguous):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// nonNil returns notes, or an empty list if there are none, so that it
+// encodes as [] rather than null.
+func nonNil(notes []models.Note) []models.Note {
+	if notes == nil {
+		return []models.Note{}
+	}
+	return notes
+}
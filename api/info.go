@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	infoFile = "server.json"
+
+	// infoPermissions keeps the token in the server file private to the user.
+	infoPermissions = 0600
+)
+
+// Info is what clients need to reach a running server. It is written to the
+// server file while the server runs.
+type Info struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token,omitempty"`
+	PID   int    `json:"pid"`
+}
+
+// InfoPath returns the location of the server file in the notes directory.
+func InfoPath(notesDirectory string) string {
+	return filepath.Join(notesDirectory, infoFile)
+}
+
+// ReadInfo reads the server file at path. It returns an error wrapping
+// fs.ErrNotExist if no server has written one.
+func ReadInfo(path string) (Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Info{}, fmt.Errorf("error reading server file: %w", err)
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Info{}, fmt.Errorf("error parsing server file %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// WriteInfo writes the server file at path, readable only by the user.
+func WriteInfo(path string, info Info) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding server file: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), infoPermissions); err != nil {
+		return fmt.Errorf("error writing server file: %w", err)
+	}
+	return nil
+}
+
+// RemoveInfo removes the server file at path, if there is one.
+func RemoveInfo(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error removing server file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestInfoRoundTrip(t *testing.T) {
+	path := InfoPath(t.TempDir())
+
+	if _, err := ReadInfo(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ReadInfo() before WriteInfo error = %v; want fs.ErrNotExist", err)
+	}
+
+	want := Info{Addr: "127.0.0.1:8080", Token: "secret", PID: 42}
+	if err := WriteInfo(path, want); err != nil {
+		t.Fatalf("WriteInfo() error = %v", err)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := stat.Mode().Perm(); perm != infoPermissions {
+		t.Errorf("server file permissions = %o; want %o", perm, infoPermissions)
+	}
+
+	got, err := ReadInfo(path)
+	if err != nil {
+		t.Fatalf("ReadInfo() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("ReadInfo() = %+v; want %+v", got, want)
+	}
+
+	if err := RemoveInfo(path); err != nil {
+		t.Fatalf("RemoveInfo() error = %v", err)
+	}
+	if err := RemoveInfo(path); err != nil {
+		t.Errorf("RemoveInfo() of a missing file error = %v; want nil", err)
+	}
+}
@@ -0,0 +1,272 @@
+// Package store implements api.Store over the notes database, for the
+// server and for commands that run without one.
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/validator"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store is an api.Store over the notes database. Notes are validated,
+// stored and deleted the way the new, edit, rename, move and delete
+// commands do it.
+type Store struct {
+	db *bolt.DB
+	// notebook is the notebook used when a request doesn't name one.
+	notebook string
+}
+
+// New returns a Store over database whose default notebook is notebook.
+func New(database *bolt.DB, notebook string) *Store {
+	return &Store{db: database, notebook: notebook}
+}
+
+// notebookOr returns notebook, or the store's default notebook if it is empty.
+func (s *Store) notebookOr(notebook string) string {
+	if notebook == "" {
+		return s.notebook
+	}
+	return notebook
+}
+
+// Notes returns the notes in notebook, or every note if it is empty.
+func (s *Store) Notes(notebook string) ([]models.Note, error) {
+	var notes []models.Note
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		if notebook == "" {
+			notes, err = db.AllNotes(tx)
+		} else {
+			notes, err = db.NotesInNotebook(tx, notebook)
+		}
+		return err
+	})
+	return notes, err
+}
+
+// Note returns the note identified by handle.
+func (s *Store) Note(notebook, handle string) (models.Note, error) {
+	var note models.Note
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, s.notebookOr(notebook), handle)
+		return err
+	})
+	return note, err
+}
+
+// CreateNote creates a note. It must have a title, unique within its notebook.
+func (s *Store) CreateNote(input api.NoteInput) (models.Note, error) {
+	if input.Title == nil {
+		return models.Note{}, fmt.Errorf("%w: a title is required", api.ErrInvalidRequest)
+	}
+	notebook := s.notebook
+	if input.Notebook != nil {
+		notebook = s.notebookOr(*input.Notebook)
+	}
+
+	if err := validator.ValidateName("note", *input.Title); err != nil {
+		return models.Note{}, fmt.Errorf("%w: invalid note name: %w", api.ErrInvalidRequest, err)
+	}
+	now := time.Now()
+	note := models.Note{
+		ID:         uuid.New().String(),
+		Title:      *input.Title,
+		Notebook:   notebook,
+		CreatedAt:  now,
+		ModifiedAt: now,
+	}
+	applyInput(&note, input)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := checkTitleFree(tx, note, notebook, note.Title); err != nil {
+			return err
+		}
+		if err := db.PutNote(tx, note); err != nil {
+			return err
+		}
+		return db.PutNoteTitle(tx, note)
+	})
+	if err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+// UpdateNote changes the fields of a note set in input. A new title renames
+// the note and a new notebook moves it, updating links to it.
+func (s *Store) UpdateNote(notebook, handle, ifMatch string, input api.NoteInput) (models.Note, error) {
+	var note models.Note
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, s.notebookOr(notebook), handle)
+		if err != nil {
+			return err
+		}
+		if err := api.CheckIfMatch(ifMatch, note); err != nil {
+			return err
+		}
+
+		if input.Notebook != nil && db.NormalizeTitle(*input.Notebook) != db.NormalizeTitle(note.Notebook) {
+			if err := checkTitleFree(tx, note, *input.Notebook, note.Title); err != nil {
+				return err
+			}
+			if note, _, err = db.MoveNote(tx, note, *input.Notebook); err != nil {
+				return err
+			}
+		}
+		if input.Title != nil && *input.Title != note.Title {
+			if err := validator.ValidateName("note", *input.Title); err != nil {
+				return fmt.Errorf("%w: %w", api.ErrInvalidRequest, err)
+			}
+			if err := checkTitleFree(tx, note, note.Notebook, *input.Title); err != nil {
+				return err
+			}
+			if note, _, err = db.RenameNote(tx, note, *input.Title); err != nil {
+				return err
+			}
+		}
+
+		if input.Content != nil && *input.Content != note.Content {
+			note.EditCount++
+		}
+		applyInput(&note, input)
+		note.ModifiedAt = time.Now()
+		return db.PutNote(tx, note)
+	})
+	if err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+// DeleteNote deletes the note identified by handle and returns it.
+func (s *Store) DeleteNote(notebook, handle, ifMatch string) (models.Note, error) {
+	var note models.Note
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, s.notebookOr(notebook), handle)
+		if err != nil {
+			return err
+		}
+		if err := api.CheckIfMatch(ifMatch, note); err != nil {
+			return err
+		}
+		return db.RemoveNote(tx, note)
+	})
+	if err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+// DeleteNotes deletes the notes identified by deletions in one transaction,
+// so that either all of them are deleted or, on any error, none are.
+func (s *Store) DeleteNotes(deletions []api.Deletion) ([]models.Note, error) {
+	var notes []models.Note
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, deletion := range deletions {
+			if deletion.ID == "" {
+				return fmt.Errorf("%w: missing note ID", api.ErrInvalidRequest)
+			}
+			note, err := db.GetNote(tx, deletion.ID)
+			if err != nil {
+				return err
+			}
+			if err := api.CheckIfMatch(deletion.IfMatch, note); err != nil {
+				return fmt.Errorf("note %q: %w", note.Title, err)
+			}
+			if err := db.RemoveNote(tx, note); err != nil {
+				return err
+			}
+			notes = append(notes, note)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// Search returns the notes in notebook, or in every notebook if it is empty,
+// whose title or content contains query.
+func (s *Store) Search(query, notebook string) ([]models.Note, error) {
+	var matches []models.Note
+	err := s.db.View(func(tx *bolt.Tx) error {
+		notes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+		matches = db.MatchNotes(notes, query, notebook)
+		return nil
+	})
+	return matches, err
+}
+
+// Tags returns the tags of the notes in notebook, or in every notebook if
+// it is empty, sorted by name.
+func (s *Store) Tags(notebook string) ([]api.Tag, error) {
+	notes, err := s.Notes(notebook)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, note := range notes {
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]api.Tag, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, api.Tag{Name: name, Count: count})
+	}
+	slices.SortFunc(tags, func(a, b api.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tags, nil
+}
+
+// checkTitleFree returns an error wrapping api.ErrConflict if a note other
+// than note already has title in notebook.
+func checkTitleFree(tx *bolt.Tx, note models.Note, notebook, title string) error {
+	titles, err := db.NotebookTitles(tx, notebook)
+	if err != nil {
+		return err
+	}
+	if existing := titles.Get(db.TitleKey(title)); existing != nil && string(existing) != note.ID {
+		return fmt.Errorf("note %q already exists in notebook %q: %w", title, notebook, api.ErrConflict)
+	}
+	return nil
+}
+
+// applyInput copies the content, tags, pinned, archived and due fields set in input to note.
+func applyInput(note *models.Note, input api.NoteInput) {
+	if input.Content != nil {
+		note.Content = *input.Content
+	}
+	if input.Tags != nil {
+		note.Tags = *input.Tags
+	}
+	if input.Pinned != nil {
+		note.Pinned = *input.Pinned
+	}
+	if input.Archived != nil {
+		note.Archived = *input.Archived
+	}
+	if input.Due != nil {
+		due := *input.Due
+		note.Due = &due
+	}
+}
@@ -0,0 +1,284 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+)
+
+// startServer serves a Store over a test database and returns a client for it.
+func startServer(t *testing.T, token string) *api.Client {
+	t.Helper()
+	testDB, _ := testutil.SetupTestDB(t)
+	server := httptest.NewServer(api.NewHandler(New(testDB, db.DefaultNotebook), token))
+	t.Cleanup(server.Close)
+	return api.NewClient(api.Info{Addr: server.Listener.Addr().String(), Token: token, PID: os.Getpid()})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCreateAndGetNote(t *testing.T) {
+	client := startServer(t, "")
+
+	created, err := client.CreateNote(api.NoteInput{Title: ptr("Groceries"), Content: ptr("milk"), Tags: ptr([]string{"home"})})
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+	if created.Notebook != db.DefaultNotebook {
+		t.Errorf("CreateNote() notebook = %q; want %q", created.Notebook, db.DefaultNotebook)
+	}
+
+	for _, handle := range []string{"groceries", created.ID[:8]} {
+		note, err := client.Note("", handle)
+		if err != nil {
+			t.Fatalf("Note(%q) error = %v", handle, err)
+		}
+		if note.ID != created.ID || note.Content != "milk" {
+			t.Errorf("Note(%q) = %+v; want the created note", handle, note)
+		}
+	}
+
+	if _, err := client.CreateNote(api.NoteInput{Title: ptr("GROCERIES")}); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("CreateNote() of a duplicate title error = %v; want ErrConflict", err)
+	}
+	if _, err := client.CreateNote(api.NoteInput{Title: ptr("bad:title")}); !errors.Is(err, api.ErrInvalidRequest) {
+		t.Errorf("CreateNote() of an invalid title error = %v; want ErrInvalidRequest", err)
+	}
+	// Titles are escaped once in the request path
+	for _, title := range []string{"Weekly plan", "50% #done"} {
+		if _, err := client.CreateNote(api.NoteInput{Title: ptr(title)}); err != nil {
+			t.Fatalf("CreateNote(%q) error = %v", title, err)
+		}
+		if note, err := client.Note("", title); err != nil || note.Title != title {
+			t.Errorf("Note(%q) = %+v, %v; want the note", title, note, err)
+		}
+	}
+	if _, err := client.Note("", "missing"); !errors.Is(err, db.ErrNoteNotFound) {
+		t.Errorf("Note() of a missing note error = %v; want ErrNoteNotFound", err)
+	}
+}
+
+func TestUpdateNoteIfMatch(t *testing.T) {
+	client := startServer(t, "")
+
+	note, err := client.CreateNote(api.NoteInput{Title: ptr("Plan")})
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+	etag := api.ETag(note)
+
+	updated, err := client.UpdateNote("", note.ID, etag, api.NoteInput{Content: ptr("step one"), Pinned: ptr(true)})
+	if err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if updated.Content != "step one" || !updated.Pinned || updated.EditCount != 1 {
+		t.Errorf("UpdateNote() = %+v; want updated content, pinned, one edit", updated)
+	}
+	if !updated.ModifiedAt.After(note.ModifiedAt) {
+		t.Errorf("UpdateNote() didn't advance ModifiedAt")
+	}
+
+	// The first update changed the note, so the old ETag is stale
+	if _, err := client.UpdateNote("", note.ID, etag, api.NoteInput{Content: ptr("lost update")}); !errors.Is(err, api.ErrPreconditionFailed) {
+		t.Errorf("UpdateNote() with a stale ETag error = %v; want ErrPreconditionFailed", err)
+	}
+	if _, err := client.DeleteNote("", note.ID, etag); !errors.Is(err, api.ErrPreconditionFailed) {
+		t.Errorf("DeleteNote() with a stale ETag error = %v; want ErrPreconditionFailed", err)
+	}
+
+	renamed, err := client.UpdateNote("", note.ID, api.ETag(updated), api.NoteInput{Title: ptr("Roadmap")})
+	if err != nil {
+		t.Fatalf("UpdateNote() rename error = %v", err)
+	}
+	if renamed.Title != "Roadmap" || renamed.Content != "step one" {
+		t.Errorf("UpdateNote() rename = %+v; want title Roadmap and content kept", renamed)
+	}
+	if _, err := client.Note("", "Roadmap"); err != nil {
+		t.Errorf("Note() by new title error = %v", err)
+	}
+
+	if _, err := client.DeleteNote("", "Roadmap", api.ETag(renamed)); err != nil {
+		t.Fatalf("DeleteNote() error = %v", err)
+	}
+	if _, err := client.Note("", note.ID); !errors.Is(err, db.ErrNoteNotFound) {
+		t.Errorf("Note() after DeleteNote() error = %v; want ErrNoteNotFound", err)
+	}
+}
+
+func TestSearchAndTags(t *testing.T) {
+	client := startServer(t, "")
+
+	inputs := []api.NoteInput{
+		{Title: ptr("Budget"), Content: ptr("rent and food"), Tags: ptr([]string{"money", "home"})},
+		{Title: ptr("Groceries"), Content: ptr("Food for the week"), Tags: ptr([]string{"home"})},
+		{Title: ptr("Ideas")},
+	}
+	for _, input := range inputs {
+		if _, err := client.CreateNote(input); err != nil {
+			t.Fatalf("CreateNote() error = %v", err)
+		}
+	}
+
+	notes, err := client.Search("food", "")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(notes) != 2 {
+		t.Errorf("Search(\"food\") found %d notes; want 2", len(notes))
+	}
+
+	tags, err := client.Tags("")
+	if err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+	want := []api.Tag{{Name: "home", Count: 2}, {Name: "money", Count: 1}}
+	if len(tags) != len(want) || tags[0] != want[0] || tags[1] != want[1] {
+		t.Errorf("Tags() = %+v; want %+v", tags, want)
+	}
+
+	if _, err := client.Notes("missing"); !errors.Is(err, db.ErrNoteNotFound) {
+		t.Errorf("Notes() of a missing notebook error = %v; want a not found error", err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	client := startServer(t, "secret")
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping() with the token error = %v", err)
+	}
+
+	wrong := api.NewClient(api.Info{Addr: client.Addr(), Token: "guess"})
+	if _, err := wrong.Notes(""); !errors.Is(err, api.ErrUnauthorized) {
+		t.Errorf("Notes() with a wrong token error = %v; want ErrUnauthorized", err)
+	}
+
+	response, err := http.Get("http://" + client.Addr() + "/notes")
+	if err != nil {
+		t.Fatalf("GET /notes error = %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /notes without a token status = %d; want %d", response.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestETagHeader(t *testing.T) {
+	client := startServer(t, "")
+	note, err := client.CreateNote(api.NoteInput{Title: ptr("Journal")})
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+
+	response, err := http.Get("http://" + client.Addr() + "/notes/" + note.ID)
+	if err != nil {
+		t.Fatalf("GET /notes/{id} error = %v", err)
+	}
+	response.Body.Close()
+	if got := response.Header.Get("ETag"); got != api.ETag(note) {
+		t.Errorf("ETag header = %q; want %q", got, api.ETag(note))
+	}
+
+	request, _ := http.NewRequest(http.MethodPut, "http://"+client.Addr()+"/notes/"+note.ID, strings.NewReader(`{"content": "x"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("If-Match", `"stale"`)
+	response, err = http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("PUT /notes/{id} error = %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("PUT with a stale If-Match status = %d; want %d", response.StatusCode, http.StatusPreconditionFailed)
+	}
+}
+
+func TestBodyMustBeJSON(t *testing.T) {
+	client := startServer(t, "")
+
+	// A form or text/plain post, which any web page could send, is rejected
+	response, err := http.Post("http://"+client.Addr()+"/notes", "text/plain", strings.NewReader(`{"title": "Sneaky"}`))
+	if err != nil {
+		t.Fatalf("POST /notes error = %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST as text/plain status = %d; want %d", response.StatusCode, http.StatusBadRequest)
+	}
+	if _, err := client.Note("", "Sneaky"); !errors.Is(err, db.ErrNoteNotFound) {
+		t.Errorf("Note() after a text/plain POST error = %v; want ErrNoteNotFound", err)
+	}
+}
+
+func TestRenderNote(t *testing.T) {
+	client := startServer(t, "")
+	target, err := client.CreateNote(api.NoteInput{Title: ptr("Target")})
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+	source, err := client.CreateNote(api.NoteInput{Title: ptr("Source"), Content: ptr("# Hi\n\nSee [[Target]] and [[Nowhere]]")})
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+
+	response, err := http.Get("http://" + client.Addr() + "/notes/" + source.ID + "/html")
+	if err != nil {
+		t.Fatalf("GET /notes/{id}/html error = %v", err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+
+	for _, want := range []string{
+		"<h1>Hi</h1>",
+		`<a class="note-link" href="#/notes/` + target.ID + `">Target</a>`,
+		`<span class="note-link dangling">Nowhere</span>`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("rendered note = %q; want it to contain %q", body, want)
+		}
+	}
+}
+
+func TestDeleteNotesAllOrNone(t *testing.T) {
+	client := startServer(t, "")
+
+	var deletions []api.Deletion
+	for _, title := range []string{"Draft", "Sketch"} {
+		note, err := client.CreateNote(api.NoteInput{Title: ptr(title)})
+		if err != nil {
+			t.Fatalf("CreateNote() error = %v", err)
+		}
+		deletions = append(deletions, api.Deletion{ID: note.ID, IfMatch: api.ETag(note)})
+	}
+
+	// The second note changed since it was read, so neither is deleted
+	if _, err := client.UpdateNote("", "Sketch", "", api.NoteInput{Content: ptr("edited")}); err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if _, err := client.DeleteNotes(deletions); !errors.Is(err, api.ErrPreconditionFailed) {
+		t.Errorf("DeleteNotes() with a stale ETag error = %v; want ErrPreconditionFailed", err)
+	}
+	if _, err := client.Note("", "Draft"); err != nil {
+		t.Errorf("Note() after a failed DeleteNotes() error = %v; want the note kept", err)
+	}
+
+	deletions[1].IfMatch = ""
+	if _, err := client.DeleteNotes(deletions); err != nil {
+		t.Fatalf("DeleteNotes() error = %v", err)
+	}
+	notes, err := client.Notes("")
+	if err != nil {
+		t.Fatalf("Notes() error = %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("Notes() after DeleteNotes() = %d notes; want none", len(notes))
+	}
+}
@@ -0,0 +1,336 @@
+package agenda
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	dueCmdFull  = "due <title|id-prefix> [when]"
+	dueCmdShort = "Set or clear a note's due date"
+	dueCmdDesc  = `Set the date a note is due, shown by 'cli-note agenda' and 'cli-note remind'.
+
+When can be an offset ("+3d", "+2h"), a day ("tomorrow", "friday",
+"2026-11-01"), a time today ("5pm") or a day and a time ("tomorrow 9am").
+A day without a time means the start of that day.
+
+Examples:
+  cli-note due "Tax Return" 2026-11-01
+  cli-note due "Standup" "tomorrow 9am"
+  cli-note due "Standup" --clear`
+
+	agendaCmdFull  = "agenda"
+	agendaCmdShort = "Show overdue, today's and upcoming notes"
+	agendaCmdDesc  = `Show the notes that are overdue, due today, and due in the next few days,
+from every notebook. Archived notes are skipped.`
+
+	remindCmdFull  = "remind"
+	remindCmdShort = "Print notes that are due"
+	remindCmdDesc  = `Print the notes that are due now or overdue, from every notebook.
+
+With --check, remind exits with status 1 if anything is due, so it can be
+used from a shell prompt or a cron job:
+
+  cli-note remind --check --within 1h || notify-send "Notes are due"`
+
+	clearFlag  = "clear"
+	daysFlag   = "days"
+	checkFlag  = "check"
+	withinFlag = "within"
+
+	dueDateFormat     = "Mon Jan 02"
+	dueTimeFormat     = "Mon Jan 02 15:04"
+	dueYearDateFormat = "Mon Jan 02 2006"
+	dueYearTimeFormat = "Mon Jan 02 2006 15:04"
+)
+
+// errNotesDue is returned by 'remind --check' when notes are due.
+// Its only purpose is to make the command exit with a non-zero status.
+var errNotesDue = errors.New("notes are due")
+
+// init registers the due, agenda and remind commands with the root command.
+func init() {
+	root.RootCmd.AddCommand(DueCommand())
+	root.RootCmd.AddCommand(AgendaCommand())
+	root.RootCmd.AddCommand(RemindCommand())
+}
+
+// DueCommand creates and returns a cobra.Command for setting a note's due date.
+func DueCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   dueCmdFull,
+		Short: dueCmdShort,
+		Long:  dueCmdDesc,
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clear, _ := cmd.Flags().GetBool(clearFlag)
+			if clear == (len(args) == 2) {
+				return fmt.Errorf("please give either a due date or --%s", clearFlag)
+			}
+
+			var due *time.Time
+			if !clear {
+				when, err := dates.ParseWhen(args[1], time.Now())
+				if err != nil {
+					return err
+				}
+				due = &when
+			}
+
+			note, err := setDue(args[0], root.ActiveNotebook, due, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			if note.Due == nil {
+				fmt.Println(output.Sprintf(output.Success, "Note %q no longer has a due date", note.Title))
+			} else {
+				fmt.Println(output.Sprintf(output.Success, "Note %q is due %s", note.Title, formatDue(*note.Due)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(clearFlag, false, "Remove the note's due date")
+
+	return cmd
+}
+
+// AgendaCommand creates and returns a cobra.Command for showing the agenda.
+func AgendaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   agendaCmdFull,
+		Short: agendaCmdShort,
+		Long:  agendaCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			days, _ := cmd.Flags().GetInt(daysFlag)
+			if days < 0 {
+				return fmt.Errorf("--%s must not be negative", daysFlag)
+			}
+
+			notes, err := notesWithDueDates(root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			printAgenda(buildAgenda(notes, time.Now(), days), days)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(daysFlag, 7, "Number of days ahead to show upcoming notes for")
+
+	return cmd
+}
+
+// RemindCommand creates and returns a cobra.Command for printing due notes.
+func RemindCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   remindCmdFull,
+		Short: remindCmdShort,
+		Long:  remindCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			check, _ := cmd.Flags().GetBool(checkFlag)
+
+			var within time.Duration
+			if value, _ := cmd.Flags().GetString(withinFlag); value != "" {
+				var err error
+				if within, err = dates.ParseDuration(value); err != nil {
+					return fmt.Errorf("invalid --%s value: %w", withinFlag, err)
+				}
+			}
+
+			notes, err := notesWithDueDates(root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			due := dueBy(notes, time.Now().Add(within))
+			if len(due) == 0 {
+				if !check {
+					fmt.Println("Nothing is due.")
+				}
+				return nil
+			}
+
+			fmt.Println(output.Paint(output.Header, "Due:"))
+			printDueNotes(due)
+			if check {
+				// Report the due notes through the exit status only
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+				return errNotesDue
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(checkFlag, false, "Exit with status 1 if any notes are due")
+	cmd.Flags().String(withinFlag, "", "Also include notes due within this duration (e.g. 30m, 1h, 1d)")
+
+	return cmd
+}
+
+// setDue sets the due date of the note identified by handle in notebook,
+// or clears it if due is nil. It doesn't change the note's modification time.
+func setDue(handle, notebook string, due *time.Time, database *bolt.DB) (models.Note, error) {
+	var updated models.Note
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		note.Due = due
+		if err := db.PutNote(tx, note); err != nil {
+			return err
+		}
+		updated = note
+		return nil
+	})
+
+	return updated, err
+}
+
+// notesWithDueDates returns every note with a due date that isn't archived,
+// sorted by due date.
+func notesWithDueDates(database *bolt.DB) ([]models.Note, error) {
+	var due []models.Note
+
+	err := database.View(func(tx *bolt.Tx) error {
+		notes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+		for _, note := range notes {
+			if note.Due != nil && !note.Archived {
+				due = append(due, note)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading notes: %w", err)
+	}
+
+	sort.SliceStable(due, func(a, b int) bool {
+		return due[a].Due.Before(*due[b].Due)
+	})
+	return due, nil
+}
+
+// agenda holds notes with due dates, split by when they are due.
+type agenda struct {
+	overdue  []models.Note
+	today    []models.Note
+	upcoming []models.Note
+}
+
+// buildAgenda sorts notes, which must all have due dates, into an agenda.
+// Notes due before today are overdue, notes due any time today are due today,
+// and notes due in the given number of days after today are upcoming.
+func buildAgenda(notes []models.Note, now time.Time, days int) agenda {
+	today := dates.StartOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	horizon := tomorrow.AddDate(0, 0, days)
+
+	var result agenda
+	for _, note := range notes {
+		due := note.Due.In(now.Location())
+		switch {
+		case due.Before(today):
+			result.overdue = append(result.overdue, note)
+		case due.Before(tomorrow):
+			result.today = append(result.today, note)
+		case due.Before(horizon):
+			result.upcoming = append(result.upcoming, note)
+		}
+	}
+	return result
+}
+
+// dueBy returns the notes due at or before cutoff.
+func dueBy(notes []models.Note, cutoff time.Time) []models.Note {
+	var due []models.Note
+	for _, note := range notes {
+		if !note.Due.After(cutoff) {
+			due = append(due, note)
+		}
+	}
+	return due
+}
+
+// printAgenda prints the sections of an agenda; empty sections are left out.
+func printAgenda(a agenda, days int) {
+	if len(a.overdue)+len(a.today)+len(a.upcoming) == 0 {
+		fmt.Println("Nothing on the agenda.")
+		return
+	}
+
+	sections := []struct {
+		name  string
+		notes []models.Note
+	}{
+		{"Overdue", a.overdue},
+		{"Today", a.today},
+		{fmt.Sprintf("Next %d days", days), a.upcoming},
+	}
+
+	printed := false
+	for _, section := range sections {
+		if len(section.notes) == 0 {
+			continue
+		}
+		if printed {
+			fmt.Println()
+		}
+		fmt.Println(output.Sprintf(output.Header, "%s:", section.name))
+		printDueNotes(section.notes)
+		printed = true
+	}
+}
+
+// printDueNotes prints one line per note with its due date, title and notebook.
+// The due dates of overdue notes are highlighted.
+func printDueNotes(notes []models.Note) {
+	now := time.Now()
+	for _, note := range notes {
+		due := fmt.Sprintf("%-21s", formatDue(*note.Due))
+		if note.Due.Before(now) {
+			due = output.Paint(output.Overdue, due)
+		}
+		fmt.Printf("  %s  %s (%s)\n", due, note.Title, note.Notebook)
+	}
+}
+
+// formatDue formats a due date in the local time zone, leaving out the
+// time for dates due at the start of a day, and the year for dates this year.
+func formatDue(due time.Time) string {
+	due = due.Local()
+	dateOnly := due.Equal(dates.StartOfDay(due))
+
+	switch {
+	case due.Year() == time.Now().Year() && dateOnly:
+		return due.Format(dueDateFormat)
+	case due.Year() == time.Now().Year():
+		return due.Format(dueTimeFormat)
+	case dateOnly:
+		return due.Format(dueYearDateFormat)
+	default:
+		return due.Format(dueYearTimeFormat)
+	}
+}
@@ -0,0 +1,65 @@
+package agenda
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+func dueNote(title string, due time.Time) models.Note {
+	return models.Note{Title: title, Due: &due}
+}
+
+func titles(notes []models.Note) []string {
+	var result []string
+	for _, note := range notes {
+		result = append(result, note.Title)
+	}
+	return result
+}
+
+func TestBuildAgenda(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 15, 0, 0, 0, time.UTC)
+	notes := []models.Note{
+		dueNote("last week", now.AddDate(0, 0, -7)),
+		dueNote("this morning", now.Add(-6*time.Hour)),
+		dueNote("tonight", now.Add(6*time.Hour)),
+		dueNote("in three days", now.AddDate(0, 0, 3)),
+		dueNote("next month", now.AddDate(0, 1, 0)),
+	}
+
+	result := buildAgenda(notes, now, 7)
+
+	check := func(section string, got []models.Note, want ...string) {
+		if len(got) != len(want) {
+			t.Errorf("%s = %v; want %v", section, titles(got), want)
+			return
+		}
+		for i := range want {
+			if got[i].Title != want[i] {
+				t.Errorf("%s = %v; want %v", section, titles(got), want)
+				return
+			}
+		}
+	}
+	check("Overdue", result.overdue, "last week")
+	check("Today", result.today, "this morning", "tonight")
+	check("Upcoming", result.upcoming, "in three days")
+}
+
+func TestDueBy(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 15, 0, 0, 0, time.UTC)
+	notes := []models.Note{
+		dueNote("past", now.Add(-time.Minute)),
+		dueNote("soon", now.Add(30*time.Minute)),
+		dueNote("later", now.Add(3*time.Hour)),
+	}
+
+	if got := dueBy(notes, now); len(got) != 1 {
+		t.Errorf("dueBy(now) = %v; want [past]", titles(got))
+	}
+	if got := dueBy(notes, now.Add(time.Hour)); len(got) != 2 {
+		t.Errorf("dueBy(now+1h) = %v; want [past soon]", titles(got))
+	}
+}
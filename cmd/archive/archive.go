@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"fmt"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	archiveCmdFull  = "archive <title|id-prefix>"
+	archiveCmdShort = "Archive a note"
+	archiveCmdDesc  = `Archive a note to hide it from list and search without deleting it.
+
+Use 'cli-note list --archived' to see archived notes.`
+
+	unarchiveCmdFull  = "unarchive <title|id-prefix>"
+	unarchiveCmdShort = "Unarchive a note"
+	unarchiveCmdDesc  = `Unarchive a note so it shows up in list and search again.`
+)
+
+// init registers the archive and unarchive commands with the root command.
+func init() {
+	root.RootCmd.AddCommand(ArchiveCommand())
+	root.RootCmd.AddCommand(UnarchiveCommand())
+}
+
+// ArchiveCommand creates and returns a cobra.Command for archiving notes.
+func ArchiveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   archiveCmdFull,
+		Short: archiveCmdShort,
+		Long:  archiveCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			note, err := setArchived(args[0], root.ActiveNotebook, true, root.NotesDB)
+			if err != nil {
+				return err
+			}
+			fmt.Println(output.Sprintf(output.Success, "Note %q archived", note.Title))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// UnarchiveCommand creates and returns a cobra.Command for unarchiving notes.
+func UnarchiveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   unarchiveCmdFull,
+		Short: unarchiveCmdShort,
+		Long:  unarchiveCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			note, err := setArchived(args[0], root.ActiveNotebook, false, root.NotesDB)
+			if err != nil {
+				return err
+			}
+			fmt.Println(output.Sprintf(output.Success, "Note %q unarchived", note.Title))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// setArchived archives or unarchives the note identified by handle in notebook.
+// Archiving doesn't change the note's modification time.
+func setArchived(handle, notebook string, archived bool, database *bolt.DB) (models.Note, error) {
+	var updated models.Note
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		note.Archived = archived
+		if err := db.PutNote(tx, note); err != nil {
+			return err
+		}
+		updated = note
+		return nil
+	})
+
+	return updated, err
+}
@@ -0,0 +1,269 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	calendarCmdFull  = "calendar"
+	calendarCmdShort = "Show a calendar of note activity"
+	calendarCmdDesc  = `Show a month calendar marking the days notes were created or modified,
+and the days notes are due.
+
+  *  a note was created
+  +  a note was modified
+  !  a note is due
+
+Use --day to list the notes created, modified or due on a day.
+
+Examples:
+  cli-note calendar
+  cli-note calendar --month 2026-09
+  cli-note calendar --day yesterday`
+
+	monthFlag        = "month"
+	dayFlag          = "day"
+	allNotebooksFlag = "all-notebooks"
+
+	createdMarker  = '*'
+	modifiedMarker = '+'
+	dueMarker      = '!'
+
+	// cellWidth is the width of a day in the grid: two digits, two markers and a space.
+	cellWidth = 5
+)
+
+// init registers the calendar command with the root command.
+func init() {
+	calendarCommand := CalendarCommand()
+	root.RootCmd.AddCommand(calendarCommand)
+}
+
+// CalendarCommand creates and returns a cobra.Command for showing the activity calendar.
+func CalendarCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   calendarCmdFull,
+		Short: calendarCmdShort,
+		Long:  calendarCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notebook := root.ActiveNotebook
+			if allNotebooks, _ := cmd.Flags().GetBool(allNotebooksFlag); allNotebooks {
+				notebook = ""
+			}
+
+			notes, err := notebookNotes(notebook, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if cmd.Flags().Changed(dayFlag) {
+				input, _ := cmd.Flags().GetString(dayFlag)
+				day, err := dates.ParseDay(input, now)
+				if err != nil {
+					return err
+				}
+				printDay(day, notesOnDay(notes, day))
+				return nil
+			}
+
+			input, _ := cmd.Flags().GetString(monthFlag)
+			month, err := dates.ParseMonth(input, now)
+			if err != nil {
+				return err
+			}
+			fmt.Print(renderMonth(month, monthActivity(notes, month)))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(monthFlag, "", "Month to show, as YYYY-MM (defaults to the current month)")
+	cmd.Flags().String(dayFlag, "", "List the notes touched on this day (today, yesterday or YYYY-MM-DD)")
+	cmd.Flags().Bool(allNotebooksFlag, false, "Include notes from every notebook")
+
+	return cmd
+}
+
+// notebookNotes returns the notes in notebook, or every note if notebook is empty.
+func notebookNotes(notebook string, database *bolt.DB) ([]models.Note, error) {
+	var notes []models.Note
+
+	err := database.View(func(tx *bolt.Tx) error {
+		allNotes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+		for _, note := range allNotes {
+			if notebook == "" || db.NormalizeTitle(note.Notebook) == db.NormalizeTitle(notebook) {
+				notes = append(notes, note)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading notes: %w", err)
+	}
+	return notes, nil
+}
+
+// wasModified reports whether a note was changed after it was created.
+// New notes get their creation and modification times from separate calls
+// to time.Now, so they are allowed to differ slightly.
+func wasModified(note models.Note) bool {
+	return note.ModifiedAt.Sub(note.CreatedAt) > time.Second
+}
+
+// dayActivity records what happened to notes on a day.
+type dayActivity struct {
+	created  bool
+	modified bool
+	due      bool
+}
+
+// monthActivity returns the activity of each day of month that had any, keyed by day of the month.
+// Times are compared in month's location.
+func monthActivity(notes []models.Note, month time.Time) map[int]dayActivity {
+	activity := make(map[int]dayActivity)
+	inMonth := func(t time.Time) (int, bool) {
+		t = t.In(month.Location())
+		return t.Day(), t.Year() == month.Year() && t.Month() == month.Month()
+	}
+
+	for _, note := range notes {
+		if day, ok := inMonth(note.CreatedAt); ok {
+			a := activity[day]
+			a.created = true
+			activity[day] = a
+		}
+		if day, ok := inMonth(note.ModifiedAt); ok && wasModified(note) {
+			a := activity[day]
+			a.modified = true
+			activity[day] = a
+		}
+		if note.Due != nil {
+			if day, ok := inMonth(*note.Due); ok {
+				a := activity[day]
+				a.due = true
+				activity[day] = a
+			}
+		}
+	}
+	return activity
+}
+
+// renderMonth renders a month grid, with weeks starting on Monday.
+// Each day is followed by a marker for its note activity and one for due notes.
+func renderMonth(month time.Time, activity map[int]dayActivity) string {
+	var b strings.Builder
+
+	weekdays := []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+	gridWidth := len(weekdays) * cellWidth
+
+	title := month.Format("January 2006")
+	fmt.Fprintf(&b, "%*s\n", (gridWidth+len(title))/2, title)
+	for _, weekday := range weekdays {
+		fmt.Fprintf(&b, "%-*s", cellWidth, weekday)
+	}
+	b.WriteString("\n")
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	column := (int(first.Weekday()) + 6) % 7
+	b.WriteString(strings.Repeat(" ", column*cellWidth))
+
+	for day := 1; day <= daysInMonth; day++ {
+		a := activity[day]
+		activityMarker, dueMarkerText := ' ', ' '
+		switch {
+		case a.created:
+			activityMarker = createdMarker
+		case a.modified:
+			activityMarker = modifiedMarker
+		}
+		if a.due {
+			dueMarkerText = dueMarker
+		}
+		fmt.Fprintf(&b, "%2d%c%c ", day, activityMarker, dueMarkerText)
+
+		column++
+		if column == len(weekdays) && day < daysInMonth {
+			b.WriteString("\n")
+			column = 0
+		}
+	}
+	b.WriteString("\n")
+
+	// Cells are padded on the right; drop the padding at the end of each line
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// touchedNote is a note together with what happened to it on a day.
+type touchedNote struct {
+	note     models.Note
+	activity dayActivity
+}
+
+// notesOnDay returns the notes created, modified or due on day, sorted by title.
+func notesOnDay(notes []models.Note, day time.Time) []touchedNote {
+	start := dates.StartOfDay(day)
+	end := start.AddDate(0, 0, 1)
+	onDay := func(t time.Time) bool {
+		return !t.Before(start) && t.Before(end)
+	}
+
+	var touched []touchedNote
+	for _, note := range notes {
+		a := dayActivity{
+			created:  onDay(note.CreatedAt),
+			modified: onDay(note.ModifiedAt) && wasModified(note),
+			due:      note.Due != nil && onDay(*note.Due),
+		}
+		if a.created || a.modified || a.due {
+			touched = append(touched, touchedNote{note: note, activity: a})
+		}
+	}
+
+	sort.Slice(touched, func(a, b int) bool {
+		return db.NormalizeTitle(touched[a].note.Title) < db.NormalizeTitle(touched[b].note.Title)
+	})
+	return touched
+}
+
+// printDay prints the notes touched on a day and what happened to them.
+func printDay(day time.Time, touched []touchedNote) {
+	if len(touched) == 0 {
+		fmt.Printf("No notes were touched on %s\n", day.Format(dates.DayFormat))
+		return
+	}
+
+	fmt.Printf("Notes on %s:\n", day.Format("Monday, January 2, 2006"))
+	for _, entry := range touched {
+		var what []string
+		if entry.activity.created {
+			what = append(what, "created")
+		}
+		if entry.activity.modified {
+			what = append(what, "modified")
+		}
+		if entry.activity.due {
+			what = append(what, "due")
+		}
+		fmt.Printf("  %s (%s): %s\n", entry.note.Title, entry.note.Notebook, strings.Join(what, ", "))
+	}
+}
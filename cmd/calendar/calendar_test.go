@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+func TestRenderMonth(t *testing.T) {
+	month := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2026, time.October, 5, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2026, time.October, 31, 0, 0, 0, 0, time.UTC)
+	notes := []models.Note{
+		{Title: "a", CreatedAt: created, ModifiedAt: created.AddDate(0, 0, 14), Due: &due},
+		{Title: "b", CreatedAt: created.AddDate(0, -1, 0), ModifiedAt: created.AddDate(0, -1, 0)},
+	}
+
+	want := "           October 2026\n" +
+		"Mo   Tu   We   Th   Fr   Sa   Su\n" +
+		"                1    2    3    4\n" +
+		" 5*   6    7    8    9   10   11\n" +
+		"12   13   14   15   16   17   18\n" +
+		"19+  20   21   22   23   24   25\n" +
+		"26   27   28   29   30   31 !\n"
+
+	got := renderMonth(month, monthActivity(notes, month))
+	if got != want {
+		t.Errorf("renderMonth() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestNotesOnDay(t *testing.T) {
+	day := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	notes := []models.Note{
+		{Title: "created", CreatedAt: day.Add(time.Hour), ModifiedAt: day.Add(time.Hour)},
+		{Title: "modified", CreatedAt: day.AddDate(0, 0, -3), ModifiedAt: day.Add(23 * time.Hour)},
+		{Title: "untouched", CreatedAt: day.AddDate(0, 0, -3), ModifiedAt: day.AddDate(0, 0, 1)},
+	}
+
+	touched := notesOnDay(notes, day)
+	if len(touched) != 2 {
+		t.Fatalf("Got %d notes; want 2", len(touched))
+	}
+	if !touched[0].activity.created || touched[0].activity.modified {
+		t.Errorf("First note activity = %+v; want created only", touched[0].activity)
+	}
+	if touched[1].activity.created || !touched[1].activity.modified {
+		t.Errorf("Second note activity = %+v; want modified only", touched[1].activity)
+	}
+}
@@ -1,26 +1,47 @@
 package delete
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 
+	"github.com/rhysmah/CLI-Note-App/api"
 	"github.com/rhysmah/CLI-Note-App/cmd/root"
 	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
 	"github.com/spf13/cobra"
 
 	bolt "go.etcd.io/bbolt"
 )
 
 const (
-	deleteCmdFull  = "delete"
-	deleteCmdShort = "Delete a note"
-	deleteCmdDesc  = `Delete an existing note from your notes database.
+	deleteCmdFull  = "delete [title|id-prefix...]"
+	deleteCmdShort = "Delete one or more notes"
+	deleteCmdDesc  = `Delete existing notes from your notes database.
 
 Usage:
-  notes delete <note-title>
+  notes delete <note-title> [note-title...]
+  notes delete <id-prefix>
+  notes delete --tag <tag> --older-than <duration> --title-glob <pattern>
 
-The note-title is required and must match the title of an existing note.
-This action cannot be undone.`
+Notes can be identified by their title or by a unique prefix of their ID
+(see 'cli-note list --show-id'), or selected with filters. Titles and filters
+only select notes in the current notebook. When several filters are given,
+a note must match all of them.
+
+The notes to be deleted are listed and you are asked to confirm, unless
+--yes is given. Use --dry-run to only see what would be deleted.
+All notes are deleted in a single transaction. This action cannot be undone.`
+
+	tagFlag       = "tag"
+	olderThanFlag = "older-than"
+	titleGlobFlag = "title-glob"
+	yesFlag       = "yes"
+	dryRunFlag    = "dry-run"
 )
 
 // init registers the delete command with the root command.
@@ -30,79 +51,157 @@ func init() {
 }
 
 // DeleteCommand creates and returns a cobra.Command for deleting notes.
-// The command requires exactly one argument: the note title to delete.
+// Notes are selected by title or ID prefix arguments, by filter flags, or both.
 func DeleteCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   deleteCmdFull,
 		Short: deleteCmdShort,
 		Long:  deleteCmdDesc,
-		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := deleteNote(args[0], root.NotesDB); err != nil {
+			filter, err := filterFromFlags(cmd, root.ActiveNotebook)
+			if err != nil {
 				return err
 			}
-			return nil
+			if len(args) == 0 && filter.isEmpty() {
+				return errors.New("please specify the notes to delete by title, ID prefix or filter")
+			}
+
+			yes, _ := cmd.Flags().GetBool(yesFlag)
+			dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+			opts := deleteOptions{yes: yes, dryRun: dryRun}
+
+			if root.Server != nil {
+				return deleteThroughServer(args, filter, opts, root.Server, cmd.InOrStdin(), cmd.OutOrStdout())
+			}
+			return deleteNotes(args, filter, opts, root.NotesDB, cmd.InOrStdin(), cmd.OutOrStdout())
 		},
 	}
+
+	cmd.Flags().StringSlice(tagFlag, nil, "Delete notes with this tag (repeatable; all tags must match)")
+	cmd.Flags().String(olderThanFlag, "", "Delete notes not modified within this duration (e.g. 12h, 30d, 2w, 6mo, 1y)")
+	cmd.Flags().String(titleGlobFlag, "", "Delete notes whose title matches this glob pattern (e.g. 'draft*')")
+	cmd.Flags().BoolP(yesFlag, "y", false, "Delete without asking for confirmation")
+	cmd.Flags().Bool(dryRunFlag, false, "Show which notes would be deleted without deleting them")
+	root.UseServer(cmd)
+
 	return cmd
 }
 
-// deleteNote removes a note from the database using its title.
-// It deletes both the note content and its title mapping.
-// Returns an error if the note doesn't exist or if deletion fails.
-func deleteNote(title string, database *bolt.DB) error {
-	return database.Update(func(tx *bolt.Tx) error {
-		if err := deleteNoteContent(title, tx); err != nil {
-			return fmt.Errorf("failed to delete note content: %w", err)
+// deleteOptions controls how deleteNotes asks for and applies deletions.
+type deleteOptions struct {
+	yes    bool
+	dryRun bool
+}
+
+// deleteNotes selects the notes identified by handles and filter, lists them
+// and, once confirmed, deletes them. The prompt is shown outside of any
+// transaction, so other commands can use the database meanwhile. The notes
+// are then selected again and deleted in one transaction: either every
+// selected note is deleted or, if the selection changed, none are.
+func deleteNotes(handles []string, filter deleteFilter, opts deleteOptions, database *bolt.DB, in io.Reader, out io.Writer) error {
+	var notes []models.Note
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		notes, err = selectNotes(tx, handles, filter)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !confirmDeletion(notes, opts, in, out) {
+		return nil
+	}
+
+	err = database.Update(func(tx *bolt.Tx) error {
+		current, err := selectNotes(tx, handles, filter)
+		if err != nil {
+			return err
+		}
+		if !sameNotes(notes, current) {
+			return errors.New("the selected notes changed before they were deleted; no notes were deleted")
 		}
-		if err := deleteNoteTitle(title, tx); err != nil {
-			return fmt.Errorf("failed to delete note title mapping: %w", err)
+
+		for _, note := range notes {
+			if err := db.RemoveNote(tx, note); err != nil {
+				return err
+			}
 		}
-		fmt.Printf("Successfully deleted note %q from database\n", title)
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, output.Sprintf(output.Success, "Successfully deleted %s from database", output.Pluralize(len(notes), "note")))
+	return nil
 }
 
-// deleteNoteContent removes the note content from the NotesBucket using the note's ID.
-// The ID is retrieved from the title mapping before deletion.
-func deleteNoteContent(title string, tx *bolt.Tx) error {
-	bucket := tx.Bucket([]byte(db.NotesBucket))
-	if bucket == nil {
-		return errors.New("NotesBucket does not exist")
-	}
-	noteID, err := extractNoteID(title, tx)
+// sameNotes reports whether two selections, sorted by title, hold the same
+// notes and none of them changed in between.
+func sameNotes(a, b []models.Note) bool {
+	return slices.EqualFunc(a, b, func(x, y models.Note) bool {
+		return x.ID == y.ID && api.ETag(x) == api.ETag(y)
+	})
+}
+
+// deleteThroughServer selects, lists and deletes notes like deleteNotes, but
+// through the server that owns the database. The notes are deleted in one
+// request, only if none has changed since it was listed.
+func deleteThroughServer(handles []string, filter deleteFilter, opts deleteOptions, store api.Store, in io.Reader, out io.Writer) error {
+	notes, err := selectStoreNotes(store, handles, filter)
 	if err != nil {
-		return fmt.Errorf("error finding note title %q: %w", title, err)
+		return err
 	}
-	if err := bucket.Delete([]byte(noteID)); err != nil {
-		return fmt.Errorf("error deleting note %q: %w", title, err)
+	if !confirmDeletion(notes, opts, in, out) {
+		return nil
 	}
-	return nil
-}
 
-// deleteNoteTitle removes the title-to-ID mapping from the NotesTitleBucket.
-func deleteNoteTitle(title string, tx *bolt.Tx) error {
-	titlesBucket := tx.Bucket([]byte(db.NotesTitleBucket))
-	if titlesBucket == nil {
-		return errors.New("NotesTitleBucket does not exist")
+	deletions := make([]api.Deletion, 0, len(notes))
+	for _, note := range notes {
+		deletions = append(deletions, api.Deletion{ID: note.ID, IfMatch: api.ETag(note)})
 	}
-	if err := titlesBucket.Delete([]byte(title)); err != nil {
-		return fmt.Errorf("error removing title mapping for %q: %w", title, err)
+	if _, err := store.DeleteNotes(deletions); err != nil {
+		return fmt.Errorf("error deleting notes, none were deleted: %w", err)
 	}
+	fmt.Fprintln(out, output.Sprintf(output.Success, "Successfully deleted %s from database", output.Pluralize(len(notes), "note")))
 	return nil
 }
 
-// extractNoteID retrieves the note's ID from the title mapping bucket.
-// Returns the note ID as a string if found, or an error if the title
-// doesn't exist or the bucket is missing.
-func extractNoteID(noteTitle string, tx *bolt.Tx) (string, error) {
-	bucket := tx.Bucket([]byte(db.NotesTitleBucket))
-	if bucket == nil {
-		return "", errors.New("NotesTitleBucket does not exist")
+// confirmDeletion lists the notes to be deleted and reports whether to go
+// ahead: there are notes, it isn't a dry run, and the user confirmed or
+// passed --yes.
+func confirmDeletion(notes []models.Note, opts deleteOptions, in io.Reader, out io.Writer) bool {
+	if len(notes) == 0 {
+		fmt.Fprintln(out, "No notes match the given filters")
+		return false
 	}
-	noteID := bucket.Get([]byte(noteTitle))
-	if noteID == nil {
-		return "", fmt.Errorf("error finding note %q", noteTitle)
+
+	fmt.Fprintf(out, "The following %s will be deleted:\n", output.Pluralize(len(notes), "note"))
+	for _, note := range notes {
+		fmt.Fprintf(out, "  %s\n", note.Title)
+	}
+
+	if opts.dryRun {
+		fmt.Fprintln(out, output.Paint(output.Warning, "Dry run: no notes were deleted"))
+		return false
 	}
-	return string(noteID), nil
+	if !opts.yes && !confirm(in, out, fmt.Sprintf("Delete %s?", output.Pluralize(len(notes), "note"))) {
+		fmt.Fprintln(out, output.Paint(output.Warning, "Aborted: no notes were deleted"))
+		return false
+	}
+	return true
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes.
+// Anything other than "y" or "yes", including no input at all, counts as no.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(out)
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
@@ -1,8 +1,12 @@
 package delete
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rhysmah/CLI-Note-App/cmd/new"
 	"github.com/rhysmah/CLI-Note-App/db"
@@ -25,7 +29,7 @@ func TestDeleteExistingNote(t *testing.T) {
 	testutil.TestNoteContentSaved(t, note, testDb)
 	testutil.TestNoteTitleSaved(t, note, testDb)
 
-	err = deleteNote(note.Title, testDb)
+	err = deleteNotes([]string{note.Title}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{yes: true}, testDb, strings.NewReader(""), io.Discard)
 	if err != nil {
 		t.Errorf("Error deleting note from database: %v", err)
 	}
@@ -42,22 +46,41 @@ func TestDeleteNonExistingNote(t *testing.T) {
 	testNoteContentNotInDB(t, note, testDb)
 	testNoteTitleNotInDB(t, note, testDb)
 
-	err := deleteNote(note.Title, testDb)
+	err := deleteNotes([]string{note.Title}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{yes: true}, testDb, strings.NewReader(""), io.Discard)
 	if err == nil {
 		t.Errorf("Expected error deleting non-existing note: %v", err)
 	}
 }
 
+func TestDeleteNoteByIDPrefix(t *testing.T) {
+	testDb, _ := testutil.SetupTestDB(t)
+
+	note := testutil.CreateTestNote()
+
+	err := new.StoreNoteInDB(note, testDb)
+	if err != nil {
+		t.Errorf("Error adding note to database: %v", err)
+	}
+
+	err = deleteNotes([]string{note.ID[:8]}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{yes: true}, testDb, strings.NewReader(""), io.Discard)
+	if err != nil {
+		t.Errorf("Error deleting note by ID prefix: %v", err)
+	}
+
+	testNoteContentNotInDB(t, note, testDb)
+	testNoteTitleNotInDB(t, note, testDb)
+}
+
 func testNoteTitleNotInDB(t *testing.T, note models.Note, database *bolt.DB) {
 	err := database.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(db.NotesTitleBucket))
+		bucket, err := db.NotebookTitles(tx, note.Notebook)
 
-		if bucket == nil {
-			t.Errorf("Bucket not found; expected %s", db.NotesTitleBucket)
-			return fmt.Errorf("bucket not found; expected %s", db.NotesTitleBucket)
+		if err != nil {
+			t.Errorf("Notebook not found; expected %s", note.Notebook)
+			return err
 		}
 
-		retrievedNote := bucket.Get([]byte(note.Title))
+		retrievedNote := bucket.Get(db.TitleKey(note.Title))
 		if retrievedNote != nil {
 			t.Errorf("Note title found; expected nothing")
 			return fmt.Errorf("note title found; expected nothing")
@@ -91,3 +114,152 @@ func testNoteContentNotInDB(t *testing.T, note models.Note, database *bolt.DB) {
 		t.Errorf("Error accessing bucket %q: %v", db.NotesBucket, err)
 	}
 }
+
+// storeTestNotes adds notes with the given titles to the database and returns them.
+// Each note is modified one day earlier than the previous one.
+func storeTestNotes(t *testing.T, database *bolt.DB, titles ...string) []models.Note {
+	var notes []models.Note
+	for i, title := range titles {
+		note := testutil.CreateTestNote()
+		note.Title = title
+		note.ModifiedAt = time.Now().Add(-time.Duration(i) * 24 * time.Hour)
+		if err := new.StoreNoteInDB(note, database); err != nil {
+			t.Fatalf("Error adding note to database: %v", err)
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+func TestDeleteNotesRequiresConfirmation(t *testing.T) {
+	testDb, _ := testutil.SetupTestDB(t)
+	notes := storeTestNotes(t, testDb, "first", "second")
+
+	var out bytes.Buffer
+	err := deleteNotes([]string{"first", "second"}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{}, testDb, strings.NewReader("n\n"), &out)
+	if err != nil {
+		t.Fatalf("Error deleting notes: %v", err)
+	}
+	for _, note := range notes {
+		testutil.TestNoteContentSaved(t, note, testDb)
+	}
+
+	err = deleteNotes([]string{"first", "second"}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{}, testDb, strings.NewReader("yes\n"), &out)
+	if err != nil {
+		t.Fatalf("Error deleting notes: %v", err)
+	}
+	for _, note := range notes {
+		testNoteContentNotInDB(t, note, testDb)
+		testNoteTitleNotInDB(t, note, testDb)
+	}
+}
+
+func TestDeleteNotesDryRun(t *testing.T) {
+	testDb, _ := testutil.SetupTestDB(t)
+	notes := storeTestNotes(t, testDb, "first")
+
+	var out bytes.Buffer
+	err := deleteNotes([]string{"first"}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{yes: true, dryRun: true}, testDb, strings.NewReader(""), &out)
+	if err != nil {
+		t.Fatalf("Error running dry run: %v", err)
+	}
+	if !strings.Contains(out.String(), "first") {
+		t.Errorf("Dry run should list the selected notes; got %q", out.String())
+	}
+	testutil.TestNoteContentSaved(t, notes[0], testDb)
+}
+
+func TestDeleteNotesByFilter(t *testing.T) {
+	testDb, _ := testutil.SetupTestDB(t)
+	notes := storeTestNotes(t, testDb, "draft one", "Draft two", "final", "draft three")
+
+	olderThan := 12 * time.Hour
+	filter := deleteFilter{
+		notebook:  db.DefaultNotebook,
+		olderThan: &olderThan,
+		titleGlob: "draft*",
+		now:       time.Now(),
+	}
+
+	var out bytes.Buffer
+	if err := deleteNotes(nil, filter, deleteOptions{yes: true}, testDb, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("Error deleting notes: %v", err)
+	}
+
+	// "draft one" was just modified and "final" doesn't match the glob.
+	testutil.TestNoteContentSaved(t, notes[0], testDb)
+	testutil.TestNoteContentSaved(t, notes[2], testDb)
+	testNoteContentNotInDB(t, notes[1], testDb)
+	testNoteContentNotInDB(t, notes[3], testDb)
+}
+
+func TestDeleteNotesIsAtomic(t *testing.T) {
+	testDb, _ := testutil.SetupTestDB(t)
+	notes := storeTestNotes(t, testDb, "first")
+
+	var out bytes.Buffer
+	err := deleteNotes([]string{"first", "missing"}, deleteFilter{notebook: db.DefaultNotebook}, deleteOptions{yes: true}, testDb, strings.NewReader(""), &out)
+	if err == nil {
+		t.Fatal("Expected error deleting a missing note; got nil")
+	}
+	testutil.TestNoteContentSaved(t, notes[0], testDb)
+}
+
+// answerAfter answers a confirmation prompt with "y" after running change,
+// which happens while the user is being asked.
+type answerAfter struct {
+	change func()
+}
+
+func (a *answerAfter) Read(p []byte) (int, error) {
+	if a.change != nil {
+		a.change()
+		a.change = nil
+	}
+	return copy(p, "y\n"), io.EOF
+}
+
+func TestDeleteNotesPromptsOutsideTransaction(t *testing.T) {
+	testDb, _ := testutil.SetupTestDB(t)
+	notes := storeTestNotes(t, testDb, "draft one")
+	filter := deleteFilter{notebook: db.DefaultNotebook, titleGlob: "draft*", now: time.Now()}
+
+	// Another note matching the filter is added while the user is asked
+	answer := &answerAfter{change: func() {
+		storeTestNotes(t, testDb, "draft two")
+	}}
+	var out bytes.Buffer
+	if err := deleteNotes(nil, filter, deleteOptions{}, testDb, answer, &out); err == nil {
+		t.Fatal("Expected error when the selection changed while confirming; got nil")
+	}
+	testutil.TestNoteContentSaved(t, notes[0], testDb)
+}
+
+func TestFilterFromFlagsZeroDuration(t *testing.T) {
+	cmd := DeleteCommand()
+	if err := cmd.Flags().Set(olderThanFlag, "0d"); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := filterFromFlags(cmd, db.DefaultNotebook)
+	if err != nil {
+		t.Fatalf("filterFromFlags() error = %v", err)
+	}
+	if filter.isEmpty() {
+		t.Error("Expected --older-than 0d to count as a filter")
+	}
+	if !filter.matches(models.Note{Notebook: db.DefaultNotebook, ModifiedAt: time.Now().Add(-time.Second)}) {
+		t.Error("Expected --older-than 0d to match every note")
+	}
+}
+
+func TestDeleteFilterMatchesTags(t *testing.T) {
+	filter := deleteFilter{tags: []string{"work", "old"}}
+
+	if !filter.matches(models.Note{Tags: []string{"old", "work", "misc"}}) {
+		t.Error("Expected note with all tags to match")
+	}
+	if filter.matches(models.Note{Tags: []string{"work"}}) {
+		t.Error("Expected note missing a tag not to match")
+	}
+}
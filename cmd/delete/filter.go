@@ -0,0 +1,161 @@
+package delete
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// deleteFilter selects notes of a notebook by their tags, age and title.
+// Unset fields don't restrict the selection.
+type deleteFilter struct {
+	// notebook scopes the other filters and the title handles;
+	// on its own it doesn't select any notes.
+	notebook string
+	tags     []string
+	// olderThan is nil unless --older-than was given; a zero duration
+	// selects every note.
+	olderThan *time.Duration
+	titleGlob string
+	now       time.Time
+}
+
+// filterFromFlags builds a deleteFilter from the command's filter flags,
+// validating the duration and glob pattern.
+func filterFromFlags(cmd *cobra.Command, notebook string) (deleteFilter, error) {
+	filter := deleteFilter{notebook: notebook, now: time.Now()}
+	filter.tags, _ = cmd.Flags().GetStringSlice(tagFlag)
+
+	if cmd.Flags().Changed(olderThanFlag) {
+		olderThan, _ := cmd.Flags().GetString(olderThanFlag)
+		duration, err := dates.ParseDuration(olderThan)
+		if err != nil {
+			return deleteFilter{}, fmt.Errorf("invalid --%s value: %w", olderThanFlag, err)
+		}
+		filter.olderThan = &duration
+	}
+
+	if titleGlob, _ := cmd.Flags().GetString(titleGlobFlag); titleGlob != "" {
+		filter.titleGlob = db.NormalizeTitle(titleGlob)
+		if _, err := path.Match(filter.titleGlob, ""); err != nil {
+			return deleteFilter{}, fmt.Errorf("invalid --%s pattern %q: %w", titleGlobFlag, titleGlob, err)
+		}
+	}
+	return filter, nil
+}
+
+// isEmpty reports whether no filter has been set.
+func (f deleteFilter) isEmpty() bool {
+	return len(f.tags) == 0 && f.olderThan == nil && f.titleGlob == ""
+}
+
+// matches reports whether a note in the filter's notebook satisfies every filter that has been set.
+// A note is older than the filter's duration when it hasn't been modified since.
+// Titles are matched case-insensitively.
+func (f deleteFilter) matches(note models.Note) bool {
+	if db.NormalizeTitle(note.Notebook) != db.NormalizeTitle(f.notebook) {
+		return false
+	}
+	for _, tag := range f.tags {
+		if !slices.Contains(note.Tags, tag) {
+			return false
+		}
+	}
+	if f.olderThan != nil && note.ModifiedAt.After(f.now.Add(-*f.olderThan)) {
+		return false
+	}
+	if f.titleGlob != "" {
+		if matched, _ := path.Match(f.titleGlob, db.NormalizeTitle(note.Title)); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// selectNotes returns the notes identified by handles together with every
+// note matching the filter, without duplicates and sorted by title.
+// It fails if any handle doesn't identify exactly one note.
+func selectNotes(tx *bolt.Tx, handles []string, filter deleteFilter) ([]models.Note, error) {
+	selected := make(map[string]models.Note)
+
+	for _, handle := range handles {
+		note, err := db.LookupNote(tx, filter.notebook, handle)
+		if err != nil {
+			return nil, fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+		selected[note.ID] = note
+	}
+
+	if !filter.isEmpty() {
+		notesBucket := tx.Bucket([]byte(db.NotesBucket))
+		if notesBucket == nil {
+			return nil, fmt.Errorf("bucket %s does not exist", db.NotesBucket)
+		}
+		err := notesBucket.ForEach(func(k, v []byte) error {
+			var note models.Note
+			if err := json.Unmarshal(v, &note); err != nil {
+				return fmt.Errorf("error reading note data: %w", err)
+			}
+			if filter.matches(note) {
+				selected[note.ID] = note
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sortByTitle(selected), nil
+}
+
+// selectStoreNotes selects notes like selectNotes, from a Store such as a
+// running server.
+func selectStoreNotes(store api.Store, handles []string, filter deleteFilter) ([]models.Note, error) {
+	selected := make(map[string]models.Note)
+
+	for _, handle := range handles {
+		note, err := store.Note(filter.notebook, handle)
+		if err != nil {
+			return nil, fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+		selected[note.ID] = note
+	}
+
+	if !filter.isEmpty() {
+		notes, err := store.Notes(filter.notebook)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			if filter.matches(note) {
+				selected[note.ID] = note
+			}
+		}
+	}
+
+	return sortByTitle(selected), nil
+}
+
+// sortByTitle returns the selected notes sorted by title.
+func sortByTitle(selected map[string]models.Note) []models.Note {
+	notes := make([]models.Note, 0, len(selected))
+	for _, note := range selected {
+		notes = append(notes, note)
+	}
+	sort.Slice(notes, func(a, b int) bool {
+		return notes[a].Title < notes[b].Title
+	})
+	return notes
+}
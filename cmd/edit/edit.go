@@ -1,26 +1,26 @@
 package edit
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
-	"os"
-	"os/exec"
-	"runtime"
 	"time"
 
 	"github.com/rhysmah/CLI-Note-App/cmd/root"
 	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/editor"
 	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
 	"github.com/spf13/cobra"
 
 	bolt "go.etcd.io/bbolt"
 )
 
 const (
-	editCmdFull  = "edit"
+	editCmdFull  = "edit <title|id-prefix>"
 	editCmdShort = "Edit a note"
-	editCmdLong  = "Edit a note by opening it in your default text editor"
+	editCmdLong  = `Edit a note by opening it in your default text editor.
+
+The note can be identified by its title or by a unique prefix of its ID
+(see 'cli-note list --show-id').`
 )
 
 // init registers the edit note command with the root command.
@@ -29,6 +29,8 @@ func init() {
 	root.RootCmd.AddCommand(editCommand)
 }
 
+// EditCommand creates and returns a cobra.Command for editing notes.
+// The command requires exactly one argument: the title or ID prefix of the note to edit.
 func EditCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   editCmdFull,
@@ -37,120 +39,56 @@ func EditCommand() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			noteTitle := args[0]
-			note, err := retrieveNote(noteTitle, root.NotesDB)
-			if err != nil {
-				return fmt.Errorf("error retrieving note %q: %w", noteTitle, err)
-			}
-
-			// Create temporary file to write data
-			tempFile, err := os.CreateTemp("", "temp-file-*.txt")
+			noteHandle := args[0]
+			note, err := retrieveNote(noteHandle, root.ActiveNotebook, root.NotesDB)
 			if err != nil {
-				return fmt.Errorf("error creating temp file: %w", err)
-			}
-
-			defer func() {
-				if err := tempFile.Close(); err != nil {
-					log.Printf("error closing temp file: %v", err)
-				}
-				if err = os.Remove(tempFile.Name()); err != nil {
-					log.Printf("error removing temp file: %v", err)
-				}
-			}()
-
-			// Copy data from current note to temp file
-			if _, err := tempFile.WriteString(note.Content); err != nil {
-				return fmt.Errorf("error writing to temp file: %w", err)
-			}
-
-			editor := determineEditor()
-			command := exec.Command(editor, tempFile.Name())
-			command.Stdin = os.Stdin
-			command.Stdout = os.Stdout
-			command.Stderr = os.Stderr
-
-			if err := command.Run(); err != nil {
-				return fmt.Errorf("error running editor: %w", err)
-			}
-
-			// Read back the edited file
-			editedContent, err := os.ReadFile(tempFile.Name())
-			if err != nil {
-				return fmt.Errorf("error reading edited file: %w", err)
-			}
-
-			// Check if content changed
-			if string(editedContent) != note.Content {
-				note.Content = string(editedContent)
-				note.ModifiedAt = time.Now()
-
-				// Save the updated note
-				if err := updateNote(note, root.NotesDB); err != nil {
-					return fmt.Errorf("error saving updated note: %w", err)
-				}
-
-				fmt.Println("Note updated successfully.")
-			} else {
-				fmt.Println("No changes made to note.")
+				return fmt.Errorf("error retrieving note %q: %w", noteHandle, err)
 			}
 
-			return nil
+			return EditNote(note, root.NotesDB)
 		},
 	}
 	return cmd
 }
 
-func retrieveNote(noteTitle string, database *bolt.DB) (models.Note, error) {
-	noteID, err := getNoteIDByTitle(noteTitle, database)
+// EditNote opens a note in the user's default text editor and saves the
+// edited content, updating its modification time and edit count, if anything changed.
+func EditNote(note models.Note, database *bolt.DB) error {
+	editedContent, err := editor.Edit(note.Content)
 	if err != nil {
-		return models.Note{}, err
+		return err
 	}
-	return getNoteContent(noteID, database)
-}
 
-func getNoteIDByTitle(noteTitle string, database *bolt.DB) (string, error) {
-	var retrievedNoteID string
+	// Check if content changed
+	if editedContent != note.Content {
+		note.Content = editedContent
+		note.ModifiedAt = time.Now()
+		note.EditCount++
 
-	err := database.View(func(tx *bolt.Tx) error {
-
-		notesTitleBucket := tx.Bucket([]byte(db.NotesTitleBucket))
-		if notesTitleBucket == nil {
-			return fmt.Errorf("bucket %s does not exist", db.NotesTitleBucket)
+		// Save the updated note
+		if err := updateNote(note, database); err != nil {
+			return fmt.Errorf("error saving updated note: %w", err)
 		}
 
-		noteID := notesTitleBucket.Get([]byte(noteTitle))
-		if noteID == nil {
-			return fmt.Errorf("note %q does not exist", noteTitle)
-		}
-
-		retrievedNoteID = string(noteID)
-
-		return nil
-	})
-
-	if err != nil {
-		return "", fmt.Errorf("error retrieving note ID %w", err)
+		fmt.Println(output.Paint(output.Success, "Note updated successfully."))
+	} else {
+		fmt.Println("No changes made to note.")
 	}
 
-	return retrievedNoteID, nil
+	return nil
 }
 
-func getNoteContent(noteID string, database *bolt.DB) (models.Note, error) {
+// retrieveNote looks up a note by its title in the notebook or a unique prefix of its ID.
+func retrieveNote(handle, notebook string, database *bolt.DB) (models.Note, error) {
 	var retrievedNote models.Note
 
 	err := database.View(func(tx *bolt.Tx) error {
-
-		notesBucket := tx.Bucket([]byte(db.NotesBucket))
-		if notesBucket == nil {
-			return fmt.Errorf("bucket %s does not exist", db.NotesBucket)
-		}
-
-		note := notesBucket.Get([]byte(noteID))
-		if note == nil {
-			return fmt.Errorf("noteID %s does not exist", noteID)
+		note, err := db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return err
 		}
-
-		return json.Unmarshal(note, &retrievedNote)
+		retrievedNote = note
+		return nil
 	})
 
 	if err != nil {
@@ -160,58 +98,9 @@ func getNoteContent(noteID string, database *bolt.DB) (models.Note, error) {
 	return retrievedNote, nil
 }
 
+// updateNote saves an edited note and re-indexes its [[links]].
 func updateNote(note models.Note, database *bolt.DB) error {
 	return database.Update(func(tx *bolt.Tx) error {
-		// Get the notes bucket
-		notesBucket := tx.Bucket([]byte(db.NotesBucket))
-		if notesBucket == nil {
-			return fmt.Errorf("bucket %s does not exist", db.NotesBucket)
-		}
-
-		// Marshal the updated note to JSON
-		noteJSON, err := json.Marshal(note)
-		if err != nil {
-			return fmt.Errorf("failed to marshal note: %w", err)
-		}
-
-		// Store the updated note
-		if err := notesBucket.Put([]byte(note.ID), noteJSON); err != nil {
-			return fmt.Errorf("failed to store note: %w", err)
-		}
-
-		return nil
+		return db.PutNote(tx, note)
 	})
 }
-
-func determineEditor() string {
-	if editor := os.Getenv("EDITOR"); editor != "" {
-		return editor
-	}
-
-	switch runtime.GOOS {
-	case "windows":
-		return "notepad"
-	case "darwin": // macOS
-		for _, editor := range []string{"nano", "vim", "vi"} {
-			if _, err := exec.LookPath(editor); err == nil {
-				return editor
-			}
-		}
-
-		// Fall back to TextEdit if available
-		if _, err := exec.LookPath("TextEdit"); err == nil {
-			return "open -a TextEdit"
-		}
-
-		// Last resort
-		return "nano"
-	default: // Linux and others
-		// Try common editors
-		for _, editor := range []string{"nano", "vim", "vi", "emacs"} {
-			if _, err := exec.LookPath(editor); err == nil {
-				return editor
-			}
-		}
-		return "nano" // Default
-	}
-}
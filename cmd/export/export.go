@@ -0,0 +1,141 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/list"
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/config"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/rhysmah/CLI-Note-App/site"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	exportCmdFull  = "export"
+	exportCmdShort = "Export notes to other formats"
+
+	htmlCmdFull  = "html <directory>"
+	htmlCmdShort = "Export notes as a static HTML site"
+	htmlCmdDesc  = `Export the notes of the current notebook as a static HTML site that can be
+browsed in any web browser.
+
+The site has an index page listing the notes, sorted like 'cli-note list',
+one page per note with its Markdown rendered, its [[links]] pointing at the
+linked notes' pages and a list of the notes linking to it, and one page per tag.
+
+Pages are generated from a built-in theme. Use --theme to point at a
+directory with your own layout.html, index.html, note.html, tag.html or
+style.css; files missing from it are taken from the built-in theme.
+
+Existing pages in the directory are overwritten.
+
+Examples:
+  cli-note export html ~/notes-site
+  cli-note export html site --all-notebooks --sort-by title
+  cli-note export html site --theme ~/my-theme`
+
+	titleFlag        = "title"
+	themeFlag        = "theme"
+	allNotebooksFlag = "all-notebooks"
+
+	defaultSiteTitle = "Notes"
+)
+
+// init registers the export command with the root command.
+func init() {
+	root.RootCmd.AddCommand(ExportCommand())
+}
+
+// ExportCommand creates and returns a cobra.Command grouping the export formats.
+func ExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   exportCmdFull,
+		Short: exportCmdShort,
+	}
+
+	cmd.AddCommand(htmlCommand())
+	return cmd
+}
+
+func htmlCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   htmlCmdFull,
+		Short: htmlCmdShort,
+		Long:  htmlCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notebook := root.ActiveNotebook
+			if allNotebooks, _ := cmd.Flags().GetBool(allNotebooksFlag); allNotebooks {
+				notebook = ""
+			}
+
+			notes, err := notesToExport(notebook, root.NotesDB)
+			if err != nil {
+				return err
+			}
+			notes = list.FilterArchived(notes, list.ArchiveFilterFromFlags(cmd))
+
+			sortBy, orderBy := list.SortFromFlags(cmd)
+			list.SortNotes(notes, sortBy, orderBy)
+
+			dateFormatter, err := exportDateFormatter(root.Config)
+			if err != nil {
+				return err
+			}
+
+			opts := site.Options{Sort: list.SortDescription(sortBy, orderBy), Dates: dateFormatter}
+			opts.Title, _ = cmd.Flags().GetString(titleFlag)
+			opts.ThemeDirectory, _ = cmd.Flags().GetString(themeFlag)
+
+			if err := site.Build(args[0], notes, opts); err != nil {
+				return fmt.Errorf("error exporting notes: %w", err)
+			}
+			fmt.Println(output.Sprintf(output.Success, "Exported %s to %s", output.Pluralize(len(notes), "note"), args[0]))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(titleFlag, defaultSiteTitle, "Title of the site")
+	cmd.Flags().String(themeFlag, "", "Directory with theme files overriding the built-in theme")
+	cmd.Flags().Bool(allNotebooksFlag, false, "Export notes from every notebook")
+	list.AddSortFlags(cmd)
+	list.AddArchiveFlags(cmd)
+
+	return cmd
+}
+
+// notesToExport returns the notes in notebook, or in every notebook if it is empty.
+func notesToExport(notebook string, database *bolt.DB) ([]models.Note, error) {
+	var notes []models.Note
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		if notebook == "" {
+			notes, err = db.AllNotes(tx)
+		} else {
+			notes, err = db.NotesInNotebook(tx, notebook)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading notes: %w", err)
+	}
+	return notes, nil
+}
+
+// exportDateFormatter returns the configured date format and time zone.
+// Relative dates would go stale in exported pages, so they are shown
+// with the default layout instead.
+func exportDateFormatter(cfg config.Config) (dates.Formatter, error) {
+	format := cfg.DateFormat
+	if strings.EqualFold(strings.TrimSpace(format), dates.FormatRelative) {
+		format = dates.FormatDefault
+	}
+	return dates.NewFormatter(format, cfg.Timezone)
+}
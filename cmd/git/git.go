@@ -0,0 +1,164 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/gitsync"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	gitCmdFull  = "git"
+	gitCmdShort = "Sync notes through a git repository"
+	gitCmdDesc  = `Keep your notes in a local git repository, to version them and share them
+between machines through a git remote. No hosted service is needed: a bare
+repository on a USB drive, a network share or a server you can SSH into
+will do.
+
+Every note is stored in notes/<id>.md, its title, notebook, tags and dates
+as front matter above its content.
+
+Examples:
+  git init --bare /mnt/usb/notes.git
+  cli-note git init ~/notes-repo --remote /mnt/usb/notes.git
+  cli-note git sync`
+
+	initCmdFull  = "init <directory>"
+	initCmdShort = "Create a git repository for your notes and sync it"
+	initCmdDesc  = `Create a git repository in a directory, or use the one there, and sync
+your notes with it. With --remote, the repository syncs with that remote;
+if it already has notes, for example pushed from another machine, they
+are added to yours.
+
+The directory is remembered, so 'git sync' needs no argument afterwards.`
+
+	syncCmdFull  = "sync [directory]"
+	syncCmdShort = "Commit, pull and push changes to your notes"
+	syncCmdDesc  = `Sync your notes with their git repository:
+
+  1. write your notes to the repository and commit the changes, with a
+     message naming the notes created, edited and deleted
+  2. pull the remote's changes, if there is a remote
+  3. apply the notes created, edited and deleted elsewhere to your notes
+  4. push the result to the remote
+
+When a note was changed on both sides and git can't merge the changes,
+your version is kept and the other one can be found in the repository's
+history. A note deleted on one side but changed on the other is kept.
+
+Without a directory, the one given to 'git init' is synced.`
+
+	remoteFlag = "remote"
+)
+
+// init registers the git command with the root command.
+func init() {
+	gitCommand := GitCommand()
+	root.RootCmd.AddCommand(gitCommand)
+}
+
+// GitCommand creates and returns a cobra.Command for syncing notes through git.
+// The command itself does nothing; the work is done by its subcommands.
+func GitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   gitCmdFull,
+		Short: gitCmdShort,
+		Long:  gitCmdDesc,
+	}
+
+	cmd.AddCommand(initCommand(), syncCommand())
+	return cmd
+}
+
+func initCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   initCmdFull,
+		Short: initCmdShort,
+		Long:  initCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remoteURL, _ := cmd.Flags().GetString(remoteFlag)
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("error resolving %s: %w", args[0], err)
+			}
+
+			repo, err := gitsync.Init(dir, root.NotesDB, remoteURL)
+			if err != nil {
+				return fmt.Errorf("error creating repository: %w", err)
+			}
+			root.Config.GitDirectory = dir
+			if err := root.Config.Save(root.ConfigPath); err != nil {
+				return fmt.Errorf("error remembering repository: %w", err)
+			}
+
+			if err := sync(repo); err != nil {
+				return err
+			}
+			fmt.Println(output.Sprintf(output.Success, "Notes are in the git repository %s", dir))
+			fmt.Println("Run 'cli-note git sync' to commit, pull and push changes.")
+			return nil
+		},
+	}
+
+	cmd.Flags().String(remoteFlag, "", "URL or path of the git remote to sync with")
+	return cmd
+}
+
+func syncCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   syncCmdFull,
+		Short: syncCmdShort,
+		Long:  syncCmdDesc,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := root.Config.GitDirectory
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			if dir == "" {
+				return errors.New("no git repository; create one with 'cli-note git init <directory>'")
+			}
+
+			repo, err := gitsync.Open(dir, root.NotesDB)
+			if errors.Is(err, gitsync.ErrNotRepository) {
+				return fmt.Errorf("%s is not a git repository; create one with 'cli-note git init %s'", dir, dir)
+			}
+			if err != nil {
+				return err
+			}
+			return sync(repo)
+		},
+	}
+}
+
+// sync syncs repo and prints what it did.
+func sync(repo *gitsync.Repo) error {
+	result, err := repo.Sync()
+	for _, warning := range result.Warnings {
+		fmt.Println(output.Sprintf(output.Warning, "warning: %s", warning))
+	}
+	for _, subject := range result.Commits {
+		fmt.Printf("Committed: %s\n", subject)
+	}
+	if result.Pulled {
+		fmt.Println("Pulled changes from the remote")
+	}
+	for _, change := range result.Imported {
+		fmt.Println(change)
+	}
+	if result.Pushed {
+		fmt.Println("Pushed to the remote")
+	}
+	if err != nil {
+		return fmt.Errorf("error syncing notes: %w", err)
+	}
+	if len(result.Commits) == 0 && len(result.Imported) == 0 {
+		fmt.Println("Notes are up to date.")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/graph"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	graphCmdFull  = "graph"
+	graphCmdShort = "Export the graph of notes, links and tags"
+	graphCmdDesc  = `Export the notes of the current notebook and the [[links]] between them
+as a Graphviz DOT or JSON graph.
+
+Use --tags to add tag nodes with an edge from each note to its tags,
+--tag to keep only notes with the given tags, and --all-notebooks to
+include every notebook.
+
+Examples:
+  cli-note graph | dot -Tsvg > notes.svg
+  cli-note graph --format json --all-notebooks
+  cli-note graph --tags --tag project`
+
+	formatFlag       = "format"
+	tagsFlag         = "tags"
+	tagFlag          = "tag"
+	allNotebooksFlag = "all-notebooks"
+
+	formatDOT  = "dot"
+	formatJSON = "json"
+)
+
+// init registers the graph command with the root command.
+func init() {
+	graphCommand := GraphCommand()
+	root.RootCmd.AddCommand(graphCommand)
+}
+
+// GraphCommand creates and returns a cobra.Command for exporting the note graph.
+func GraphCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   graphCmdFull,
+		Short: graphCmdShort,
+		Long:  graphCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString(formatFlag)
+			if format != formatDOT && format != formatJSON {
+				return fmt.Errorf("unknown format %q; use %q or %q", format, formatDOT, formatJSON)
+			}
+
+			var opts graph.Options
+			opts.Tags, _ = cmd.Flags().GetBool(tagsFlag)
+			opts.FilterTags, _ = cmd.Flags().GetStringSlice(tagFlag)
+
+			notebook := root.ActiveNotebook
+			if allNotebooks, _ := cmd.Flags().GetBool(allNotebooksFlag); allNotebooks {
+				notebook = ""
+			}
+
+			noteGraph, err := buildGraph(notebook, opts, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			if format == formatJSON {
+				return noteGraph.WriteJSON(os.Stdout)
+			}
+			return noteGraph.WriteDOT(os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringP(formatFlag, "f", formatDOT, "Output format: dot, json")
+	cmd.Flags().Bool(tagsFlag, false, "Include tags as nodes")
+	cmd.Flags().StringSlice(tagFlag, nil, "Only include notes with this tag (repeatable)")
+	cmd.Flags().Bool(allNotebooksFlag, false, "Include notes from every notebook")
+
+	return cmd
+}
+
+// buildGraph builds the graph of the notes in notebook, or of every note if notebook is empty.
+func buildGraph(notebook string, opts graph.Options, database *bolt.DB) (graph.Graph, error) {
+	var notes []models.Note
+	links := make(map[string][]string)
+
+	err := database.View(func(tx *bolt.Tx) error {
+		allNotes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, note := range allNotes {
+			if notebook != "" && db.NormalizeTitle(note.Notebook) != db.NormalizeTitle(notebook) {
+				continue
+			}
+			notes = append(notes, note)
+
+			noteLinks, err := db.OutboundLinks(tx, note.ID)
+			if err != nil {
+				return err
+			}
+			for _, link := range noteLinks {
+				if !link.Dangling() {
+					links[note.ID] = append(links[note.ID], link.TargetID)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return graph.Graph{}, fmt.Errorf("error reading notes: %w", err)
+	}
+
+	return graph.Build(notes, links, opts), nil
+}
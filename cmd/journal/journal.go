@@ -0,0 +1,235 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/edit"
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/config"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/templates"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	journalCmdFull  = "journal [today|yesterday|tomorrow|YYYY-MM-DD]"
+	journalCmdShort = "Open the journal entry for a day"
+	journalCmdDesc  = `Open the journal entry for a day in your default text editor,
+creating it if it doesn't exist yet. Without a date, today's entry is opened.
+
+Journal entries are ordinary notes tagged "journal", titled with the date.
+The title format and an optional template for new entries are set in the
+config file (~/.notes/config.json):
+
+  {
+    "journal_title_format": "2006-01-02",
+    "journal_template": "standup"
+  }
+
+The title format is a Go time layout and must produce a valid note title,
+so it cannot contain characters such as '/', ':' or '.'.
+
+Examples:
+  cli-note journal
+  cli-note journal yesterday
+  cli-note journal 2026-10-01
+  cli-note journal list --month 2026-10`
+
+	todayCmdFull  = "today"
+	todayCmdShort = "Open today's journal entry"
+
+	monthFlag = "month"
+
+	// JournalTag is added to every journal entry.
+	JournalTag = "journal"
+)
+
+// init registers the journal and today commands with the root command.
+func init() {
+	root.RootCmd.AddCommand(JournalCommand())
+	root.RootCmd.AddCommand(TodayCommand())
+}
+
+// JournalCommand creates and returns a cobra.Command for opening journal entries.
+// The command accepts an optional argument: the day of the entry.
+func JournalCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   journalCmdFull,
+		Short: journalCmdShort,
+		Long:  journalCmdDesc,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var input string
+			if len(args) > 0 {
+				input = args[0]
+			}
+
+			day, err := dates.ParseDay(input, time.Now())
+			if err != nil {
+				return err
+			}
+			return openJournalEntry(day)
+		},
+	}
+
+	cmd.AddCommand(listCommand())
+	return cmd
+}
+
+// TodayCommand creates and returns a cobra.Command for opening today's journal entry.
+// It is a shortcut for 'journal today'.
+func TodayCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   todayCmdFull,
+		Short: todayCmdShort,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return openJournalEntry(dates.StartOfDay(time.Now()))
+		},
+	}
+}
+
+func listCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the journal entries of a month",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			monthInput, _ := cmd.Flags().GetString(monthFlag)
+			month, err := dates.ParseMonth(monthInput, time.Now())
+			if err != nil {
+				return err
+			}
+
+			entries, err := journalEntries(month, root.ActiveNotebook, root.Config, root.NotesDB)
+			if err != nil {
+				return fmt.Errorf("error listing journal entries: %w", err)
+			}
+
+			if len(entries) == 0 {
+				fmt.Printf("No journal entries for %s\n", month.Format("January 2006"))
+				return nil
+			}
+			for _, entry := range entries {
+				firstLine, _, _ := strings.Cut(strings.TrimSpace(entry.note.Content), "\n")
+				fmt.Printf("%s  %-9s  %s\n", entry.day.Format(dates.DayFormat), entry.day.Weekday(), firstLine)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(monthFlag, "", "Month to list as YYYY-MM (defaults to the current month)")
+	return cmd
+}
+
+// openJournalEntry opens the journal entry for day in the user's editor,
+// creating it first if needed.
+func openJournalEntry(day time.Time) error {
+	note, created, err := journalEntry(day, root.ActiveNotebook, root.Config, root.NotesDB)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("Created journal entry %q\n", note.Title)
+	}
+	return edit.EditNote(note, root.NotesDB)
+}
+
+// journalEntry returns the journal entry for day in notebook, creating it
+// (from the configured journal template, if any) when it doesn't exist yet.
+// It reports whether the entry was created.
+func journalEntry(day time.Time, notebook string, cfg config.Config, database *bolt.DB) (models.Note, bool, error) {
+	title := day.Format(cfg.JournalTitleLayout())
+
+	var entry models.Note
+	var created bool
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		existing, err := db.LookupNote(tx, notebook, title)
+		if err == nil {
+			entry = existing
+			return nil
+		}
+		if !errors.Is(err, db.ErrNoteNotFound) {
+			return err
+		}
+
+		note, err := new.CreateNote(title, notebook)
+		if err != nil {
+			return fmt.Errorf("journal title %q from format %q is not a valid note title: %w",
+				title, cfg.JournalTitleLayout(), err)
+		}
+		note.Tags = []string{JournalTag}
+
+		if cfg.JournalTemplate != "" {
+			template, err := db.GetTemplate(tx, cfg.JournalTemplate)
+			if err != nil {
+				return fmt.Errorf("error loading journal template: %w", err)
+			}
+			variables := templates.Variables(title, notebook, day, nil)
+			if note.Content, err = templates.Render(template.Content, variables); err != nil {
+				return fmt.Errorf("error applying journal template %q: %w", template.Name, err)
+			}
+		}
+
+		if err := db.PutNote(tx, note); err != nil {
+			return err
+		}
+		if err := db.PutNoteTitle(tx, note); err != nil {
+			return err
+		}
+		entry, created = note, true
+		return nil
+	})
+
+	return entry, created, err
+}
+
+// datedEntry is a journal entry together with the day it belongs to.
+type datedEntry struct {
+	day  time.Time
+	note models.Note
+}
+
+// journalEntries returns the journal entries in notebook for the month
+// starting at month, sorted by day. Entries are recognized by their tag
+// and by their title matching the configured title format.
+func journalEntries(month time.Time, notebook string, cfg config.Config, database *bolt.DB) ([]datedEntry, error) {
+	var entries []datedEntry
+
+	err := database.View(func(tx *bolt.Tx) error {
+		notes, err := db.NotesInNotebook(tx, notebook)
+		if err != nil {
+			return err
+		}
+
+		for _, note := range notes {
+			if !slices.Contains(note.Tags, JournalTag) {
+				continue
+			}
+			day, err := time.ParseInLocation(cfg.JournalTitleLayout(), note.Title, month.Location())
+			if err != nil {
+				continue
+			}
+			if day.Year() == month.Year() && day.Month() == month.Month() {
+				entries = append(entries, datedEntry{day: day, note: note})
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].day.Before(entries[b].day)
+	})
+	return entries, err
+}
@@ -0,0 +1,92 @@
+package journal
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/config"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestJournalEntryCreatesOnce(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	err := testDB.Update(func(tx *bolt.Tx) error {
+		return db.PutTemplate(tx, models.Template{Name: "standup", Content: "# {{.Weekday}} {{.Title}}"})
+	})
+	if err != nil {
+		t.Fatalf("Failed to store template: %v", err)
+	}
+
+	cfg := config.Config{JournalTemplate: "standup"}
+	day := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local)
+
+	note, created, err := journalEntry(day, db.DefaultNotebook, cfg, testDB)
+	if err != nil {
+		t.Fatalf("Failed to create journal entry: %v", err)
+	}
+	if !created {
+		t.Error("Expected journal entry to be created")
+	}
+	if note.Title != "2026-10-19" {
+		t.Errorf("Journal title = %q; want %q", note.Title, "2026-10-19")
+	}
+	if !slices.Contains(note.Tags, JournalTag) {
+		t.Errorf("Journal entry should be tagged %q; got %v", JournalTag, note.Tags)
+	}
+	if want := "# Monday 2026-10-19"; note.Content != want {
+		t.Errorf("Journal content = %q; want %q", note.Content, want)
+	}
+	testutil.TestNoteTitleSaved(t, note, testDB)
+
+	again, created, err := journalEntry(day, db.DefaultNotebook, cfg, testDB)
+	if err != nil {
+		t.Fatalf("Failed to open journal entry: %v", err)
+	}
+	if created || again.ID != note.ID {
+		t.Errorf("Expected the existing journal entry to be reused")
+	}
+}
+
+func TestJournalEntryRejectsInvalidTitleFormat(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	cfg := config.Config{JournalTitleFormat: "2006/01/02"}
+	day := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local)
+
+	if _, _, err := journalEntry(day, db.DefaultNotebook, cfg, testDB); err == nil {
+		t.Error("Expected error for a title format producing illegal characters")
+	}
+}
+
+func TestJournalEntriesByMonth(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	cfg := config.Config{JournalTitleFormat: "Mon 02 Jan 2006"}
+	for _, day := range []time.Time{
+		time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local),
+		time.Date(2026, time.October, 2, 0, 0, 0, 0, time.Local),
+		time.Date(2026, time.November, 1, 0, 0, 0, 0, time.Local),
+	} {
+		if _, _, err := journalEntry(day, db.DefaultNotebook, cfg, testDB); err != nil {
+			t.Fatalf("Failed to create journal entry: %v", err)
+		}
+	}
+
+	month := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)
+	entries, err := journalEntries(month, db.DefaultNotebook, cfg, testDB)
+	if err != nil {
+		t.Fatalf("Failed to list journal entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries in October; got %d", len(entries))
+	}
+	if entries[0].note.Title != "Fri 02 Oct 2026" || entries[1].note.Title != "Mon 19 Oct 2026" {
+		t.Errorf("Unexpected entries: %q, %q", entries[0].note.Title, entries[1].note.Title)
+	}
+}
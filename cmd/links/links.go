@@ -0,0 +1,255 @@
+package links
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	linksCmdFull  = "links [title|id-prefix]"
+	linksCmdShort = "List the links from a note"
+	linksCmdDesc  = `List the notes a note links to with [[Note Title]].
+
+Links to notes that don't exist are marked as dangling. With --dangling and
+no note, every dangling link in every notebook is reported.
+
+Examples:
+  cli-note links "Project Plan"
+  cli-note links --dangling`
+
+	backlinksCmdFull  = "backlinks <title|id-prefix>"
+	backlinksCmdShort = "List the notes linking to a note"
+	backlinksCmdDesc  = `List the notes that link to a note with [[Note Title]].
+
+Links are stored by note ID. When a note is renamed or moved, or its
+notebook renamed, the links to it are rewritten to keep pointing at it.`
+
+	danglingFlag = "dangling"
+)
+
+// init registers the links and backlinks commands with the root command.
+func init() {
+	root.RootCmd.AddCommand(LinksCommand())
+	root.RootCmd.AddCommand(BacklinksCommand())
+}
+
+// LinksCommand creates and returns a cobra.Command for listing a note's outbound links.
+func LinksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   linksCmdFull,
+		Short: linksCmdShort,
+		Long:  linksCmdDesc,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			danglingOnly, _ := cmd.Flags().GetBool(danglingFlag)
+
+			if len(args) == 0 {
+				if !danglingOnly {
+					return fmt.Errorf("please name a note, or use --%s to list every dangling link", danglingFlag)
+				}
+				report, err := danglingReport(root.NotesDB)
+				if err != nil {
+					return err
+				}
+				printDanglingReport(report)
+				return nil
+			}
+
+			note, links, err := outboundLinks(args[0], root.ActiveNotebook, root.NotesDB)
+			if err != nil {
+				return err
+			}
+			printLinks(note, links, danglingOnly)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(danglingFlag, false, "Only show links to notes that don't exist")
+
+	return cmd
+}
+
+// BacklinksCommand creates and returns a cobra.Command for listing the notes linking to a note.
+func BacklinksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   backlinksCmdFull,
+		Short: backlinksCmdShort,
+		Long:  backlinksCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			note, sources, err := backlinks(args[0], root.ActiveNotebook, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			if len(sources) == 0 {
+				fmt.Printf("No notes link to %q.\n", note.Title)
+				return nil
+			}
+			fmt.Printf("Notes linking to %q:\n", note.Title)
+			for _, source := range sources {
+				fmt.Printf("  %s (%s)\n", source.Title, source.Notebook)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// linkedNote is an outbound link together with the note it points at, if any.
+type linkedNote struct {
+	link db.NoteLink
+	note models.Note
+}
+
+// outboundLinks returns the note identified by handle and its outbound links.
+func outboundLinks(handle, notebook string, database *bolt.DB) (models.Note, []linkedNote, error) {
+	var note models.Note
+	var linked []linkedNote
+
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		noteLinks, err := db.OutboundLinks(tx, note.ID)
+		if err != nil {
+			return err
+		}
+		for _, link := range noteLinks {
+			entry := linkedNote{link: link}
+			if !link.Dangling() {
+				if entry.note, err = db.GetNote(tx, link.TargetID); err != nil {
+					return err
+				}
+			}
+			linked = append(linked, entry)
+		}
+		return nil
+	})
+
+	sort.Slice(linked, func(a, b int) bool {
+		return db.NormalizeTitle(linked[a].link.Target) < db.NormalizeTitle(linked[b].link.Target)
+	})
+	return note, linked, err
+}
+
+// backlinks returns the note identified by handle and the notes linking to it, sorted by title.
+func backlinks(handle, notebook string, database *bolt.DB) (models.Note, []models.Note, error) {
+	var note models.Note
+	var sources []models.Note
+
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		sourceIDs, err := db.Backlinks(tx, note.ID)
+		if err != nil {
+			return err
+		}
+		for _, sourceID := range sourceIDs {
+			source, err := db.GetNote(tx, sourceID)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, source)
+		}
+		return nil
+	})
+
+	sort.Slice(sources, func(a, b int) bool {
+		return db.NormalizeTitle(sources[a].Title) < db.NormalizeTitle(sources[b].Title)
+	})
+	return note, sources, err
+}
+
+// danglingLinks is a note together with the targets of its dangling links.
+type danglingLinks struct {
+	note    models.Note
+	targets []string
+}
+
+// danglingReport returns every note with dangling links, sorted by notebook and title.
+func danglingReport(database *bolt.DB) ([]danglingLinks, error) {
+	var report []danglingLinks
+
+	err := database.View(func(tx *bolt.Tx) error {
+		dangling, err := db.DanglingLinks(tx)
+		if err != nil {
+			return err
+		}
+		for sourceID, targets := range dangling {
+			note, err := db.GetNote(tx, sourceID)
+			if err != nil {
+				return err
+			}
+			sort.Strings(targets)
+			report = append(report, danglingLinks{note: note, targets: targets})
+		}
+		return nil
+	})
+
+	sort.Slice(report, func(a, b int) bool {
+		if report[a].note.Notebook != report[b].note.Notebook {
+			return db.NormalizeTitle(report[a].note.Notebook) < db.NormalizeTitle(report[b].note.Notebook)
+		}
+		return db.NormalizeTitle(report[a].note.Title) < db.NormalizeTitle(report[b].note.Title)
+	})
+	return report, err
+}
+
+// printLinks prints a note's outbound links, or only its dangling ones.
+func printLinks(note models.Note, linked []linkedNote, danglingOnly bool) {
+	var shown []linkedNote
+	for _, entry := range linked {
+		if !danglingOnly || entry.link.Dangling() {
+			shown = append(shown, entry)
+		}
+	}
+
+	if len(shown) == 0 {
+		if danglingOnly {
+			fmt.Printf("%q has no dangling links.\n", note.Title)
+		} else {
+			fmt.Printf("%q doesn't link to any notes.\n", note.Title)
+		}
+		return
+	}
+
+	fmt.Printf("Links from %q:\n", note.Title)
+	for _, entry := range shown {
+		if entry.link.Dangling() {
+			fmt.Printf("  [[%s]] (dangling)\n", entry.link.Target)
+		} else {
+			fmt.Printf("  %s (%s)\n", entry.note.Title, entry.note.Notebook)
+		}
+	}
+}
+
+// printDanglingReport prints every dangling link, grouped by the note containing it.
+func printDanglingReport(report []danglingLinks) {
+	if len(report) == 0 {
+		fmt.Println("No dangling links.")
+		return
+	}
+
+	fmt.Println("Dangling links:")
+	for _, entry := range report {
+		fmt.Printf("  %s (%s)\n", entry.note.Title, entry.note.Notebook)
+		for _, target := range entry.targets {
+			fmt.Printf("    [[%s]]\n", target)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package list
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/stats"
+	"github.com/rhysmah/CLI-Note-App/table"
+)
+
+// Names of the columns that can be shown with --columns.
+const (
+	ColumnID       = "id"
+	ColumnTitle    = "title"
+	ColumnNotebook = "notebook"
+	ColumnTags     = "tags"
+	ColumnCreated  = "created"
+	ColumnModified = "modified"
+	ColumnWords    = "words"
+	ColumnProgress = "progress"
+)
+
+// DefaultColumns are shown when no columns are chosen.
+var DefaultColumns = []string{ColumnTitle, ColumnCreated, ColumnModified}
+
+// column is a column of the notes table and how to fill it from a note.
+type column struct {
+	table.Column
+	value func(note models.Note, opts DisplayOptions) string
+}
+
+// columns maps column names to their definitions.
+var columns = map[string]column{
+	ColumnID: {
+		Column: table.Column{Header: headerID},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return opts.IDPrefixes[note.ID]
+		},
+	},
+	ColumnTitle: {
+		Column: table.Column{Header: headerFileName, Truncate: table.TruncateMiddle},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return note.Title
+		},
+	},
+	ColumnNotebook: {
+		Column: table.Column{Header: headerNotebook, Truncate: table.TruncateEnd},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return note.Notebook
+		},
+	},
+	ColumnTags: {
+		Column: table.Column{Header: headerTags, Truncate: table.TruncateEnd},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return strings.Join(note.Tags, ", ")
+		},
+	},
+	ColumnCreated: {
+		Column: table.Column{Header: headerCreated},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return opts.Dates.Format(note.CreatedAt)
+		},
+	},
+	ColumnModified: {
+		Column: table.Column{Header: headerModified},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return opts.Dates.Format(note.ModifiedAt)
+		},
+	},
+	ColumnWords: {
+		Column: table.Column{Header: headerWords, Align: table.AlignRight},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return strconv.Itoa(stats.Words(note.Content))
+		},
+	},
+	ColumnProgress: {
+		Column: table.Column{Header: headerProgress},
+		value: func(note models.Note, opts DisplayOptions) string {
+			return formatProgress(note)
+		},
+	},
+}
+
+// columnNames returns the names of every column, sorted.
+func columnNames() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseColumns parses a comma-separated list of column names, such as
+// "title,tags,modified". An empty list yields DefaultColumns.
+func ParseColumns(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return slices.Clone(DefaultColumns), nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q: choose from %s", name, strings.Join(columnNames(), ", "))
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -2,17 +2,27 @@ package list
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"strings"
-	"time"
 
+	"github.com/rhysmah/CLI-Note-App/dates"
 	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/rhysmah/CLI-Note-App/table"
+	"github.com/rhysmah/CLI-Note-App/todo"
 )
 
 const (
-	dateTimeFormat = "Jan 02, 2006 15:04"
+	headerID       = "ID"
 	headerFileName = "File Name"
+	headerNotebook = "Notebook"
+	headerTags     = "Tags"
 	headerCreated  = "Created Date"
 	headerModified = "Modified Date"
+	headerWords    = "Words"
+	headerProgress = "Progress"
+	noProgress     = "-"
 
 	alphabetical        = "A - Z"
 	reverseAlphabetical = "Z - A"
@@ -21,47 +31,122 @@ const (
 )
 
 const (
-	dateTimeWidth = 17
-	lineSymbol    = "-"
-	separator     = "  |  "
+	lineSymbol = "-"
+	separator  = "  |  "
 )
 
+// DisplayOptions controls optional parts of the notes table.
+type DisplayOptions struct {
+	// Notebook is the name of the notebook being listed, shown in the header.
+	// It is left empty when notes from several notebooks are listed together.
+	Notebook string
+
+	// Columns are the names of the columns to show, in order.
+	// DefaultColumns are shown when it is empty.
+	Columns []string
+
+	// IDPrefixes maps note IDs to the prefix shown in the ID column.
+	// The ID column is added in front when this is non-nil.
+	IDPrefixes map[string]string
+
+	// ShowProgress adds a column with the number of done and total
+	// checklist items of each note.
+	ShowProgress bool
+
+	// Dates formats the created and modified dates.
+	// The zero value uses the default layout in local time.
+	Dates dates.Formatter
+
+	// MaxWidth is the width the table is fitted into by truncating
+	// titles, notebooks and tags. The table isn't truncated if it is 0.
+	MaxWidth int
+}
+
 // DisplayNotes renders a formatted table of notes.
-// It displays notes based on the specified sort criteria and order.
+// It displays notes based on the specified sort criteria and order,
+// except that pinned notes are always shown first.
 //
 // Parameters:
 //   - notes: The slice of notes to display
 //   - sort: The field by which to sort the notes (e.g., by name, date)
 //   - order: The order in which to sort (ascending or descending)
-func DisplayNotes(notes []models.Note, sort SortBy, order SortOrder) {
-	rowLineLength := calculateRowLineLength(notes)
-	longestFileNameLength := getLongestFileName(notes)
+//   - opts: The columns to include in the table and how to lay them out
+func DisplayNotes(notes []models.Note, sort SortBy, order SortOrder, opts DisplayOptions) {
+	notesTable := buildNotesTable(pinnedFirst(notes), opts)
 
-	printHeader(sort, order, rowLineLength)
-	printNotesTable(notes, rowLineLength, longestFileNameLength)
+	printHeader(sort, order, opts.Notebook, notesTable.Width())
+	notesTable.Render(os.Stdout, lineSymbol)
 }
 
-// Calculates and returns the length of the longest file name,
-// either the header -- "File Name" -- or the name of an actual
-// file. This is used for table-formatting purposes.
-func getLongestFileName(notes []models.Note) int {
-	longestName := len(headerFileName)
+// displayColumns returns the names of the columns to show for opts.
+func displayColumns(opts DisplayOptions) []string {
+	names := opts.Columns
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+	if opts.IDPrefixes != nil && !slices.Contains(names, ColumnID) {
+		names = append([]string{ColumnID}, names...)
+	}
+	if opts.ShowProgress && !slices.Contains(names, ColumnProgress) {
+		names = append(slices.Clip(names), ColumnProgress)
+	}
+	return names
+}
 
-	if len(notes) == 0 {
-		return longestName
+// buildNotesTable lays out notes in the columns chosen in opts.
+// Unknown column names are skipped; see ParseColumns.
+func buildNotesTable(notes []models.Note, opts DisplayOptions) table.Table {
+	var names []string
+	var shown []column
+	for _, name := range displayColumns(opts) {
+		if col, ok := columns[name]; ok {
+			names = append(names, name)
+			shown = append(shown, col)
+		}
+	}
+
+	notesTable := table.Table{
+		Separator: separator,
+		MaxWidth:  opts.MaxWidth,
+		Style: func(row, column int, text string) string {
+			return styleCell(names[column], row, notes, text)
+		},
+	}
+	for _, col := range shown {
+		notesTable.Columns = append(notesTable.Columns, col.Column)
 	}
 	for _, note := range notes {
-		if len(note.Title) > longestName {
-			longestName = len(note.Title)
+		row := make([]string, len(shown))
+		for i, col := range shown {
+			row[i] = col.value(note, opts)
 		}
+		notesTable.Rows = append(notesTable.Rows, row)
 	}
-	return longestName
+	return notesTable
 }
 
-// Calculates the length of the table row dashes for table-formatting purposes.
-func calculateRowLineLength(notes []models.Note) int {
-	fileNameWidth := max(len(headerFileName), getLongestFileName(notes))
-	return fileNameWidth + (dateTimeWidth * 2) + (len(separator) * 2)
+// styleCell colors a cell of the notes table: the header row,
+// the titles of pinned notes, and tags.
+func styleCell(name string, row int, notes []models.Note, text string) string {
+	switch {
+	case row < 0:
+		return output.Paint(output.Header, text)
+	case name == ColumnTitle && notes[row].Pinned:
+		return output.Paint(output.Pinned, text)
+	case name == ColumnTags:
+		return output.Paint(output.Tag, text)
+	}
+	return text
+}
+
+// formatProgress returns "done/total" for the checklist items in a note,
+// or noProgress if it has none.
+func formatProgress(note models.Note) string {
+	done, total := todo.Progress(note.Content)
+	if total == 0 {
+		return noProgress
+	}
+	return fmt.Sprintf("%d/%d", done, total)
 }
 
 // printHeader prints a formatted header for the notes list display.
@@ -70,43 +155,21 @@ func calculateRowLineLength(notes []models.Note) int {
 // Parameters:
 //   - sort: The field by which notes are sorted
 //   - order: The direction of the sort (ascending/descending)
+//   - notebook: The notebook being listed, if any
 //   - rowLineLength: Length of the decorative lines surrounding the header
-func printHeader(sort SortBy, order SortOrder, rowLineLength int) {
-	sortName := getSortString(sort)
-	orderName := getOrderString(sort, order)
-
-	rowLine := strings.Repeat(lineSymbol, rowLineLength)
-	header := fmt.Sprintf("Notes sorted by %s (%s)", sortName, orderName)
-
-	fmt.Printf("%s\n%s\n%s\n", rowLine, header, rowLine)
-}
-
-// printNotesTable displays a formatted table of notes with columns for
-// filename, creation date, and modification date.
-func printNotesTable(notes []models.Note, rowLineLength, longestFileNameLength int) {
-	// Create the divider line
+func printHeader(sort SortBy, order SortOrder, notebook string, rowLineLength int) {
 	rowLine := strings.Repeat(lineSymbol, rowLineLength)
-
-	// Print header row
-	fmt.Printf("%-*s%s%-*s%s%-*s\n",
-		longestFileNameLength, headerFileName,
-		separator, dateTimeWidth, headerCreated,
-		separator, dateTimeWidth, headerModified)
-
-	// Print divider after header
-	fmt.Println(rowLine)
-
-	// Print each note with the same formatting as the header
-	for _, note := range notes {
-		fmt.Printf("%-*s%s%-*s%s%-*s\n",
-			longestFileNameLength, note.Title,
-			separator, dateTimeWidth, formatDateTime(note.CreatedAt),
-			separator, dateTimeWidth, formatDateTime(note.ModifiedAt))
+	header := fmt.Sprintf("Notes sorted by %s", SortDescription(sort, order))
+	if notebook != "" {
+		header = fmt.Sprintf("Notes in %q sorted by %s", notebook, SortDescription(sort, order))
 	}
+
+	fmt.Printf("%s\n%s\n%s\n", rowLine, output.Paint(output.Header, header), rowLine)
 }
 
-func formatDateTime(dt time.Time) string {
-	return dt.Format(dateTimeFormat)
+// SortDescription describes how notes are sorted, e.g. "TITLE (A - Z)".
+func SortDescription(sort SortBy, order SortOrder) string {
+	return fmt.Sprintf("%s (%s)", getSortString(sort), getOrderString(sort, order))
 }
 
 // Returns a string describing how the data is ordered.
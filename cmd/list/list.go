@@ -3,10 +3,14 @@ package list
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/config"
+	"github.com/rhysmah/CLI-Note-App/dates"
 	"github.com/rhysmah/CLI-Note-App/db"
 	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/table"
 	"github.com/spf13/cobra"
 
 	bolt "go.etcd.io/bbolt"
@@ -15,10 +19,57 @@ import (
 const (
 	listCmdFull  = "list"
 	listCmdShort = "List all notes"
-	listCmdDesc  = "Display a list of all your notes."
+	listCmdDesc  = `Display a list of all your notes.
 
-	sortFlag  = "sort-by"
-	orderFlag = "reverse"
+Pinned notes are always listed first. Archived notes are hidden
+unless --archived or --all is passed.
+
+Dates are shown in local time as "Jan 02, 2006 15:04" by default.
+Use --date-format to show them as relative ("3h ago"), iso, rfc3339,
+or with a Go time layout, and --utc or --tz to pick a time zone.
+Both can also be set in the config file (~/.notes/config.json):
+
+  {
+    "date_format": "relative",
+    "timezone": "Europe/Paris"
+  }
+
+Use --columns to choose the columns and their order from: id, title,
+notebook, tags, created, modified, words and progress. Titles, notebooks
+and tags are shortened to fit the terminal unless --wide is passed.
+
+Example:
+  cli-note list --columns title,tags,modified,words,id`
+
+	sortFlag   = "sort-by"
+	orderFlag  = "reverse"
+	showIDFlag = "show-id"
+
+	showProgressFlag = "show-progress"
+	columnsFlag      = "columns"
+	wideFlag         = "wide"
+
+	allNotebooksFlag    = "all-notebooks"
+	groupByNotebookFlag = "group-by-notebook"
+
+	archivedFlag = "archived"
+	allFlag      = "all"
+
+	dateFormatFlag = "date-format"
+	utcFlag        = "utc"
+	timezoneFlag   = "tz"
+)
+
+// ArchiveFilter selects notes by whether they are archived.
+type ArchiveFilter int
+
+const (
+	// HideArchived selects notes that aren't archived.
+	HideArchived ArchiveFilter = iota
+	// OnlyArchived selects archived notes.
+	OnlyArchived
+	// IncludeArchived selects every note.
+	IncludeArchived
 )
 
 func init() {
@@ -35,34 +86,152 @@ func ListCommand() *cobra.Command {
 		Long:  listCmdDesc,
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			sort, _ := cmd.Flags().GetString(sortFlag)
-			sortBy := convertToSortBy(sort)
+			sortBy, orderBy := SortFromFlags(cmd)
 
-			order, _ := cmd.Flags().GetBool(orderFlag)
-			orderBy := convertToSortOrder(order)
-
-			notes, err := getNotes(root.NotesDB)
+			notes, err := loadNotes()
 			if err != nil {
 				return fmt.Errorf("error opening database")
 			}
+			allNotes := notes
+			notes = FilterArchived(notes, ArchiveFilterFromFlags(cmd))
+
+			dateFormatter, err := DateFormatterFromFlags(cmd, root.Config)
+			if err != nil {
+				return err
+			}
+
+			columnList, _ := cmd.Flags().GetString(columnsFlag)
+			shownColumns, err := ParseColumns(columnList)
+			if err != nil {
+				return err
+			}
+
+			opts := DisplayOptions{Columns: shownColumns, Dates: dateFormatter}
+			if wide, _ := cmd.Flags().GetBool(wideFlag); !wide {
+				opts.MaxWidth = table.TerminalWidth()
+			}
+			if showID, _ := cmd.Flags().GetBool(showIDFlag); showID || slices.Contains(shownColumns, ColumnID) {
+				// Prefixes must be unique across all notes, not just the listed ones
+				opts.IDPrefixes = idPrefixes(allNotes)
+			}
+
+			opts.ShowProgress, _ = cmd.Flags().GetBool(showProgressFlag)
+
+			allNotebooks, _ := cmd.Flags().GetBool(allNotebooksFlag)
+			groupByNotebook, _ := cmd.Flags().GetBool(groupByNotebookFlag)
+
+			if groupByNotebook {
+				for i, group := range groupNotesByNotebook(notes) {
+					if i > 0 {
+						fmt.Println()
+					}
+					opts.Notebook = group[0].Notebook
+					SortNotes(group, sortBy, orderBy)
+					DisplayNotes(group, sortBy, orderBy, opts)
+				}
+				return nil
+			}
+
+			if !allNotebooks {
+				notes = filterByNotebook(notes, root.ActiveNotebook)
+				opts.Notebook = root.ActiveNotebook
+			}
 
 			if len(notes) == 0 {
 				fmt.Println("You have no notes")
 				return nil
 			}
 
-			sortNotes(notes, sortBy, orderBy)
-			DisplayNotes(notes, sortBy, orderBy)
+			SortNotes(notes, sortBy, orderBy)
+			DisplayNotes(notes, sortBy, orderBy, opts)
 
 			return nil
 		},
 	}
 
-	// TODO: create constants for arguments
+	AddSortFlags(cmd)
+	cmd.Flags().Bool(showIDFlag, false, "Show the shortest unique ID prefix of each note")
+	cmd.Flags().Bool(showProgressFlag, false, "Show done/total checklist items of each note")
+	cmd.Flags().StringP(columnsFlag, "c", "", "Comma-separated columns to show (default \"title,created,modified\")")
+	cmd.Flags().BoolP(wideFlag, "w", false, "Don't shorten columns to fit the terminal")
+	cmd.Flags().Bool(allNotebooksFlag, false, "List notes from every notebook")
+	cmd.Flags().Bool(groupByNotebookFlag, false, "List notes from every notebook, grouped by notebook")
+	AddArchiveFlags(cmd)
+	AddDateFlags(cmd)
+	root.UseServer(cmd)
+
+	return cmd
+}
+
+// AddSortFlags adds the --sort-by and --reverse flags, which choose the order of notes.
+func AddSortFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP(sortFlag, "s", "modified", "Sort by: title, created, modified")
 	cmd.Flags().BoolP(orderFlag, "r", false, "Reverse the sort order")
+}
 
-	return cmd
+// SortFromFlags returns the sort field and order chosen with the flags added by AddSortFlags.
+func SortFromFlags(cmd *cobra.Command) (SortBy, SortOrder) {
+	sort, _ := cmd.Flags().GetString(sortFlag)
+	reverse, _ := cmd.Flags().GetBool(orderFlag)
+	return convertToSortBy(sort), convertToSortOrder(reverse)
+}
+
+// AddArchiveFlags adds the --archived and --all flags, which show archived notes.
+func AddArchiveFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(archivedFlag, false, "Only show archived notes")
+	cmd.Flags().Bool(allFlag, false, "Show archived notes as well")
+}
+
+// ArchiveFilterFromFlags returns the ArchiveFilter chosen with the flags added by AddArchiveFlags.
+func ArchiveFilterFromFlags(cmd *cobra.Command) ArchiveFilter {
+	if all, _ := cmd.Flags().GetBool(allFlag); all {
+		return IncludeArchived
+	}
+	if archived, _ := cmd.Flags().GetBool(archivedFlag); archived {
+		return OnlyArchived
+	}
+	return HideArchived
+}
+
+// AddDateFlags adds the --date-format, --utc and --tz flags, which control how dates are shown.
+func AddDateFlags(cmd *cobra.Command) {
+	cmd.Flags().String(dateFormatFlag, "", "Show dates as: relative, iso, rfc3339, or a Go time layout")
+	cmd.Flags().Bool(utcFlag, false, "Show dates in UTC")
+	cmd.Flags().String(timezoneFlag, "", "Show dates in this time zone, e.g. Europe/Paris")
+	cmd.MarkFlagsMutuallyExclusive(utcFlag, timezoneFlag)
+}
+
+// DateFormatterFromFlags returns the dates.Formatter chosen with the flags
+// added by AddDateFlags. Flags that aren't set fall back to the config.
+func DateFormatterFromFlags(cmd *cobra.Command, cfg config.Config) (dates.Formatter, error) {
+	format := cfg.DateFormat
+	if cmd.Flags().Changed(dateFormatFlag) {
+		format, _ = cmd.Flags().GetString(dateFormatFlag)
+	}
+
+	timezone := cfg.Timezone
+	if cmd.Flags().Changed(timezoneFlag) {
+		timezone, _ = cmd.Flags().GetString(timezoneFlag)
+	}
+	if utc, _ := cmd.Flags().GetBool(utcFlag); utc {
+		timezone = "UTC"
+	}
+
+	return dates.NewFormatter(format, timezone)
+}
+
+// FilterArchived returns the notes selected by filter.
+func FilterArchived(notes []models.Note, filter ArchiveFilter) []models.Note {
+	if filter == IncludeArchived {
+		return notes
+	}
+	var filtered []models.Note
+	for _, note := range notes {
+		if note.Archived == (filter == OnlyArchived) {
+			filtered = append(filtered, note)
+		}
+	}
+	return filtered
 }
 
 func convertToSortBy(sort string) SortBy {
@@ -89,6 +258,57 @@ func convertToSortOrder(reverse bool) SortOrder {
 	}
 }
 
+// filterByNotebook returns the notes that belong to the given notebook.
+func filterByNotebook(notes []models.Note, notebook string) []models.Note {
+	var filtered []models.Note
+	for _, note := range notes {
+		if db.NormalizeTitle(note.Notebook) == db.NormalizeTitle(notebook) {
+			filtered = append(filtered, note)
+		}
+	}
+	return filtered
+}
+
+// groupNotesByNotebook splits notes into one group per notebook,
+// ordered by notebook name. Every group contains at least one note.
+func groupNotesByNotebook(notes []models.Note) [][]models.Note {
+	groups := make(map[string][]models.Note)
+	for _, note := range notes {
+		key := db.NormalizeTitle(note.Notebook)
+		groups[key] = append(groups[key], note)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	grouped := make([][]models.Note, 0, len(keys))
+	for _, key := range keys {
+		grouped = append(grouped, groups[key])
+	}
+	return grouped
+}
+
+// idPrefixes returns the shortest unique ID prefix of every note,
+// which can be used in place of a title in other commands.
+func idPrefixes(notes []models.Note) map[string]string {
+	ids := make([]string, 0, len(notes))
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	return db.ShortestUniquePrefixes(ids)
+}
+
+// loadNotes returns every note, through the server if one owns the database.
+func loadNotes() ([]models.Note, error) {
+	if root.Server != nil {
+		return root.Server.Notes("")
+	}
+	return getNotes(root.NotesDB)
+}
+
 func getNotes(database *bolt.DB) ([]models.Note, error) {
 	var notes []models.Note
 
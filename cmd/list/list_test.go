@@ -0,0 +1,155 @@
+package list
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/config"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+func noteTitles(notes []models.Note) []string {
+	titles := make([]string, 0, len(notes))
+	for _, note := range notes {
+		titles = append(titles, note.Title)
+	}
+	return titles
+}
+
+func TestPinnedNotesSortFirst(t *testing.T) {
+	now := time.Now()
+	notes := []models.Note{
+		{Title: "a", ModifiedAt: now},
+		{Title: "b", ModifiedAt: now.Add(-time.Hour), Pinned: true},
+		{Title: "c", ModifiedAt: now.Add(-2 * time.Hour)},
+		{Title: "d", ModifiedAt: now.Add(-3 * time.Hour), Pinned: true},
+	}
+
+	for _, sortBy := range []SortBy{SortByTitle, SortByModified} {
+		SortNotes(notes, sortBy, SortOrderAscending)
+		got := noteTitles(pinnedFirst(notes))
+		want := []string{"b", "d", "a", "c"}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Sorted by %s: got %v; want %v", sortBy, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestFilterArchived(t *testing.T) {
+	notes := []models.Note{
+		{Title: "active"},
+		{Title: "archived", Archived: true},
+	}
+
+	tests := []struct {
+		name   string
+		filter ArchiveFilter
+		want   int
+	}{
+		{name: "Hide Archived", filter: HideArchived, want: 1},
+		{name: "Only Archived", filter: OnlyArchived, want: 1},
+		{name: "Include Archived", filter: IncludeArchived, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterArchived(notes, tt.filter)
+			if len(got) != tt.want {
+				t.Fatalf("Got %v; want %d notes", noteTitles(got), tt.want)
+			}
+			if tt.filter == OnlyArchived && !got[0].Archived {
+				t.Errorf("Expected only archived notes; got %v", noteTitles(got))
+			}
+			if tt.filter == HideArchived && got[0].Archived {
+				t.Errorf("Expected no archived notes; got %v", noteTitles(got))
+			}
+		})
+	}
+}
+
+func TestDateFormatterFromFlags(t *testing.T) {
+	at := time.Date(2026, time.October, 19, 13, 4, 0, 0, time.UTC)
+	cfg := config.Config{DateFormat: "iso", Timezone: "Asia/Tokyo"}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "Config", args: nil, want: "2026-10-19 22:04"},
+		{name: "UTC Flag", args: []string{"--utc"}, want: "2026-10-19 13:04"},
+		{name: "Format Flag", args: []string{"--date-format", "rfc3339", "--tz", "UTC"}, want: "2026-10-19T13:04:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := ListCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+			formatter, err := DateFormatterFromFlags(cmd, cfg)
+			if err != nil {
+				t.Fatalf("Failed to build date formatter: %v", err)
+			}
+			if got := formatter.Format(at); got != tt.want {
+				t.Errorf("Format() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateColumnWidth(t *testing.T) {
+	notes := []models.Note{{Title: "a", CreatedAt: time.Now(), ModifiedAt: time.Now()}}
+
+	widths := buildNotesTable(notes, DisplayOptions{}).Widths()
+	if widths[1] != len("Oct 19, 2026 13:04") {
+		t.Errorf("Default date width = %d; want %d", widths[1], len("Oct 19, 2026 13:04"))
+	}
+
+	relative := DisplayOptions{Dates: dates.Formatter{Relative: true}}
+	widths = buildNotesTable(notes, relative).Widths()
+	if widths[2] != len(headerModified) {
+		t.Errorf("Relative date width = %d; want the header width %d", widths[2], len(headerModified))
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	got, err := ParseColumns(" Title, tags,modified,words,id,title")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+	want := []string{ColumnTitle, ColumnTags, ColumnModified, ColumnWords, ColumnID}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseColumns() = %v; want %v", got, want)
+	}
+
+	if got, _ := ParseColumns(""); !slices.Equal(got, DefaultColumns) {
+		t.Errorf("ParseColumns(\"\") = %v; want %v", got, DefaultColumns)
+	}
+	if _, err := ParseColumns("title,size"); err == nil {
+		t.Error("Expected error for an unknown column")
+	}
+}
+
+func TestDisplayColumns(t *testing.T) {
+	opts := DisplayOptions{
+		Columns:      []string{ColumnTitle, ColumnTags},
+		IDPrefixes:   map[string]string{},
+		ShowProgress: true,
+	}
+	want := []string{ColumnID, ColumnTitle, ColumnTags, ColumnProgress}
+	if got := displayColumns(opts); !slices.Equal(got, want) {
+		t.Errorf("displayColumns() = %v; want %v", got, want)
+	}
+
+	notes := []models.Note{{Title: "Quarterly Planning", Tags: []string{"work", "planning"}}}
+	opts = DisplayOptions{Columns: []string{ColumnTitle, ColumnTags}, MaxWidth: 30}
+	if got := buildNotesTable(notes, opts).Width(); got > 30 {
+		t.Errorf("Table width = %d; want at most 30", got)
+	}
+}
@@ -6,9 +6,9 @@ import (
 	"github.com/rhysmah/CLI-Note-App/models"
 )
 
-// sortFiles sorts a slice of files based on the specified field and order.
+// SortNotes sorts a slice of files based on the specified field and order.
 // It uses the compareFiles function to determine the ordering between any two files.
-func sortNotes(notes []models.Note, field SortBy, order SortOrder) {
+func SortNotes(notes []models.Note, field SortBy, order SortOrder) {
 	sort.Slice(notes, func(a, b int) bool {
 		return compareNotes(notes[a], notes[b], field, order)
 	})
@@ -43,3 +43,20 @@ func compareNotes(a, b models.Note, field SortBy, order SortOrder) bool {
 		return a.Title < b.Title // A - Z
 	}
 }
+
+// pinnedFirst returns the notes with pinned notes moved to the front.
+// The order within pinned and unpinned notes is kept.
+func pinnedFirst(notes []models.Note) []models.Note {
+	ordered := make([]models.Note, 0, len(notes))
+	for _, note := range notes {
+		if note.Pinned {
+			ordered = append(ordered, note)
+		}
+	}
+	for _, note := range notes {
+		if !note.Pinned {
+			ordered = append(ordered, note)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,65 @@
+package lsp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/lsp"
+	"github.com/spf13/cobra"
+)
+
+const (
+	lspCmdFull  = "lsp"
+	lspCmdShort = "Run a language server for [[link]] completion in editors"
+	lspCmdDesc  = `Run a Language Server Protocol server on standard input and output,
+for editors to help you write notes in Markdown files:
+
+  - completion of [[note titles]] and #tags
+  - go to definition on a [[link]] opens the linked note; saving the
+    file updates the note, unless it was changed elsewhere meanwhile
+  - hovering over a [[link]] previews the linked note
+  - warnings for dangling links, to notes that don't exist
+
+Linked notes are opened as files under --cache-dir. Links in other files
+refer to notes in the active notebook (see --notebook).
+
+Configure your editor to start "cli-note lsp" for Markdown files. In Neovim:
+  vim.lsp.start({ name = "cli-note", cmd = { "cli-note", "lsp" } })`
+
+	cacheDirFlag = "cache-dir"
+)
+
+// init registers the lsp command with the root command.
+func init() {
+	lspCommand := LSPCommand()
+	root.RootCmd.AddCommand(lspCommand)
+}
+
+// LSPCommand creates and returns a cobra.Command for running the language server.
+func LSPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   lspCmdFull,
+		Short: lspCmdShort,
+		Long:  lspCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cacheDir, _ := cmd.Flags().GetString(cacheDirFlag)
+			if cacheDir == "" {
+				userCacheDir, err := os.UserCacheDir()
+				if err != nil {
+					return fmt.Errorf("error finding cache directory: %w", err)
+				}
+				cacheDir = filepath.Join(userCacheDir, "cli-note", "notes")
+			}
+
+			return lsp.NewServer(root.Store(), root.ActiveNotebook, cacheDir).Serve(cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+	root.UseServer(cmd)
+
+	cmd.Flags().String(cacheDirFlag, "", "Directory to open linked notes in (defaults to the user cache directory)")
+
+	return cmd
+}
@@ -0,0 +1,157 @@
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/mirror"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	mirrorCmdFull  = "mirror <directory>"
+	mirrorCmdShort = "Mirror notes as Markdown files in a directory"
+	mirrorCmdDesc  = `Write every note to a directory as a Markdown file, <notebook>/<title>.md,
+so that you can use grep, ripgrep and any editor on your notes. Run
+'cli-note mirror sync' to bring changes made on either side to the other;
+the notes database remains the source of truth.
+
+Files already in the directory are created as notes. The directory is
+remembered, so 'mirror sync' needs no argument afterwards.
+
+Examples:
+  cli-note mirror ~/notes-mirror
+  rg budget ~/notes-mirror
+  cli-note mirror sync`
+
+	syncCmdFull  = "sync [directory]"
+	syncCmdShort = "Sync changes between notes and their mirror"
+	syncCmdDesc  = `Apply the changes made since the last sync, on either side:
+
+  - notes changed, renamed or moved since are written to their files
+  - files edited since are imported into their notes
+  - new .md files are created as notes: at the top of the directory in
+    the active notebook, in a notebook's directory in that notebook
+  - deleting a note removes its file
+  - a removed file is written again from its note, unless --delete is
+    given, in which case its note is deleted
+
+If a note and its file both changed, the note wins: its file is rewritten,
+and your edits are kept next to it in <title>.conflict-<time>.md. Conflict
+copies are never imported; merge them by hand and delete them.
+
+Without a directory, the one given to 'cli-note mirror' is synced.`
+
+	deleteFlag = "delete"
+)
+
+// init registers the mirror command with the root command.
+func init() {
+	mirrorCommand := MirrorCommand()
+	root.RootCmd.AddCommand(mirrorCommand)
+}
+
+// MirrorCommand creates and returns a cobra.Command for mirroring notes as files.
+func MirrorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   mirrorCmdFull,
+		Short: mirrorCmdShort,
+		Long:  mirrorCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("error resolving %s: %w", args[0], err)
+			}
+
+			changes, err := newMirror(dir).Init()
+			printChanges(changes)
+			if errors.Is(err, mirror.ErrAlreadyMirror) {
+				return fmt.Errorf("%s is already a mirror; run 'cli-note mirror sync %s' to sync it", dir, args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("error creating mirror: %w", err)
+			}
+
+			root.Config.MirrorDirectory = dir
+			if err := root.Config.Save(root.ConfigPath); err != nil {
+				return fmt.Errorf("error remembering mirror directory: %w", err)
+			}
+			fmt.Println(output.Sprintf(output.Success, "Mirrored notes to %s", dir))
+			fmt.Println("Run 'cli-note mirror sync' to sync changes made on either side.")
+			return nil
+		},
+	}
+	root.UseServer(cmd)
+
+	cmd.AddCommand(syncCommand())
+	return cmd
+}
+
+func syncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   syncCmdFull,
+		Short: syncCmdShort,
+		Long:  syncCmdDesc,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := root.Config.MirrorDirectory
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			if dir == "" {
+				return errors.New("no mirror directory; create one with 'cli-note mirror <directory>'")
+			}
+
+			deleteMissing, _ := cmd.Flags().GetBool(deleteFlag)
+
+			changes, err := newMirror(dir).Sync(mirror.SyncOptions{DeleteMissing: deleteMissing})
+			printChanges(changes)
+			if errors.Is(err, mirror.ErrNotMirror) {
+				return fmt.Errorf("%s is not a mirror; create one with 'cli-note mirror %s'", dir, dir)
+			}
+			if err != nil {
+				return fmt.Errorf("error syncing mirror: %w", err)
+			}
+
+			if len(changes) == 0 {
+				fmt.Println("Mirror is up to date.")
+				return nil
+			}
+			fmt.Println(output.Sprintf(output.Success, "Synced %s with %s", output.Pluralize(len(changes), "change"), dir))
+			return nil
+		},
+	}
+	root.UseServer(cmd)
+
+	cmd.Flags().Bool(deleteFlag, false, "Delete the notes whose files were removed from the mirror")
+	return cmd
+}
+
+// newMirror returns the mirror in dir, over the server if it is running.
+func newMirror(dir string) *mirror.Mirror {
+	return mirror.New(dir, root.Store(), root.ActiveNotebook)
+}
+
+// printChanges prints what a sync did, conflicts, restored and skipped
+// files first.
+func printChanges(changes []mirror.Change) {
+	for _, change := range changes {
+		if needsAttention(change) {
+			fmt.Println(output.Paint(output.Warning, change.String()))
+		}
+	}
+	for _, change := range changes {
+		if !needsAttention(change) {
+			fmt.Println(change)
+		}
+	}
+}
+
+// needsAttention reports whether a change may not be what the user expected.
+func needsAttention(change mirror.Change) bool {
+	return change.Kind == mirror.Conflict || change.Kind == mirror.Restored || change.Kind == mirror.Skipped
+}
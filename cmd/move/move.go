@@ -0,0 +1,87 @@
+package move
+
+import (
+	"fmt"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	moveCmdFull  = "move <title|id-prefix>"
+	moveCmdShort = "Move a note to another notebook"
+	moveCmdDesc  = `Move a note from the current notebook to another notebook.
+
+The target notebook must not already contain a note with the same title.
+[[Links]] to the note are rewritten to name its new notebook, and links in
+the note without a notebook are given its old one, so they keep pointing at
+the same notes.
+
+Example:
+  cli-note move "Standup" --to work`
+
+	toFlag = "to"
+)
+
+// init registers the move command with the root command.
+func init() {
+	moveCommand := MoveCommand()
+	root.RootCmd.AddCommand(moveCommand)
+}
+
+// MoveCommand creates and returns a cobra.Command for moving notes between notebooks.
+// The command requires exactly one argument: the title or ID prefix of the note to move.
+func MoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   moveCmdFull,
+		Short: moveCmdShort,
+		Long:  moveCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, _ := cmd.Flags().GetString(toFlag)
+
+			note, rewritten, err := moveNote(args[0], root.ActiveNotebook, target, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(output.Sprintf(output.Success, "Note %q moved to notebook %q", note.Title, note.Notebook))
+			if len(rewritten) > 0 {
+				fmt.Printf("Updated links in %s\n", output.Pluralize(len(rewritten), "note"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(toFlag, "", "Notebook to move the note to")
+	cmd.MarkFlagRequired(toFlag)
+
+	return cmd
+}
+
+// moveNote moves the note identified by handle in notebook to the target
+// notebook. It returns the moved note and the notes whose links were rewritten.
+func moveNote(handle, notebook, target string, database *bolt.DB) (models.Note, []models.Note, error) {
+	var moved models.Note
+	var rewritten []models.Note
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		moved, rewritten, err = db.MoveNote(tx, note, target)
+		if err != nil {
+			return fmt.Errorf("error moving note %q: %w", note.Title, err)
+		}
+		return nil
+	})
+
+	return moved, rewritten, err
+}
@@ -0,0 +1,66 @@
+package move
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// storeNoteIn adds a test note titled title to notebook and returns it.
+func storeNoteIn(t *testing.T, database *bolt.DB, notebook, title string) models.Note {
+	t.Helper()
+	note := testutil.CreateTestNote()
+	note.Title = title
+	note.Notebook = notebook
+	if err := new.StoreNoteInDB(note, database); err != nil {
+		t.Fatalf("Error adding note to database: %v", err)
+	}
+	return note
+}
+
+func TestMoveNote(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+	err := testDB.Update(func(tx *bolt.Tx) error {
+		_, err := db.CreateNotebook(tx, "work")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Error creating notebook: %v", err)
+	}
+	note := storeNoteIn(t, testDB, db.DefaultNotebook, "standup")
+	storeNoteIn(t, testDB, db.DefaultNotebook, "plan")
+	storeNoteIn(t, testDB, "work", "plan")
+
+	moved, _, err := moveNote("standup", db.DefaultNotebook, "work", testDB)
+	if err != nil {
+		t.Fatalf("moveNote() error = %v", err)
+	}
+	if moved.ID != note.ID || moved.Notebook != "work" {
+		t.Errorf("Moved note = %+v; want note %s in notebook work", moved, note.ID)
+	}
+
+	// The target notebook already has a note titled "plan"
+	if _, _, err := moveNote("plan", db.DefaultNotebook, "work", testDB); err == nil {
+		t.Error("Expected error moving a note onto a clashing title")
+	}
+	if _, _, err := moveNote("plan", db.DefaultNotebook, "missing", testDB); !errors.Is(err, db.ErrNotebookNotFound) {
+		t.Errorf("moveNote() to a missing notebook error = %v; want ErrNotebookNotFound", err)
+	}
+	if _, _, err := moveNote("standup", db.DefaultNotebook, "work", testDB); !errors.Is(err, db.ErrNoteNotFound) {
+		t.Errorf("moveNote() of a moved note error = %v; want ErrNoteNotFound", err)
+	}
+
+	err = testDB.View(func(tx *bolt.Tx) error {
+		_, err := db.LookupNote(tx, db.DefaultNotebook, "plan")
+		return err
+	})
+	if err != nil {
+		t.Errorf("Expected the clashing note to stay in its notebook; got %v", err)
+	}
+}
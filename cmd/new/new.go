@@ -1,15 +1,18 @@
 package new
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/rhysmah/CLI-Note-App/api"
 	"github.com/rhysmah/CLI-Note-App/cmd/root"
 	"github.com/rhysmah/CLI-Note-App/db"
 	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/rhysmah/CLI-Note-App/templates"
 	"github.com/spf13/cobra"
 
 	bolt "go.etcd.io/bbolt"
@@ -20,7 +23,13 @@ const (
 	createCmdShort = "Create a new note"
 	createCmdDesc  = `Create a new note with the specified name.
 The note will be saved as '[note-name]_[date].txt' in your notes directory.
-Note names cannot contain special characters or exceed 50 characters.`
+Note names cannot contain special characters or exceed 50 characters.
+
+Use --template to start the note from a template (see 'cli-note template'),
+and --var key=value to set custom template variables.`
+
+	templateFlag = "template"
+	varFlag      = "var"
 )
 
 // init registers the new note command with the root command.
@@ -40,38 +49,92 @@ func NewCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			noteTitle := args[0]
 
-			exists, err := checkIfNoteExists(noteTitle, root.NotesDB)
+			if root.Server != nil {
+				if templateName, _ := cmd.Flags().GetString(templateFlag); templateName != "" {
+					return errors.New("templates can't be used while 'cli-note serve' owns the database; stop the server first")
+				}
+				return createThroughServer(noteTitle, root.ActiveNotebook, root.Server)
+			}
+
+			exists, err := checkIfNoteExists(noteTitle, root.ActiveNotebook, root.NotesDB)
 			if err != nil {
 				return fmt.Errorf("error checking if note already exists: %w", err)
 			}
 			if exists {
-				return fmt.Errorf("note %q already exists!\nPlease choose another name for your note", noteTitle)
+				return fmt.Errorf("note %q already exists in notebook %q!\nPlease choose another name for your note", noteTitle, root.ActiveNotebook)
 			}
 
-			note, err := createNote(args[0])
+			note, err := CreateNote(args[0], root.ActiveNotebook)
 			if err != nil {
 				return fmt.Errorf("error creating note: %w", err)
 			}
 
+			if templateName, _ := cmd.Flags().GetString(templateFlag); templateName != "" {
+				pairs, _ := cmd.Flags().GetStringArray(varFlag)
+				vars, err := templates.ParseVars(pairs)
+				if err != nil {
+					return err
+				}
+				if note.Content, err = renderTemplate(templateName, note, vars, root.NotesDB); err != nil {
+					return fmt.Errorf("error applying template %q: %w", templateName, err)
+				}
+			}
+
 			if err = StoreNoteInDB(note, root.NotesDB); err != nil {
 				return fmt.Errorf("error saving note to database: %w", err)
 			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringP(templateFlag, "t", "", "Create the note from this template")
+	cmd.Flags().StringArray(varFlag, nil, "Set a template variable as key=value (repeatable)")
+	root.UseServer(cmd)
+
 	return cmd
 }
 
-func checkIfNoteExists(title string, database *bolt.DB) (bool, error) {
+// createThroughServer creates an empty note through the server that owns the database.
+func createThroughServer(title, notebook string, store api.Store) error {
+	note, err := store.CreateNote(api.NoteInput{Title: &title, Notebook: &notebook})
+	if errors.Is(err, api.ErrConflict) {
+		return fmt.Errorf("note %q already exists in notebook %q!\nPlease choose another name for your note", title, notebook)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating note: %w", err)
+	}
+	announceNote(note)
+	return nil
+}
+
+// renderTemplate renders the named template for the given note.
+func renderTemplate(name string, note models.Note, vars map[string]string, database *bolt.DB) (string, error) {
+	var template models.Template
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		template, err = db.GetTemplate(tx, name)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	variables := templates.Variables(note.Title, note.Notebook, note.CreatedAt, vars)
+	return templates.Render(template.Content, variables)
+}
+
+// checkIfNoteExists reports whether a note with an equivalent title already exists in the notebook.
+// Titles are compared in their normalized form, so "Groceries" and "groceries" clash.
+func checkIfNoteExists(title, notebook string, database *bolt.DB) (bool, error) {
 	var exists bool
 	err := database.View(func(tx *bolt.Tx) error {
 
-		bucket := tx.Bucket([]byte(db.NotesTitleBucket))
-		if bucket == nil {
-			return fmt.Errorf("error finding bucket %q", db.NotesTitleBucket)
+		bucket, err := db.NotebookTitles(tx, notebook)
+		if err != nil {
+			return err
 		}
 
-		val := bucket.Get([]byte(title))
+		val := bucket.Get(db.TitleKey(title))
 		exists = val != nil
 		return nil
 
@@ -79,11 +142,13 @@ func checkIfNoteExists(title string, database *bolt.DB) (bool, error) {
 	return exists, err
 }
 
-// createNote instantiates a new Note with the given title and validates it.
-func createNote(title string) (models.Note, error) {
+// CreateNote instantiates a new Note with the given title in the given notebook and validates it.
+// The note is not stored; see StoreNoteInDB.
+func CreateNote(title, notebook string) (models.Note, error) {
 	newNote := models.Note{
 		ID:         uuid.New().String(),
 		Title:      title,
+		Notebook:   notebook,
 		Content:    "",
 		CreatedAt:  time.Now(),
 		ModifiedAt: time.Now(),
@@ -100,49 +165,19 @@ func createNote(title string) (models.Note, error) {
 // It marshals the note to JSON and stores it using the note's ID as the key.
 func StoreNoteInDB(note models.Note, database *bolt.DB) error {
 	return database.Update(func(tx *bolt.Tx) error {
-		if err := StoreNoteContent(tx, note); err != nil {
+		if err := db.PutNote(tx, note); err != nil {
 			return fmt.Errorf("error storing note %q in database: %w", note.Title, err)
 		}
-		if err := StoreNoteTitle(tx, note); err != nil {
+		if err := db.PutNoteTitle(tx, note); err != nil {
 			return fmt.Errorf("error storing note %q in database: %w", note.Title, err)
 		}
-		fmt.Printf("Note %q successfully added to database!\nUse 'cli-note edit %s' to open your default text editor and start writing!\n", note.Title, note.Title)
+		announceNote(note)
 		return nil
 	})
 }
 
-// StoreNoteContent saves a note to the database within an existing transaction.
-// It marshals the note to JSON and stores it using the note's ID as the key.
-// Returns an error if the bucket doesn't exist, JSON marshaling fails, or the database operation fails.
-func StoreNoteContent(tx *bolt.Tx, note models.Note) error {
-	bucket := tx.Bucket([]byte(db.NotesBucket))
-
-	if bucket == nil {
-		return fmt.Errorf("bucket %s does not exist", db.NotesBucket)
-	}
-	noteJSON, err := json.Marshal(note)
-	if err != nil {
-		return fmt.Errorf("failed to marshal note as JSON: %w", err)
-	}
-	err = bucket.Put([]byte(note.ID), noteJSON)
-	if err != nil {
-		return fmt.Errorf("failed to store note in database %q", db.NotesBucket)
-	}
-	return nil
-}
-
-// StoreNoteTitle stores a mapping from note title to note ID in the titles bucket.
-// It allows notes to be looked up by their titles.
-// The function expects to be called within an existing bolt transaction.
-func StoreNoteTitle(tx *bolt.Tx, note models.Note) error {
-	bucket := tx.Bucket([]byte(db.NotesTitleBucket))
-
-	if bucket == nil {
-		return fmt.Errorf("failed to marshal note as JSON: %s", db.NotesTitleBucket)
-	}
-	err := bucket.Put([]byte(note.Title), []byte(note.ID))
-	if err != nil {
-		return fmt.Errorf("failed to store title in database %q", db.NotesBucket)
-	}
-	return nil
+// announceNote tells the user a note was created and how to start writing it.
+func announceNote(note models.Note) {
+	fmt.Println(output.Sprintf(output.Success, "Note %q successfully added to database!", note.Title))
+	fmt.Printf("Use 'cli-note edit %s' to open your default text editor and start writing!\n", note.Title)
 }
@@ -9,13 +9,15 @@ import (
 	"time"
 
 	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
 	"github.com/rhysmah/CLI-Note-App/testutil"
 
 	bolt "go.etcd.io/bbolt"
 )
 
 func TestNewNote(t *testing.T) {
-	note, err := createNote(testutil.TestValidNoteTitle)
+	note, err := CreateNote(testutil.TestValidNoteTitle, db.DefaultNotebook)
 	if err != nil {
 		t.Errorf("Couldn't create note: %v", err)
 	}
@@ -41,7 +43,7 @@ func TestNewNote(t *testing.T) {
 }
 
 func TestInvalidNote(t *testing.T) {
-	_, err := createNote(testutil.TestInvalidNoteTitle)
+	_, err := CreateNote(testutil.TestInvalidNoteTitle, db.DefaultNotebook)
 	if err == nil {
 		t.Errorf("Note title invalid; should have thrown error; got nil")
 	}
@@ -132,3 +134,75 @@ func TestPreventDuplicateNoteInsertion(t *testing.T) {
 		t.Errorf("Should have mentioned error about note existing: %v", err)
 	}
 }
+
+func TestPreventDuplicateNoteInsertionIgnoringCase(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	originalDB := root.NotesDB
+	root.NotesDB = testDB
+
+	t.Cleanup(func() {
+		root.NotesDB = originalDB
+	})
+
+	newCmd := NewCommand()
+	newCmd.SetArgs([]string{"Caf\u00e9"})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("Failed to create note: %v", err)
+	}
+
+	// Same title, different case and Unicode normalization
+	newCmd = NewCommand()
+	newCmd.SetArgs([]string{"CAFE\u0301"})
+	err := newCmd.Execute()
+
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Should have received duplicate note error; got %v", err)
+	}
+}
+
+func TestNewNoteFromTemplate(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	originalDB := root.NotesDB
+	root.NotesDB = testDB
+
+	t.Cleanup(func() {
+		root.NotesDB = originalDB
+	})
+
+	err := testDB.Update(func(tx *bolt.Tx) error {
+		return db.PutTemplate(tx, models.Template{
+			Name:    "meeting",
+			Content: "# {{.Title}}\nClient: {{.client}}",
+		})
+	})
+	if err != nil {
+		t.Fatalf("Failed to store template: %v", err)
+	}
+
+	newCmd := NewCommand()
+	newCmd.SetArgs([]string{"Kickoff", "--template", "Meeting", "--var", "client=ACME"})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("Failed to create note from template: %v", err)
+	}
+
+	var note models.Note
+	err = testDB.View(func(tx *bolt.Tx) error {
+		note, err = db.LookupNote(tx, db.DefaultNotebook, "Kickoff")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to retrieve note: %v", err)
+	}
+	if want := "# Kickoff\nClient: ACME"; note.Content != want {
+		t.Errorf("Note content = %q; want %q", note.Content, want)
+	}
+
+	// A template variable without a value is an error
+	newCmd = NewCommand()
+	newCmd.SetArgs([]string{"Other", "--template", "meeting"})
+	if err := newCmd.Execute(); err == nil {
+		t.Error("Expected error for missing template variable; got nil")
+	}
+}
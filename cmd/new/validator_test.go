@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/validator"
 )
 
 func TestValidateNoteTitleLength(t *testing.T) {
@@ -25,12 +26,22 @@ func TestValidateNoteTitleLength(t *testing.T) {
 		},
 		{
 			name:      "Title At Max Length",
-			noteTitle: strings.Repeat("a", noteNameMaxLimit),
+			noteTitle: strings.Repeat("a", validator.MaxNameLength),
+			wantErr:   false,
+		},
+		{
+			name:      "Accented Title At Max Length",
+			noteTitle: strings.Repeat("\u00e9", validator.MaxNameLength),
+			wantErr:   false,
+		},
+		{
+			name:      "Decomposed Accented Title At Max Length",
+			noteTitle: strings.Repeat("e\u0301", validator.MaxNameLength),
 			wantErr:   false,
 		},
 		{
 			name:      "Title Great Than Max Length",
-			noteTitle: strings.Repeat("a", noteNameMaxLimit*2),
+			noteTitle: strings.Repeat("a", validator.MaxNameLength*2),
 			wantErr:   true,
 		},
 		{
@@ -130,12 +141,12 @@ func TestValidateNoteTitleCharacters(t *testing.T) {
 }
 
 func TestNoteValidator(t *testing.T) {
-	validator := newValidator()
+	noteValidator := newValidator()
 
 	// Test that validator has x number of rules.
 	// As of [04-03-2025]: 2 rules.
-	if len(validator.Rules) != 2 {
-		t.Errorf("Validator has %d rules; expected 2", len(validator.Rules))
+	if len(noteValidator.Rules) != 2 {
+		t.Errorf("Validator has %d rules; expected 2", len(noteValidator.Rules))
 	}
 
 	// Test cases
@@ -161,7 +172,7 @@ func TestNoteValidator(t *testing.T) {
 		},
 		{
 			name:    "Title too long",
-			Note:    models.Note{Title: strings.Repeat("a", noteNameMaxLimit+1)},
+			Note:    models.Note{Title: strings.Repeat("a", validator.MaxNameLength+1)},
 			wantErr: true,
 		},
 		{
@@ -173,7 +184,7 @@ func TestNoteValidator(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validator.Run(tt.Note)
+			err := noteValidator.Run(tt.Note)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Validator.Run error = %v; wanted %v", err, tt.wantErr)
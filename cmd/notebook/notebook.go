@@ -0,0 +1,273 @@
+package notebook
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/rhysmah/CLI-Note-App/validator"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	notebookCmdFull  = "notebook"
+	notebookCmdShort = "Manage notebooks"
+	notebookCmdDesc  = `Notebooks are named collections of notes.
+
+Every note belongs to exactly one notebook, and note titles only need to be
+unique within their notebook. Commands operate on the default notebook
+unless another one is chosen with --notebook (-n).
+
+Examples:
+  cli-note notebook create work
+  cli-note notebook list
+  cli-note notebook rename work job
+  cli-note notebook default job
+  cli-note notebook delete job --force`
+
+	forceFlag = "force"
+)
+
+// init registers the notebook command with the root command.
+func init() {
+	notebookCommand := NotebookCommand()
+	root.RootCmd.AddCommand(notebookCommand)
+}
+
+// NotebookCommand creates and returns a cobra.Command for managing notebooks.
+// The command itself does nothing; the work is done by its subcommands.
+func NotebookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     notebookCmdFull,
+		Aliases: []string{"nb"},
+		Short:   notebookCmdShort,
+		Long:    notebookCmdDesc,
+	}
+
+	cmd.AddCommand(
+		createCommand(),
+		listCommand(),
+		renameCommand(),
+		deleteCommand(),
+		defaultCommand(),
+	)
+	return cmd
+}
+
+func createCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a new notebook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := createNotebook(name, root.NotesDB); err != nil {
+				return err
+			}
+
+			fmt.Printf("Notebook %q created!\nUse 'cli-note new <title> --notebook %s' to add notes to it.\n", name, name)
+			return nil
+		},
+	}
+}
+
+func listCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all notebooks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return root.NotesDB.View(func(tx *bolt.Tx) error {
+				notebooks, err := db.ListNotebooks(tx)
+				if err != nil {
+					return fmt.Errorf("error listing notebooks: %w", err)
+				}
+
+				for _, notebook := range notebooks {
+					notes, err := db.NotesInNotebook(tx, notebook.Name)
+					if err != nil {
+						return fmt.Errorf("error listing notebooks: %w", err)
+					}
+
+					marker := " "
+					if db.NormalizeTitle(notebook.Name) == db.NormalizeTitle(root.ActiveNotebook) {
+						marker = "*"
+					}
+					fmt.Printf("%s %s (%s)\n", marker, notebook.Name, output.Pluralize(len(notes), "note"))
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func renameCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old-name> <new-name>",
+		Short: "Rename a notebook",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldName, newName := args[0], args[1]
+			rewritten, err := renameNotebook(oldName, newName, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			// Keep the default notebook setting pointing at the renamed notebook
+			if db.NormalizeTitle(root.Config.DefaultNotebook) == db.NormalizeTitle(oldName) {
+				root.Config.DefaultNotebook = newName
+				if err := root.Config.Save(root.ConfigPath); err != nil {
+					return fmt.Errorf("error updating default notebook: %w", err)
+				}
+			}
+
+			fmt.Printf("Notebook %q renamed to %q\n", oldName, newName)
+			if len(rewritten) > 0 {
+				fmt.Printf("Updated links in %s\n", output.Pluralize(len(rewritten), "note"))
+			}
+			return nil
+		},
+	}
+}
+
+func deleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a notebook",
+		Long: `Delete a notebook.
+
+A notebook that still contains notes is only deleted when --force is given,
+in which case all of its notes are deleted too. This action cannot be undone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			force, _ := cmd.Flags().GetBool(forceFlag)
+
+			if db.NormalizeTitle(root.Config.DefaultNotebook) == db.NormalizeTitle(name) {
+				return fmt.Errorf("notebook %q is your default notebook; choose another default first", name)
+			}
+
+			deleted, err := deleteNotebook(name, force, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Notebook %q deleted along with %s\n", name, output.Pluralize(len(deleted), "note"))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(forceFlag, false, "Also delete the notes in the notebook")
+	return cmd
+}
+
+func defaultCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "default [name]",
+		Short: "Show or set the default notebook",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				defaultNotebook := root.Config.DefaultNotebook
+				if defaultNotebook == "" {
+					defaultNotebook = db.DefaultNotebook
+				}
+				fmt.Println(defaultNotebook)
+				return nil
+			}
+
+			notebook, err := findNotebook(args[0], root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			root.Config.DefaultNotebook = notebook.Name
+			if err := root.Config.Save(root.ConfigPath); err != nil {
+				return fmt.Errorf("error setting default notebook: %w", err)
+			}
+
+			fmt.Printf("Default notebook set to %q\n", notebook.Name)
+			return nil
+		},
+	}
+}
+
+// createNotebook validates name and creates a notebook with it.
+func createNotebook(name string, database *bolt.DB) error {
+	if err := validator.ValidateName("notebook", name); err != nil {
+		return fmt.Errorf("invalid notebook name: %w", err)
+	}
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		_, err := db.CreateNotebook(tx, name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error creating notebook: %w", err)
+	}
+	return nil
+}
+
+// renameNotebook validates newName and renames the notebook oldName to it.
+// It returns the notes whose links were rewritten.
+func renameNotebook(oldName, newName string, database *bolt.DB) ([]models.Note, error) {
+	if err := validator.ValidateName("notebook", newName); err != nil {
+		return nil, fmt.Errorf("invalid notebook name: %w", err)
+	}
+
+	var rewritten []models.Note
+	err := database.Update(func(tx *bolt.Tx) error {
+		var err error
+		rewritten, err = db.RenameNotebook(tx, oldName, newName)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error renaming notebook: %w", err)
+	}
+	return rewritten, nil
+}
+
+// deleteNotebook deletes a notebook and returns the notes deleted with it.
+// A notebook that still contains notes is only deleted if force is set.
+func deleteNotebook(name string, force bool, database *bolt.DB) ([]models.Note, error) {
+	var deleted []models.Note
+	err := database.Update(func(tx *bolt.Tx) error {
+		notes, err := db.NotesInNotebook(tx, name)
+		if err != nil {
+			return err
+		}
+		if len(notes) > 0 && !force {
+			return fmt.Errorf("notebook %q contains %s; use --%s to delete it with its notes", name, output.Pluralize(len(notes), "note"), forceFlag)
+		}
+
+		deleted, err = db.DeleteNotebook(tx, name)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error deleting notebook: %w", err)
+	}
+	return deleted, nil
+}
+
+// findNotebook returns the notebook with the given name, to be made the
+// default notebook.
+func findNotebook(name string, database *bolt.DB) (models.Notebook, error) {
+	var notebook models.Notebook
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		notebook, err = db.GetNotebook(tx, name)
+		return err
+	})
+	if errors.Is(err, db.ErrNotebookNotFound) {
+		return models.Notebook{}, fmt.Errorf("notebook %q does not exist; create it with 'cli-note notebook create %s'", name, name)
+	}
+	if err != nil {
+		return models.Notebook{}, fmt.Errorf("error setting default notebook: %w", err)
+	}
+	return notebook, nil
+}
@@ -0,0 +1,92 @@
+package notebook
+
+import (
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// storeNoteIn adds a test note titled title to notebook.
+func storeNoteIn(t *testing.T, database *bolt.DB, notebook, title string) {
+	t.Helper()
+	note := testutil.CreateTestNote()
+	note.Title = title
+	note.Notebook = notebook
+	if err := new.StoreNoteInDB(note, database); err != nil {
+		t.Fatalf("Error adding note to database: %v", err)
+	}
+}
+
+func TestCreateNotebook(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	if err := createNotebook("work", testDB); err != nil {
+		t.Fatalf("createNotebook() error = %v", err)
+	}
+	if err := createNotebook("Work", testDB); err == nil {
+		t.Error("Expected error creating a notebook with an existing name")
+	}
+	if err := createNotebook("work/home", testDB); err == nil {
+		t.Error("Expected error creating a notebook with an invalid name")
+	}
+}
+
+func TestRenameNotebook(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+	for _, name := range []string{"work", "home"} {
+		if err := createNotebook(name, testDB); err != nil {
+			t.Fatalf("createNotebook() error = %v", err)
+		}
+	}
+	storeNoteIn(t, testDB, "work", "standup")
+
+	if _, err := renameNotebook("work", "home", testDB); err == nil {
+		t.Error("Expected error renaming a notebook to an existing name")
+	}
+	if _, err := renameNotebook(db.DefaultNotebook, "main", testDB); err == nil {
+		t.Error("Expected error renaming the default notebook")
+	}
+	if _, err := renameNotebook("missing", "other", testDB); err == nil {
+		t.Error("Expected error renaming a missing notebook")
+	}
+
+	if _, err := renameNotebook("work", "job", testDB); err != nil {
+		t.Fatalf("renameNotebook() error = %v", err)
+	}
+	if _, err := findNotebook("job", testDB); err != nil {
+		t.Errorf("findNotebook() of the renamed notebook error = %v", err)
+	}
+	if _, err := findNotebook("work", testDB); err == nil {
+		t.Error("Expected the old notebook name to be gone")
+	}
+}
+
+func TestDeleteNotebook(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+	if err := createNotebook("work", testDB); err != nil {
+		t.Fatalf("createNotebook() error = %v", err)
+	}
+	storeNoteIn(t, testDB, "work", "standup")
+
+	if _, err := deleteNotebook("work", false, testDB); err == nil {
+		t.Fatal("Expected error deleting a notebook with notes without force")
+	}
+	if _, err := findNotebook("work", testDB); err != nil {
+		t.Fatalf("Expected notebook to be kept; got %v", err)
+	}
+
+	deleted, err := deleteNotebook("work", true, testDB)
+	if err != nil {
+		t.Fatalf("deleteNotebook() with force error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Title != "standup" {
+		t.Errorf("Deleted notes = %v; want the standup note", deleted)
+	}
+	if _, err := deleteNotebook("work", true, testDB); err == nil {
+		t.Error("Expected error deleting a missing notebook")
+	}
+}
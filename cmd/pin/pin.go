@@ -0,0 +1,89 @@
+package pin
+
+import (
+	"fmt"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	pinCmdFull  = "pin <title|id-prefix>"
+	pinCmdShort = "Pin a note to the top of the list"
+	pinCmdDesc  = `Pin a note so it is always listed first, whatever the sort order.`
+
+	unpinCmdFull  = "unpin <title|id-prefix>"
+	unpinCmdShort = "Unpin a note"
+	unpinCmdDesc  = `Unpin a note so it is listed in sort order again.`
+)
+
+// init registers the pin and unpin commands with the root command.
+func init() {
+	root.RootCmd.AddCommand(PinCommand())
+	root.RootCmd.AddCommand(UnpinCommand())
+}
+
+// PinCommand creates and returns a cobra.Command for pinning notes.
+func PinCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   pinCmdFull,
+		Short: pinCmdShort,
+		Long:  pinCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			note, err := setPinned(args[0], root.ActiveNotebook, true, root.NotesDB)
+			if err != nil {
+				return err
+			}
+			fmt.Println(output.Sprintf(output.Success, "Note %q pinned", note.Title))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// UnpinCommand creates and returns a cobra.Command for unpinning notes.
+func UnpinCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   unpinCmdFull,
+		Short: unpinCmdShort,
+		Long:  unpinCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			note, err := setPinned(args[0], root.ActiveNotebook, false, root.NotesDB)
+			if err != nil {
+				return err
+			}
+			fmt.Println(output.Sprintf(output.Success, "Note %q unpinned", note.Title))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// setPinned pins or unpins the note identified by handle in notebook.
+// Pinning doesn't change the note's modification time.
+func setPinned(handle, notebook string, pinned bool, database *bolt.DB) (models.Note, error) {
+	var updated models.Note
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		note.Pinned = pinned
+		if err := db.PutNote(tx, note); err != nil {
+			return err
+		}
+		updated = note
+		return nil
+	})
+
+	return updated, err
+}
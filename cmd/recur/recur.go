@@ -0,0 +1,302 @@
+package recur
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/recur"
+	"github.com/rhysmah/CLI-Note-App/templates"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	recurCmdFull  = "recur"
+	recurCmdShort = "Create notes from templates on a schedule"
+	recurCmdDesc  = `Recurring notes are created from a template on a schedule, such as a
+weekly retro or a monthly budget. Each note is titled "<name> <date>" and
+the template's {{.Date}} and {{.Weekday}} are those of the scheduled day.
+
+A schedule is "daily", "weekly", "monthly", "yearly", or an iCalendar
+RRULE using FREQ, INTERVAL, BYDAY (weekly) and BYMONTHDAY (monthly).
+Schedules start on the day they are added.
+
+'recur run' creates the notes that are due and remembers the last day it
+created a note for, so it is safe to run as often as you like, e.g. from cron.
+
+Examples:
+  cli-note recur add retro "FREQ=WEEKLY;BYDAY=FR"
+  cli-note recur add budget "FREQ=MONTHLY;BYMONTHDAY=1" --name Budget
+  cli-note recur run`
+
+	nameFlag   = "name"
+	dryRunFlag = "dry-run"
+)
+
+// init registers the recur command with the root command.
+func init() {
+	recurCommand := RecurCommand()
+	root.RootCmd.AddCommand(recurCommand)
+}
+
+// RecurCommand creates and returns a cobra.Command for managing recurring notes.
+// The command itself does nothing; the work is done by its subcommands.
+func RecurCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   recurCmdFull,
+		Short: recurCmdShort,
+		Long:  recurCmdDesc,
+	}
+
+	cmd.AddCommand(
+		addCommand(),
+		listCommand(),
+		removeCommand(),
+		runCommand(),
+	)
+	return cmd
+}
+
+func addCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <template> <schedule>",
+		Short: "Add a recurring note to the current notebook",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString(nameFlag)
+			if name == "" {
+				name = args[0]
+			}
+
+			recurrence := models.Recurrence{
+				Name:      name,
+				Template:  args[0],
+				Spec:      args[1],
+				Notebook:  root.ActiveNotebook,
+				Start:     dates.StartOfDay(time.Now()),
+				CreatedAt: time.Now(),
+			}
+			if err := addRecurrence(recurrence, root.NotesDB); err != nil {
+				return err
+			}
+
+			fmt.Printf("Recurring note %q added!\nUse 'cli-note recur run' to create the notes that are due.\n", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(nameFlag, "", "Name of the recurring note, used in note titles (defaults to the template name)")
+	return cmd
+}
+
+func listCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recurring notes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var recurrences []models.Recurrence
+			err := root.NotesDB.View(func(tx *bolt.Tx) error {
+				var err error
+				recurrences, err = db.ListRecurrences(tx)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error listing recurring notes: %w", err)
+			}
+
+			if len(recurrences) == 0 {
+				fmt.Println("You have no recurring notes")
+				return nil
+			}
+			for _, recurrence := range recurrences {
+				last := "never"
+				if !recurrence.LastGenerated.IsZero() {
+					last = recurrence.LastGenerated.Format(dates.DayFormat)
+				}
+				fmt.Printf("%s (%s): template %q, schedule %s, last created %s\n",
+					recurrence.Name, recurrence.Notebook, recurrence.Template, recurrence.Spec, last)
+			}
+			return nil
+		},
+	}
+}
+
+func removeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Stop creating a recurring note; notes already created are kept",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := root.NotesDB.Update(func(tx *bolt.Tx) error {
+				return db.DeleteRecurrence(tx, args[0])
+			})
+			if err != nil {
+				return fmt.Errorf("error removing recurring note: %w", err)
+			}
+
+			fmt.Printf("Successfully removed recurring note %q\n", args[0])
+			return nil
+		},
+	}
+}
+
+func runCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Create the recurring notes that are due",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+
+			created, err := runRecurrences(time.Now(), dryRun, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			for _, note := range created {
+				if dryRun {
+					fmt.Printf("Would create %q in notebook %q\n", note.Title, note.Notebook)
+				} else {
+					fmt.Printf("Created %q in notebook %q\n", note.Title, note.Notebook)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(dryRunFlag, false, "Show which notes would be created without creating them")
+	return cmd
+}
+
+// addRecurrence validates and stores a new recurrence.
+func addRecurrence(recurrence models.Recurrence, database *bolt.DB) error {
+	if _, err := recur.Parse(recurrence.Spec); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	if err := new.ValidateTitle(instanceTitle(recurrence, recurrence.Start)); err != nil {
+		return fmt.Errorf("name %q is not usable in note titles: %w", recurrence.Name, err)
+	}
+
+	return database.Update(func(tx *bolt.Tx) error {
+		if _, err := db.GetRecurrence(tx, recurrence.Name); err == nil {
+			return fmt.Errorf("recurring note %q already exists", recurrence.Name)
+		} else if !errors.Is(err, db.ErrRecurrenceNotFound) {
+			return err
+		}
+		if _, err := db.GetTemplate(tx, recurrence.Template); err != nil {
+			return err
+		}
+		if _, err := db.GetNotebook(tx, recurrence.Notebook); err != nil {
+			return err
+		}
+		return db.PutRecurrence(tx, recurrence)
+	})
+}
+
+// runRecurrences creates a note for every scheduled day of every recurrence,
+// from the day after the last note it created up to and including today.
+// Days whose note already exists are skipped. All notes are created in a
+// single transaction. With dryRun, nothing is stored.
+// It returns the notes that were (or would be) created.
+func runRecurrences(now time.Time, dryRun bool, database *bolt.DB) ([]models.Note, error) {
+	var created []models.Note
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		recurrences, err := db.ListRecurrences(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, recurrence := range recurrences {
+			notes, err := runRecurrence(tx, recurrence, now)
+			if err != nil {
+				return fmt.Errorf("error creating recurring note %q: %w", recurrence.Name, err)
+			}
+			created = append(created, notes...)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+
+	return created, err
+}
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// runRecurrence creates the due notes of a single recurrence and records
+// the last day it created a note for.
+func runRecurrence(tx *bolt.Tx, recurrence models.Recurrence, now time.Time) ([]models.Note, error) {
+	rule, err := recur.Parse(recurrence.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	from := recurrence.Start
+	if !recurrence.LastGenerated.IsZero() {
+		from = recurrence.LastGenerated.AddDate(0, 0, 1)
+	}
+	occurrences := rule.Occurrences(recurrence.Start, from, now)
+	if len(occurrences) == 0 {
+		return nil, nil
+	}
+
+	template, err := db.GetTemplate(tx, recurrence.Template)
+	if err != nil {
+		return nil, err
+	}
+	titles, err := db.NotebookTitles(tx, recurrence.Notebook)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []models.Note
+	for _, day := range occurrences {
+		title := instanceTitle(recurrence, day)
+		if titles.Get(db.TitleKey(title)) != nil {
+			continue
+		}
+
+		note, err := new.CreateNote(title, recurrence.Notebook)
+		if err != nil {
+			return nil, err
+		}
+		variables := templates.Variables(title, recurrence.Notebook, day, nil)
+		if note.Content, err = templates.Render(template.Content, variables); err != nil {
+			return nil, fmt.Errorf("error applying template %q: %w", template.Name, err)
+		}
+
+		if err := db.PutNote(tx, note); err != nil {
+			return nil, err
+		}
+		if err := db.PutNoteTitle(tx, note); err != nil {
+			return nil, err
+		}
+		created = append(created, note)
+	}
+
+	recurrence.LastGenerated = occurrences[len(occurrences)-1]
+	if err := db.PutRecurrence(tx, recurrence); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// instanceTitle returns the title of the note a recurrence creates for day.
+func instanceTitle(recurrence models.Recurrence, day time.Time) string {
+	return recurrence.Name + " " + day.Format(dates.DayFormat)
+}
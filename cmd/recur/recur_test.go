@@ -0,0 +1,99 @@
+package recur
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestRunRecurrencesIsIdempotent(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	err := testDB.Update(func(tx *bolt.Tx) error {
+		return db.PutTemplate(tx, models.Template{Name: "retro", Content: "# Retro {{.Date}}"})
+	})
+	if err != nil {
+		t.Fatalf("Failed to store template: %v", err)
+	}
+
+	// A Monday; the retro is every Friday
+	start := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local)
+	recurrence := models.Recurrence{
+		Name:     "retro",
+		Template: "retro",
+		Spec:     "FREQ=WEEKLY;BYDAY=FR",
+		Notebook: db.DefaultNotebook,
+		Start:    start,
+	}
+	if err := addRecurrence(recurrence, testDB); err != nil {
+		t.Fatalf("addRecurrence() error = %v", err)
+	}
+	if err := addRecurrence(recurrence, testDB); err == nil {
+		t.Error("Expected error adding a recurrence twice")
+	}
+
+	firstFriday := start.AddDate(0, 0, 4)
+	secondFriday := start.AddDate(0, 0, 11)
+
+	dryRun, err := runRecurrences(secondFriday, true, testDB)
+	if err != nil || len(dryRun) != 2 {
+		t.Fatalf("Dry run created %d notes (error %v); want 2", len(dryRun), err)
+	}
+
+	created, err := runRecurrences(firstFriday.Add(9*time.Hour), false, testDB)
+	if err != nil {
+		t.Fatalf("runRecurrences() error = %v", err)
+	}
+	if len(created) != 1 || created[0].Title != "retro 2026-10-23" {
+		t.Fatalf("Created %v; want one note titled %q", created, "retro 2026-10-23")
+	}
+	if want := "# Retro 2026-10-23"; created[0].Content != want {
+		t.Errorf("Content = %q; want %q", created[0].Content, want)
+	}
+	testutil.TestNoteTitleSaved(t, created[0], testDB)
+
+	again, err := runRecurrences(firstFriday.Add(10*time.Hour), false, testDB)
+	if err != nil || len(again) != 0 {
+		t.Errorf("Running again on the same day created %d notes (error %v); want 0", len(again), err)
+	}
+
+	next, err := runRecurrences(secondFriday, false, testDB)
+	if err != nil || len(next) != 1 {
+		t.Errorf("Running a week later created %d notes (error %v); want 1", len(next), err)
+	}
+}
+
+func TestAddRecurrenceValidates(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	err := testDB.Update(func(tx *bolt.Tx) error {
+		return db.PutTemplate(tx, models.Template{Name: "budget", Content: ""})
+	})
+	if err != nil {
+		t.Fatalf("Failed to store template: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		recurrence models.Recurrence
+	}{
+		{name: "Invalid Schedule", recurrence: models.Recurrence{Name: "budget", Template: "budget", Spec: "sometimes"}},
+		{name: "Missing Template", recurrence: models.Recurrence{Name: "retro", Template: "retro", Spec: "weekly"}},
+		{name: "Name Too Long", recurrence: models.Recurrence{Name: "monthly budget", Template: "budget", Spec: "monthly"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.recurrence.Notebook = db.DefaultNotebook
+			tt.recurrence.Start = time.Now()
+			if err := addRecurrence(tt.recurrence, testDB); err == nil {
+				t.Error("Expected error adding recurrence")
+			}
+		})
+	}
+}
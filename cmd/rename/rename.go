@@ -0,0 +1,84 @@
+package rename
+
+import (
+	"fmt"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	renameCmdFull  = "rename <title|id-prefix> <new-title>"
+	renameCmdShort = "Rename a note"
+	renameCmdDesc  = `Give a note a new title.
+
+Every [[link]] to the note in other notes is rewritten to use the new title,
+and dangling links to the new title now point at the note.
+
+Example:
+  cli-note rename "Shoping List" "Shopping List"`
+)
+
+// init registers the rename command with the root command.
+func init() {
+	renameCommand := RenameCommand()
+	root.RootCmd.AddCommand(renameCommand)
+}
+
+// RenameCommand creates and returns a cobra.Command for renaming notes.
+// The command requires exactly two arguments: the note's title or ID prefix, and its new title.
+func RenameCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   renameCmdFull,
+		Short: renameCmdShort,
+		Long:  renameCmdDesc,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldTitle := args[0]
+
+			note, rewritten, err := renameNote(args[0], root.ActiveNotebook, args[1], root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(output.Sprintf(output.Success, "Note %q renamed to %q", oldTitle, note.Title))
+			if len(rewritten) > 0 {
+				fmt.Printf("Updated links in %s\n", output.Pluralize(len(rewritten), "note"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// renameNote renames the note identified by handle in notebook and rewrites
+// the links to it. It returns the renamed note and the notes whose links were rewritten.
+func renameNote(handle, notebook, newTitle string, database *bolt.DB) (models.Note, []models.Note, error) {
+	if err := new.ValidateTitle(newTitle); err != nil {
+		return models.Note{}, nil, fmt.Errorf("invalid note name: %w", err)
+	}
+
+	var renamed models.Note
+	var rewritten []models.Note
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		renamed, rewritten, err = db.RenameNote(tx, note, newTitle)
+		if err != nil {
+			return fmt.Errorf("error renaming note %q: %w", note.Title, err)
+		}
+		return nil
+	})
+
+	return renamed, rewritten, err
+}
@@ -4,26 +4,93 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/config"
 	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/output"
 	"github.com/spf13/cobra"
 	bolt "go.etcd.io/bbolt"
 )
 
 var NotesDB *bolt.DB
 
+// Server is the client of a running 'cli-note serve', which owns the database
+// while it runs. When it is set, NotesDB is nil and commands that support the
+// server (see UseServer) send their requests through it.
+var Server *api.Client
+
+// serverAnnotation marks commands that can work through a running server.
+const serverAnnotation = "server"
+
+// Config holds the user's settings, loaded from ConfigPath.
+var Config config.Config
+
+// ConfigPath is the location of the user's settings file.
+var ConfigPath string
+
+// ActiveNotebook is the notebook commands operate on: the --notebook flag,
+// the configured default notebook, or db.DefaultNotebook, in that order.
+var ActiveNotebook = db.DefaultNotebook
+
+// notebookFlag holds the value of the persistent --notebook flag.
+var notebookFlag string
+
+// colorFlag holds the value of the persistent --color flag.
+var colorFlag string
+
 // rootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:   "cli-note",
 	Short: "A simple CLI-based note-taking app",
 	Long: `CLI Note is exactly as the name implies: a dead simple CLI-based note-taking app.
 
-You can create, edit, delete, and list your notes.
+You can create, edit, delete, and list your notes, and organize them into notebooks.
 
 Basic Commands:
 	new         Create a new .txt file
 	edit        Open a file using your OS's default text editor
 	delete      Delete a file via filename
 	list        List all notes (name, creation date, modified date)
+	show        Print a note, optionally rendering its Markdown
+	search      Search notes by title and content
+	pin         Pin a note to the top of the list (unpin to undo)
+	archive     Hide a note from list and search (unarchive to undo)
+	todo        List open checklist items and tick them off
+	due         Set or clear a note's due date
+	agenda      Show overdue, today's and upcoming notes
+	remind      Print due notes (--check exits non-zero if any)
+	recur       Create notes from templates on a schedule
+	stats       Show statistics about your notes
+	calendar    Show a calendar of note activity
+	notebook    Create, list, rename, and delete notebooks
+	move        Move a note to another notebook
+	rename      Rename a note and update links to it
+	links       List the [[links]] from a note
+	backlinks   List the notes linking to a note
+	graph       Export notes, links and tags as DOT or JSON
+	export      Export notes as a static HTML site
+	mirror      Mirror notes as Markdown files and sync changes both ways
+	git         Sync notes through a git repository and its remote
+	serve       Serve notes over a local HTTP JSON API and web UI
+	rpc         Serve notes over JSON-RPC on stdin/stdout for editor plugins
+	lsp         Run a language server for [[link]] completion in editors
+	template    Manage templates for new notes
+	today       Open today's journal entry
+	journal     Open or list journal entries
+
+Output is colored when it goes to a terminal, unless NO_COLOR is set.
+Use --color to override this, or set "color" and a "theme" in the config file:
+
+  {
+    "color": "auto",
+    "theme": {"header": "bold blue", "overdue": "bold red", "tag": "cyan"}
+  }
+
+Themes can style the roles header, pinned, overdue, tag, success, warning,
+error, and heading, strong, emphasis, code, link and quote for rendered
+Markdown, with bold, dim, italic, underline, colors (red, bright-red, ...)
+and backgrounds (on-red, ...).
 
 When you run CLI Notes for the first time, a small database is created locally on your machine.
 This database is located in your home directory at ~/.cli-notes/
@@ -39,7 +106,11 @@ Examples:
   cli-note delete "Shopping List"
   
   # List all your notes
-  cli-note list`,
+  cli-note list
+
+  # Create a note in the "work" notebook
+  cli-note notebook create work
+  cli-note new "Standup" --notebook work`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		var err error
 
@@ -50,18 +121,139 @@ Examples:
 		// Improve error messages
 		// Consider global logger
 
-		db, err := db.Initialize("")
+		notesDirectory, err := db.NotesDirectory("")
+		if err != nil {
+			fmt.Printf("error finding notes directory: %s", err)
+			os.Exit(1)
+		}
+
+		var warnings []string
+		if client, ok := runningServer(notesDirectory); ok {
+			if !supportsServer(cmd) {
+				fmt.Printf("the notes database is in use by 'cli-note serve' at %s\n"+
+					"Stop the server to use %q\n", client.Addr(), cmd.CommandPath())
+				os.Exit(1)
+			}
+			Server = client
+		} else {
+			database, err := db.Initialize("")
+			if err != nil {
+				fmt.Printf("error initializing database: %s", err)
+				os.Exit(1)
+			}
+			NotesDB = database
+
+			warnings, err = db.Migrate(NotesDB)
+			if err != nil {
+				fmt.Printf("error migrating database: %s", err)
+				os.Exit(1)
+			}
+		}
+
+		ConfigPath = config.Path(notesDirectory)
+		Config, err = config.Load(ConfigPath)
 		if err != nil {
-			fmt.Printf("error initializing database: %s", err)
+			fmt.Printf("error loading config: %s", err)
 			os.Exit(1)
 		}
-		NotesDB = db
+		ActiveNotebook = resolveNotebook(notebookFlag, Config)
+
+		if err := configureOutput(colorFlag, Config); err != nil {
+			fmt.Printf("error configuring output: %s", err)
+			os.Exit(1)
+		}
+		cmd.Root().SetErrPrefix(output.PaintErr(output.Error, "Error:"))
+
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, output.SprintfErr(output.Warning, "warning: %s", warning))
+		}
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		NotesDB.Close()
+		if NotesDB != nil {
+			NotesDB.Close()
+		}
 	},
 }
 
+func init() {
+	RootCmd.PersistentFlags().StringVarP(&notebookFlag, "notebook", "n", "", "Notebook to use (defaults to the configured default notebook)")
+	RootCmd.PersistentFlags().StringVar(&colorFlag, "color", "", "Color output: auto, always or never (defaults to the configured mode, or auto)")
+}
+
+// UseServer marks cmd as able to work through a running server, using Server
+// instead of NotesDB. Other commands refuse to run while a server owns the database.
+func UseServer(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[serverAnnotation] = "true"
+}
+
+// Store returns the store commands marked with UseServer work through:
+// Server if one is running, otherwise a store over NotesDB.
+func Store() api.Store {
+	if Server != nil {
+		return Server
+	}
+	return store.New(NotesDB, ActiveNotebook)
+}
+
+// supportsServer reports whether cmd can run while a server owns the database:
+// it was marked with UseServer, or it is cobra's help or completion command.
+func supportsServer(cmd *cobra.Command) bool {
+	if cmd.Annotations[serverAnnotation] != "" {
+		return true
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+	}
+	return false
+}
+
+// runningServer returns a client for the server recorded in the notes
+// directory, if there is one and it answers. A server file left behind by a
+// server that is no longer running is ignored.
+func runningServer(notesDirectory string) (*api.Client, bool) {
+	info, err := api.ReadInfo(api.InfoPath(notesDirectory))
+	if err != nil {
+		return nil, false
+	}
+	client := api.NewClient(info)
+	if err := client.Ping(); err != nil {
+		return nil, false
+	}
+	return client, true
+}
+
+// configureOutput sets up colored output from the --color flag if set,
+// otherwise from the configured mode, using the configured theme.
+func configureOutput(flag string, cfg config.Config) error {
+	value := cfg.Color
+	if flag != "" {
+		value = flag
+	}
+	mode, err := output.ParseMode(value)
+	if err != nil {
+		return err
+	}
+	return output.Configure(mode, cfg.Theme)
+}
+
+// resolveNotebook picks the notebook to operate on: the flag value if set,
+// otherwise the configured default, otherwise db.DefaultNotebook.
+func resolveNotebook(flag string, cfg config.Config) string {
+	if flag != "" {
+		return flag
+	}
+	if cfg.DefaultNotebook != "" {
+		return cfg.DefaultNotebook
+	}
+	return db.DefaultNotebook
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/jsonrpc"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rpcCmdFull  = "rpc"
+	rpcCmdShort = "Serve notes over JSON-RPC on stdin and stdout"
+	rpcCmdDesc  = `Answer JSON-RPC 2.0 requests on standard input until it is closed,
+for editor plugins that keep one process running instead of calling
+cli-note for every operation.
+
+Send one request (or batch) per line; each answer is written on one line.
+Params are passed by name:
+
+  list    {notebook?}
+  get     {id, notebook?}
+  create  {title, notebook?, content?, tags?, pinned?, archived?, due?}
+  update  {id, notebook?, if_match?, changes: {title?, notebook?, content?, tags?, ...}}
+  delete  {id, notebook?, if_match?}
+  search  {query, notebook?}
+  tag     {id, notebook?, if_match?, add?, remove?}
+  tags    {notebook?}
+
+"id" is a note ID, ID prefix or title. Notes are returned with an "etag";
+pass it as "if_match" to only change a note nobody else changed meanwhile.
+The database is opened once for the whole session, or the requests go
+through 'cli-note serve' if it is running.
+
+Example:
+  echo '{"jsonrpc": "2.0", "id": 1, "method": "search", "params": {"query": "budget"}}' | cli-note rpc`
+)
+
+// init registers the rpc command with the root command.
+func init() {
+	rpcCommand := RPCCommand()
+	root.RootCmd.AddCommand(rpcCommand)
+}
+
+// RPCCommand creates and returns a cobra.Command for serving JSON-RPC on stdin and stdout.
+func RPCCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   rpcCmdFull,
+		Short: rpcCmdShort,
+		Long:  rpcCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jsonrpc.NewServer(root.Store()).Serve(cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+	root.UseServer(cmd)
+
+	return cmd
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/list"
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/table"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	searchCmdFull  = "search <query>"
+	searchCmdShort = "Search notes by title and content"
+	searchCmdDesc  = `List the notes whose title or content contains the query, ignoring case.
+
+Archived notes are hidden unless --archived or --all is passed.
+
+Example:
+  cli-note search "budget"`
+
+	allNotebooksFlag = "all-notebooks"
+)
+
+// init registers the search command with the root command.
+func init() {
+	searchCommand := SearchCommand()
+	root.RootCmd.AddCommand(searchCommand)
+}
+
+// SearchCommand creates and returns a cobra.Command for searching notes.
+// The command requires exactly one argument: the text to search for.
+func SearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   searchCmdFull,
+		Short: searchCmdShort,
+		Long:  searchCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			notebook := root.ActiveNotebook
+			if allNotebooks, _ := cmd.Flags().GetBool(allNotebooksFlag); allNotebooks {
+				notebook = ""
+			}
+
+			var notes []models.Note
+			var err error
+			if root.Server != nil {
+				notes, err = root.Server.Search(args[0], notebook)
+			} else {
+				notes, err = searchNotes(args[0], notebook, root.NotesDB)
+			}
+			if err != nil {
+				return err
+			}
+			notes = list.FilterArchived(notes, list.ArchiveFilterFromFlags(cmd))
+
+			if len(notes) == 0 {
+				fmt.Printf("No notes match %q\n", args[0])
+				return nil
+			}
+
+			dateFormatter, err := list.DateFormatterFromFlags(cmd, root.Config)
+			if err != nil {
+				return err
+			}
+
+			list.SortNotes(notes, list.SortByModified, list.SortOrderAscending)
+			list.DisplayNotes(notes, list.SortByModified, list.SortOrderAscending,
+				list.DisplayOptions{Notebook: notebook, Dates: dateFormatter, MaxWidth: table.TerminalWidth()})
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(allNotebooksFlag, false, "Search notes from every notebook")
+	list.AddArchiveFlags(cmd)
+	list.AddDateFlags(cmd)
+	root.UseServer(cmd)
+
+	return cmd
+}
+
+// searchNotes returns the notes in notebook, or in every notebook if it is empty,
+// whose title or content contains query.
+func searchNotes(query, notebook string, database *bolt.DB) ([]models.Note, error) {
+	var matches []models.Note
+	err := database.View(func(tx *bolt.Tx) error {
+		notes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+		matches = db.MatchNotes(notes, query, notebook)
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error searching notes: %w", err)
+	}
+	return matches, nil
+}
@@ -0,0 +1,47 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+)
+
+func TestSearchNotes(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	for title, content := range map[string]string{
+		"Budget":    "rent and food",
+		"Groceries": "Food for the week",
+		"Ideas":     "nothing yet",
+	} {
+		note := testutil.CreateTestNote()
+		note.Title = title
+		note.Content = content
+		if err := new.StoreNoteInDB(note, testDB); err != nil {
+			t.Fatalf("Error adding note to database: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{query: "FOOD", want: 2},
+		{query: "idea", want: 1},
+		{query: "missing", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			notes, err := searchNotes(tt.query, db.DefaultNotebook, testDB)
+			if err != nil {
+				t.Fatalf("searchNotes() error = %v", err)
+			}
+			if len(notes) != tt.want {
+				t.Errorf("searchNotes(%q) found %d notes; want %d", tt.query, len(notes), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/rhysmah/CLI-Note-App/web"
+	"github.com/spf13/cobra"
+)
+
+const (
+	serveCmdFull  = "serve"
+	serveCmdShort = "Serve notes over a local HTTP JSON API and web UI"
+	serveCmdDesc  = `Serve your notes as JSON over HTTP until interrupted, with a web UI
+to list, search, view and edit them in a browser at http://<addr>/ui/.
+
+Endpoints:
+  GET    /notes             List notes (?notebook=work)
+  POST   /notes             Create a note: {"title": "...", "content": "...", "tags": [...]}
+  GET    /notes/{id}        Get a note by ID, ID prefix or title
+  GET    /notes/{id}/html   Get a note's content rendered as HTML
+  PUT    /notes/{id}        Update the fields given: title, notebook, content, tags, pinned, archived, due
+  DELETE /notes/{id}        Delete a note
+  POST   /notes/delete      Delete notes, all or none: [{"id": "...", "if_match": "..."}]
+  GET    /search?q=...      Search notes by title and content
+  GET    /tags              List tags and how many notes have them
+
+Notes come with an ETag header. Send it back as If-Match with PUT and DELETE
+to fail with 412 Precondition Failed if the note changed in the meantime.
+With --token, requests must send "Authorization: Bearer <token>"; the web UI
+asks for the token. Request bodies must be sent as application/json.
+When listening on a loopback address, only requests for localhost or a
+loopback IP are accepted, so other web sites can't reach your notes.
+
+While the server runs it is the only process using the database. The list,
+show, search, new and delete commands send their requests through it; other
+commands ask you to stop the server first.
+
+Example:
+  cli-note serve --addr 127.0.0.1:8080 --token secret
+  curl -H "Authorization: Bearer secret" http://127.0.0.1:8080/notes`
+
+	addrFlag  = "addr"
+	tokenFlag = "token"
+
+	// shutdownTimeout is how long requests in flight get to finish on exit.
+	shutdownTimeout = 5 * time.Second
+)
+
+// init registers the serve command with the root command.
+func init() {
+	serveCommand := ServeCommand()
+	root.RootCmd.AddCommand(serveCommand)
+}
+
+// ServeCommand creates and returns a cobra.Command for serving notes over HTTP.
+func ServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   serveCmdFull,
+		Short: serveCmdShort,
+		Long:  serveCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, _ := cmd.Flags().GetString(addrFlag)
+			token, _ := cmd.Flags().GetString(tokenFlag)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return serve(ctx, addr, token, newHandler(store.New(root.NotesDB, root.ActiveNotebook), token))
+		},
+	}
+
+	cmd.Flags().String(addrFlag, "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().String(tokenFlag, "", "Require this bearer token on every request")
+
+	return cmd
+}
+
+// newHandler serves the API over store, and the web UI under /ui/.
+func newHandler(store api.Store, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", api.NewHandler(store, token))
+	mux.Handle("GET /ui/", http.StripPrefix("/ui", web.Handler()))
+	mux.Handle("GET /{$}", http.RedirectHandler("/ui/", http.StatusFound))
+	return mux
+}
+
+// serve listens on addr and serves handler until ctx is done. The server
+// file in the notes directory tells other cli-note processes where to find
+// it while it runs.
+func serve(ctx context.Context, addr, token string, handler http.Handler) error {
+	notesDirectory, err := db.NotesDirectory("")
+	if err != nil {
+		return fmt.Errorf("error finding notes directory: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", addr, err)
+	}
+	addr = listener.Addr().String()
+
+	infoPath := api.InfoPath(notesDirectory)
+	if err := api.WriteInfo(infoPath, api.Info{Addr: addr, Token: token, PID: os.Getpid()}); err != nil {
+		listener.Close()
+		return err
+	}
+	defer api.RemoveInfo(infoPath)
+
+	if isLoopback(listener.Addr()) {
+		handler = localOnly(handler)
+	} else if token == "" {
+		fmt.Println(output.Sprintf(output.Warning, "warning: serving on %s without a --token; anyone who can reach it can change your notes", addr))
+	}
+	fmt.Println(output.Sprintf(output.Success, "Serving notes on http://%s", addr))
+	fmt.Printf("Open http://%s/ui/ in your browser to use the web UI\n", addr)
+	fmt.Println("Press Ctrl+C to stop")
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-failed:
+		return fmt.Errorf("error serving notes: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error stopping server: %w", err)
+	}
+	fmt.Println("Server stopped")
+	return nil
+}
+
+// localOnly rejects requests that don't name localhost or a loopback IP as
+// their host. Browsers send the host of the page's URL, so this keeps web
+// sites from reaching the server through a domain resolving to 127.0.0.1.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if host != "localhost" && !net.ParseIP(host).IsLoopback() {
+			http.Error(w, "requests must be addressed to localhost", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback reports whether addr only accepts connections from this machine.
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
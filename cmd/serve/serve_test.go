@@ -0,0 +1,61 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+)
+
+func TestNewHandlerRoutes(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+	handler := newHandler(store.New(testDB, db.DefaultNotebook), "secret")
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/", http.StatusFound},
+		{"/ui/", http.StatusOK},
+		{"/ui/app.js", http.StatusOK},
+		// The UI loads without the token; the API asks for it
+		{"/notes", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("GET %s status = %d; want %d", tt.path, recorder.Code, tt.status)
+		}
+	}
+}
+
+func TestLocalOnly(t *testing.T) {
+	handler := localOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		host   string
+		status int
+	}{
+		{"localhost:8080", http.StatusOK},
+		{"127.0.0.1:8080", http.StatusOK},
+		{"[::1]:8080", http.StatusOK},
+		{"localhost", http.StatusOK},
+		{"evil.example:8080", http.StatusForbidden},
+		{"192.168.1.10:8080", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/notes", nil)
+		request.Host = tt.host
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tt.status {
+			t.Errorf("request for host %q status = %d; want %d", tt.host, recorder.Code, tt.status)
+		}
+	}
+}
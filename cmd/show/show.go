@@ -0,0 +1,125 @@
+package show
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/markdown"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/output"
+	"github.com/rhysmah/CLI-Note-App/pager"
+	"github.com/rhysmah/CLI-Note-App/table"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	showCmdFull  = "show <title|id-prefix>"
+	showCmdShort = "Print a note"
+	showCmdDesc  = `Print the content of a note.
+
+With --render, the note is formatted as Markdown: headings, emphasis,
+lists, checkboxes, code blocks, links and tables are styled and text is
+wrapped to the width of the terminal. When the output isn't a terminal,
+the note is rendered as plain text without colors or wrapping.
+
+Notes taller than the terminal are shown through $PAGER (or less).
+Use --no-pager to print them directly.
+
+Examples:
+  cli-note show "Shopping List"
+  cli-note show "Meeting Notes" --render`
+
+	renderFlag  = "render"
+	noPagerFlag = "no-pager"
+)
+
+// init registers the show command with the root command.
+func init() {
+	showCommand := ShowCommand()
+	root.RootCmd.AddCommand(showCommand)
+}
+
+// ShowCommand creates and returns a cobra.Command for printing notes.
+// The command requires exactly one argument: the note title or a unique ID prefix.
+func ShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   showCmdFull,
+		Short: showCmdShort,
+		Long:  showCmdDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var note models.Note
+			var err error
+			if root.Server != nil {
+				note, err = root.Server.Note(root.ActiveNotebook, args[0])
+				if err != nil {
+					err = fmt.Errorf("error finding note %q: %w", args[0], err)
+				}
+			} else {
+				note, err = findNote(args[0], root.ActiveNotebook, root.NotesDB)
+			}
+			if err != nil {
+				return err
+			}
+
+			text := note.Content
+			if render, _ := cmd.Flags().GetBool(renderFlag); render {
+				text = renderNote(note, table.TerminalWidth(), output.Colored())
+			} else if text != "" && !strings.HasSuffix(text, "\n") {
+				text += "\n"
+			}
+
+			if noPager, _ := cmd.Flags().GetBool(noPagerFlag); noPager {
+				fmt.Print(text)
+				return nil
+			}
+			return pager.Page(text)
+		},
+	}
+
+	cmd.Flags().BoolP(renderFlag, "r", false, "Format the note as Markdown")
+	cmd.Flags().Bool(noPagerFlag, false, "Don't show long notes through a pager")
+	root.UseServer(cmd)
+
+	return cmd
+}
+
+// findNote returns the note identified by handle in notebook.
+func findNote(handle, notebook string, database *bolt.DB) (models.Note, error) {
+	var note models.Note
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+		return nil
+	})
+	return note, err
+}
+
+// markdownRoles maps the styles of rendered Markdown to output roles.
+var markdownRoles = map[markdown.Style]output.Role{
+	markdown.StyleHeading:  output.Heading,
+	markdown.StyleStrong:   output.Strong,
+	markdown.StyleEmphasis: output.Emphasis,
+	markdown.StyleCode:     output.Code,
+	markdown.StyleLink:     output.Link,
+	markdown.StyleQuote:    output.Quote,
+}
+
+// renderNote renders a note's Markdown content wrapped to width, which is
+// 0 for no wrapping, and styled with the output theme if colored is set.
+func renderNote(note models.Note, width int, colored bool) string {
+	opts := markdown.TerminalOptions{Width: width}
+	if colored {
+		opts.Style = func(style markdown.Style, text string) string {
+			return output.Paint(markdownRoles[style], text)
+		}
+	}
+	return markdown.RenderTerminal(markdown.Parse(note.Content), opts)
+}
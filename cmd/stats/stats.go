@@ -0,0 +1,207 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/stats"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	statsCmdFull  = "stats [title|id-prefix]"
+	statsCmdShort = "Show statistics about your notes"
+	statsCmdDesc  = `Show statistics about all of your notes: word and character counts,
+weekly activity, top tags, the largest notes and the size of the database.
+
+Given a note, show its word and line counts, reading time and edit count.`
+
+	weeksFlag = "weeks"
+	topFlag   = "top"
+
+	histogramWidth = 30
+)
+
+// init registers the stats command with the root command.
+func init() {
+	statsCommand := StatsCommand()
+	root.RootCmd.AddCommand(statsCommand)
+}
+
+// StatsCommand creates and returns a cobra.Command for showing note statistics.
+func StatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   statsCmdFull,
+		Short: statsCmdShort,
+		Long:  statsCmdDesc,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				note, err := findNote(args[0], root.ActiveNotebook, root.NotesDB)
+				if err != nil {
+					return err
+				}
+				printNoteStats(note)
+				return nil
+			}
+
+			weeks, _ := cmd.Flags().GetInt(weeksFlag)
+			top, _ := cmd.Flags().GetInt(topFlag)
+			if weeks < 1 || top < 1 {
+				return fmt.Errorf("--%s and --%s must be at least 1", weeksFlag, topFlag)
+			}
+
+			notes, buckets, err := readDatabase(root.NotesDB)
+			if err != nil {
+				return err
+			}
+			printSummary(stats.Summarize(notes, time.Now(), weeks, top))
+
+			fmt.Println()
+			return printDatabaseStats(root.NotesDB.Path(), buckets)
+		},
+	}
+
+	cmd.Flags().Int(weeksFlag, 8, "Number of weeks of activity to show")
+	cmd.Flags().Int(topFlag, 5, "Number of top tags and largest notes to show")
+
+	return cmd
+}
+
+// findNote looks up a note by its title in the notebook or a unique prefix of its ID.
+func findNote(handle, notebook string, database *bolt.DB) (models.Note, error) {
+	var note models.Note
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		note, err = db.LookupNote(tx, notebook, handle)
+		return err
+	})
+	if err != nil {
+		return models.Note{}, fmt.Errorf("error finding note %q: %w", handle, err)
+	}
+	return note, nil
+}
+
+// bucketStats is the number of keys in a top-level bucket, including nested ones.
+type bucketStats struct {
+	name string
+	keys int
+}
+
+// readDatabase returns every note and the key counts of the top-level buckets.
+func readDatabase(database *bolt.DB) ([]models.Note, []bucketStats, error) {
+	var notes []models.Note
+	var buckets []bucketStats
+
+	err := database.View(func(tx *bolt.Tx) error {
+		var err error
+		if notes, err = db.AllNotes(tx); err != nil {
+			return err
+		}
+		return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+			buckets = append(buckets, bucketStats{name: string(name), keys: bucket.Stats().KeyN})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading database: %w", err)
+	}
+
+	sort.Slice(buckets, func(a, b int) bool {
+		return buckets[a].name < buckets[b].name
+	})
+	return notes, buckets, nil
+}
+
+// printNoteStats prints the statistics of a single note.
+func printNoteStats(note models.Note) {
+	fmt.Printf("%s (%s)\n", note.Title, note.Notebook)
+	fmt.Printf("  Words:         %d\n", stats.Words(note.Content))
+	fmt.Printf("  Characters:    %d\n", stats.Characters(note.Content))
+	fmt.Printf("  Lines:         %d\n", stats.Lines(note.Content))
+	fmt.Printf("  Reading time:  %s\n", formatReadingTime(stats.ReadingTime(note.Content)))
+	fmt.Printf("  Edits:         %d\n", note.EditCount)
+}
+
+// printSummary prints the statistics of all notes.
+func printSummary(summary stats.Summary) {
+	fmt.Printf("Notes:       %d\n", summary.Notes)
+	fmt.Printf("Words:       %d total, %.0f per note\n", summary.Words, summary.AverageWords())
+	fmt.Printf("Characters:  %d total, %.0f per note\n", summary.Characters, summary.AverageCharacters())
+
+	fmt.Println()
+	fmt.Println("Activity per week (created / modified):")
+	busiest := 0
+	for _, week := range summary.Weeks {
+		busiest = max(busiest, week.Created, week.Modified)
+	}
+	for _, week := range summary.Weeks {
+		fmt.Printf("  %s  created  %3d %s\n", week.Start.Format(dates.DayFormat), week.Created, stats.Bar(week.Created, busiest, histogramWidth))
+		fmt.Printf("  %s  modified %3d %s\n", strings.Repeat(" ", len(dates.DayFormat)), week.Modified, stats.Bar(week.Modified, busiest, histogramWidth))
+	}
+
+	if len(summary.TopTags) > 0 {
+		fmt.Println()
+		fmt.Println("Top tags:")
+		for _, tag := range summary.TopTags {
+			fmt.Printf("  %-20s %d\n", tag.Name, tag.Count)
+		}
+	}
+
+	if len(summary.Largest) > 0 {
+		fmt.Println()
+		fmt.Println("Largest notes (words):")
+		for _, note := range summary.Largest {
+			fmt.Printf("  %-20s %d\n", note.Name, note.Count)
+		}
+	}
+}
+
+// printDatabaseStats prints the size of the database file and the number of keys in each bucket.
+func printDatabaseStats(path string, buckets []bucketStats) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading database file: %w", err)
+	}
+
+	fmt.Printf("Database:    %s (%s)\n", path, formatBytes(info.Size()))
+	for _, bucket := range buckets {
+		fmt.Printf("  %-20s %d keys\n", bucket.name, bucket.keys)
+	}
+	return nil
+}
+
+// formatReadingTime formats a reading time in whole minutes.
+func formatReadingTime(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// formatBytes formats a size in bytes using binary units.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, suffix := float64(size), ""
+	for _, s := range []string{"KiB", "MiB", "GiB"} {
+		value /= unit
+		suffix = s
+		if value < unit {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
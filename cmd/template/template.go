@@ -0,0 +1,222 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/editor"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	templateCmdFull  = "template"
+	templateCmdShort = "Manage note templates"
+	templateCmdDesc  = `Templates provide the initial content of new notes.
+
+Templates use Go's text/template syntax and can refer to these variables:
+  {{.Title}}     the title of the new note
+  {{.Notebook}}  the notebook the note is created in
+  {{.Date}}      the date, e.g. 2026-10-19
+  {{.Time}}      the time, e.g. 14:05
+  {{.Weekday}}   the day of the week, e.g. Monday
+as well as custom variables passed to 'new' with --var key=value.
+
+Examples:
+  cli-note template add meeting
+  cli-note template add meeting --file meeting.md
+  cli-note new "Kickoff" --template meeting --var client=ACME`
+
+	fileFlag = "file"
+)
+
+// init registers the template command with the root command.
+func init() {
+	templateCommand := TemplateCommand()
+	root.RootCmd.AddCommand(templateCommand)
+}
+
+// TemplateCommand creates and returns a cobra.Command for managing templates.
+// The command itself does nothing; the work is done by its subcommands.
+func TemplateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   templateCmdFull,
+		Short: templateCmdShort,
+		Long:  templateCmdDesc,
+	}
+
+	cmd.AddCommand(
+		listCommand(),
+		addCommand(),
+		editCommand(),
+		deleteCommand(),
+	)
+	return cmd
+}
+
+func listCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List all templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var templateList []models.Template
+			err := root.NotesDB.View(func(tx *bolt.Tx) error {
+				var err error
+				templateList, err = db.ListTemplates(tx)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error listing templates: %w", err)
+			}
+
+			if len(templateList) == 0 {
+				fmt.Println("You have no templates")
+				return nil
+			}
+			for _, template := range templateList {
+				firstLine, _, _ := strings.Cut(strings.TrimSpace(template.Content), "\n")
+				fmt.Printf("%-*s  %s\n", templateNameMaxLimit, template.Name, firstLine)
+			}
+			return nil
+		},
+	}
+}
+
+func addCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a new template",
+		Long: `Add a new template, written in your default text editor
+or read from a file with --file.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			err := root.NotesDB.View(func(tx *bolt.Tx) error {
+				_, err := db.GetTemplate(tx, name)
+				return err
+			})
+			if err == nil {
+				return fmt.Errorf("template %q already exists; use 'cli-note template edit %s' to change it", name, name)
+			}
+			if !errors.Is(err, db.ErrTemplateNotFound) {
+				return fmt.Errorf("error checking if template already exists: %w", err)
+			}
+
+			content, err := templateContent(cmd)
+			if err != nil {
+				return err
+			}
+
+			template := models.Template{
+				Name:       name,
+				Content:    content,
+				CreatedAt:  time.Now(),
+				ModifiedAt: time.Now(),
+			}
+			if err := saveTemplate(template); err != nil {
+				return err
+			}
+
+			fmt.Printf("Template %q added!\nUse 'cli-note new <title> --template %s' to create a note from it.\n", name, name)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(fileFlag, "", "Read the template from this file instead of opening an editor")
+	return cmd
+}
+
+func editCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Edit a template in your default text editor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var template models.Template
+			err := root.NotesDB.View(func(tx *bolt.Tx) error {
+				var err error
+				template, err = db.GetTemplate(tx, args[0])
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error retrieving template: %w", err)
+			}
+
+			editedContent, err := editor.Edit(template.Content)
+			if err != nil {
+				return err
+			}
+			if editedContent == template.Content {
+				fmt.Println("No changes made to template.")
+				return nil
+			}
+
+			template.Content = editedContent
+			template.ModifiedAt = time.Now()
+			if err := saveTemplate(template); err != nil {
+				return err
+			}
+
+			fmt.Println("Template updated successfully.")
+			return nil
+		},
+	}
+}
+
+func deleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := root.NotesDB.Update(func(tx *bolt.Tx) error {
+				return db.DeleteTemplate(tx, args[0])
+			})
+			if err != nil {
+				return fmt.Errorf("error deleting template: %w", err)
+			}
+
+			fmt.Printf("Successfully deleted template %q\n", args[0])
+			return nil
+		},
+	}
+}
+
+// templateContent reads the content of a new template from the --file flag,
+// or from the user's editor if no file was given.
+func templateContent(cmd *cobra.Command) (string, error) {
+	file, _ := cmd.Flags().GetString(fileFlag)
+	if file == "" {
+		return editor.Edit("")
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error reading template file: %w", err)
+	}
+	return string(content), nil
+}
+
+// saveTemplate validates a template and stores it in the database.
+func saveTemplate(template models.Template) error {
+	if err := newValidator().Run(template); err != nil {
+		return err
+	}
+
+	err := root.NotesDB.Update(func(tx *bolt.Tx) error {
+		return db.PutTemplate(tx, template)
+	})
+	if err != nil {
+		return fmt.Errorf("error saving template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/templates"
+	"github.com/rhysmah/CLI-Note-App/validator"
+)
+
+const (
+	templateNameMaxLimit int = 20
+	templateNameMinLimit int = 1
+)
+
+// newValidator creates and returns a new validator for Template objects
+// with predefined validation rules.
+func newValidator() *validator.Validator[models.Template] {
+	return &validator.Validator[models.Template]{
+		Rules: []validator.ValidationRule[models.Template]{
+			validateTemplateNameLength,
+			validateTemplateContent,
+		},
+	}
+}
+
+// validateTemplateNameLength checks that the template's name is neither empty
+// nor longer than the character limit.
+func validateTemplateNameLength(template models.Template) error {
+	nameTrimmed := strings.TrimSpace(template.Name)
+	nameLength := utf8.RuneCountInString(nameTrimmed)
+
+	if nameLength < templateNameMinLimit {
+		return errors.New("template name cannot be empty")
+	}
+	if nameLength > templateNameMaxLimit {
+		errMsg := fmt.Sprintf("template name %q must be less than %d characters", nameTrimmed, templateNameMaxLimit)
+		return errors.New(errMsg)
+	}
+	return nil
+}
+
+// validateTemplateContent checks that the template's content can be parsed.
+func validateTemplateContent(template models.Template) error {
+	_, err := templates.Parse(template.Content)
+	return err
+}
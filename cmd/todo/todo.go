@@ -0,0 +1,224 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/todo"
+	"github.com/spf13/cobra"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	todoCmdFull  = "todo"
+	todoCmdShort = "List open checklist items"
+	todoCmdDesc  = `List the open checklist items ("- [ ] ...") of every note, with the
+title of the note and the line each item is on. Archived notes are skipped.
+
+Use 'cli-note todo done <title> <line>' to tick an item off.`
+
+	doneCmdFull  = "done <title|id-prefix> <line>"
+	doneCmdShort = "Tick off a checklist item"
+	doneCmdDesc  = `Tick off the checklist item on the given line of a note,
+as shown by 'cli-note todo'.
+
+Like 'cli-note todo', it works across notebooks: a title not found in the
+current notebook is looked up in the others. If several of them have a
+note with that title, choose one with --notebook or use an ID prefix.
+
+Example:
+  cli-note todo done "Groceries" 3`
+)
+
+// init registers the todo command with the root command.
+func init() {
+	todoCommand := TodoCommand()
+	root.RootCmd.AddCommand(todoCommand)
+}
+
+// TodoCommand creates and returns a cobra.Command for listing open checklist items,
+// with a 'done' subcommand for ticking them off.
+func TodoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   todoCmdFull,
+		Short: todoCmdShort,
+		Long:  todoCmdDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			openItems, err := openTodos(root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			if len(openItems) == 0 {
+				fmt.Println("Nothing to do!")
+				return nil
+			}
+			for i, entry := range openItems {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%s (%s)\n", entry.note.Title, entry.note.Notebook)
+				for _, item := range entry.items {
+					fmt.Printf("  %3d: [ ] %s\n", item.Line, item.Text)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(doneCommand())
+	return cmd
+}
+
+// doneCommand creates and returns a cobra.Command for ticking off a checklist item.
+func doneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   doneCmdFull,
+		Short: doneCmdShort,
+		Long:  doneCmdDesc,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			line, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid line number %q", args[1])
+			}
+
+			note, changed, err := markDone(args[0], root.ActiveNotebook, line, root.NotesDB)
+			if err != nil {
+				return err
+			}
+
+			if changed {
+				fmt.Printf("Ticked off line %d of %q\n", line, note.Title)
+			} else {
+				fmt.Printf("Line %d of %q is already done\n", line, note.Title)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// noteTodos is a note together with its open checklist items.
+type noteTodos struct {
+	note  models.Note
+	items []todo.Item
+}
+
+// openTodos returns the open checklist items of every note that isn't archived,
+// grouped by note and sorted by notebook and title.
+func openTodos(database *bolt.DB) ([]noteTodos, error) {
+	var openItems []noteTodos
+
+	err := database.View(func(tx *bolt.Tx) error {
+		notes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, note := range notes {
+			if note.Archived {
+				continue
+			}
+			var items []todo.Item
+			for _, item := range todo.Parse(note.Content) {
+				if !item.Done {
+					items = append(items, item)
+				}
+			}
+			if len(items) > 0 {
+				openItems = append(openItems, noteTodos{note: note, items: items})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading notes: %w", err)
+	}
+
+	sort.Slice(openItems, func(a, b int) bool {
+		noteA, noteB := openItems[a].note, openItems[b].note
+		if db.NormalizeTitle(noteA.Notebook) != db.NormalizeTitle(noteB.Notebook) {
+			return db.NormalizeTitle(noteA.Notebook) < db.NormalizeTitle(noteB.Notebook)
+		}
+		return db.NormalizeTitle(noteA.Title) < db.NormalizeTitle(noteB.Title)
+	})
+	return openItems, nil
+}
+
+// markDone ticks off the checklist item on the given line of the note identified by handle.
+// If the item was open, the note's modification time and edit count are updated.
+// It returns the note and whether the item was open.
+func markDone(handle, notebook string, line int, database *bolt.DB) (models.Note, bool, error) {
+	var updated models.Note
+	var changed bool
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := findNote(tx, notebook, handle)
+		if err != nil {
+			return fmt.Errorf("error finding note %q: %w", handle, err)
+		}
+
+		content, wasOpen, err := todo.MarkDone(note.Content, line)
+		if err != nil {
+			return fmt.Errorf("error ticking off item in %q: %w", note.Title, err)
+		}
+
+		updated, changed = note, wasOpen
+		if !wasOpen {
+			return nil
+		}
+
+		updated.Content = content
+		updated.ModifiedAt = time.Now()
+		updated.EditCount++
+		return db.PutNote(tx, updated)
+	})
+
+	return updated, changed, err
+}
+
+// findNote returns the note identified by handle: a unique ID prefix, or a
+// title in notebook or, failing that, in exactly one other notebook.
+func findNote(tx *bolt.Tx, notebook, handle string) (models.Note, error) {
+	note, err := db.LookupNote(tx, notebook, handle)
+	if !errors.Is(err, db.ErrNoteNotFound) {
+		return note, err
+	}
+
+	notFound := err
+
+	notebooks, err := db.ListNotebooks(tx)
+	if err != nil {
+		return models.Note{}, err
+	}
+	var matches, names []string
+	for _, other := range notebooks {
+		titles, err := db.NotebookTitles(tx, other.Name)
+		if err != nil {
+			return models.Note{}, err
+		}
+		if noteID := titles.Get(db.TitleKey(handle)); noteID != nil {
+			matches = append(matches, string(noteID))
+			names = append(names, other.Name)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return models.Note{}, notFound
+	case 1:
+		return db.GetNote(tx, matches[0])
+	default:
+		return models.Note{}, fmt.Errorf("notebooks %s all have a note titled %q; choose one with --notebook", strings.Join(names, ", "), handle)
+	}
+}
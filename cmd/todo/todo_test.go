@@ -0,0 +1,102 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/cmd/new"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestMarkDoneUpdatesNote(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	note := testutil.CreateTestNote()
+	note.Content = "- [ ] milk\n- [ ] bread"
+	if err := new.StoreNoteInDB(note, testDB); err != nil {
+		t.Fatalf("Error adding note to database: %v", err)
+	}
+
+	openItems, err := openTodos(testDB)
+	if err != nil {
+		t.Fatalf("openTodos() error = %v", err)
+	}
+	if len(openItems) != 1 || len(openItems[0].items) != 2 {
+		t.Fatalf("Expected 2 open items in 1 note; got %+v", openItems)
+	}
+
+	updated, changed, err := markDone(note.Title, db.DefaultNotebook, 2, testDB)
+	if err != nil {
+		t.Fatalf("markDone() error = %v", err)
+	}
+	if !changed {
+		t.Error("Expected the item to be ticked off")
+	}
+	if want := "- [ ] milk\n- [x] bread"; updated.Content != want {
+		t.Errorf("Content = %q; want %q", updated.Content, want)
+	}
+	if !updated.ModifiedAt.After(note.ModifiedAt) {
+		t.Error("Expected the modification time to be updated")
+	}
+	if updated.EditCount != note.EditCount+1 {
+		t.Errorf("EditCount = %d; want %d", updated.EditCount, note.EditCount+1)
+	}
+	testutil.TestNoteContentSaved(t, updated, testDB)
+
+	if _, changed, _ := markDone(note.Title, db.DefaultNotebook, 2, testDB); changed {
+		t.Error("Ticking off a done item should not change the note")
+	}
+}
+
+func TestMarkDoneFindsNotesInOtherNotebooks(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+
+	var notes []models.Note
+	for _, notebook := range []string{"work", "home"} {
+		err := testDB.Update(func(tx *bolt.Tx) error {
+			_, err := db.CreateNotebook(tx, notebook)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Error creating notebook: %v", err)
+		}
+		note := testutil.CreateTestNote()
+		note.Notebook = notebook
+		note.Title = "chores"
+		note.Content = "- [ ] " + notebook
+		if notebook == "work" {
+			note.Title = "standup"
+		}
+		if err := new.StoreNoteInDB(note, testDB); err != nil {
+			t.Fatalf("Error adding note to database: %v", err)
+		}
+		notes = append(notes, note)
+	}
+
+	// The only "standup" note is in another notebook than the current one
+	updated, changed, err := markDone("standup", db.DefaultNotebook, 1, testDB)
+	if err != nil {
+		t.Fatalf("markDone() error = %v", err)
+	}
+	if !changed || updated.ID != notes[0].ID {
+		t.Errorf("markDone() = %+v, %v; want the work note ticked off", updated, changed)
+	}
+
+	// A second "chores" note makes the title ambiguous outside its notebook
+	note := testutil.CreateTestNote()
+	note.Title = "chores"
+	note.Notebook = "work"
+	if err := new.StoreNoteInDB(note, testDB); err != nil {
+		t.Fatalf("Error adding note to database: %v", err)
+	}
+	if _, _, err := markDone("chores", db.DefaultNotebook, 1, testDB); err == nil || !strings.Contains(err.Error(), "--notebook") {
+		t.Errorf("markDone() of an ambiguous title error = %v; want a hint to use --notebook", err)
+	}
+	if _, changed, err := markDone("chores", "home", 1, testDB); err != nil || !changed {
+		t.Errorf("markDone() in the note's notebook = %v, %v; want the item ticked off", changed, err)
+	}
+}
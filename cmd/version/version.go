@@ -13,6 +13,7 @@ const (
 
 func init() {
 	VersionCmd := VersionCmd
+	root.UseServer(VersionCmd)
 	root.RootCmd.AddCommand(VersionCmd)
 }
 
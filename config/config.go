@@ -0,0 +1,102 @@
+// Package config loads and saves the user's settings file.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	configFile           = "config.json"
+	ReadWritePermissions = 0600
+
+	// DefaultJournalTitleFormat is the Go time layout used for journal titles.
+	// It must only produce characters that are valid in note titles.
+	DefaultJournalTitleFormat = "2006-01-02"
+)
+
+// Config holds the user's settings. It is stored as JSON in the notes directory.
+// Unset fields fall back to the application defaults.
+type Config struct {
+	// DefaultNotebook is the notebook used when --notebook is not given.
+	DefaultNotebook string `json:"default_notebook,omitempty"`
+
+	// JournalTitleFormat is the Go time layout used to title journal entries,
+	// e.g. "2006-01-02" or "Mon 02 Jan 2006".
+	JournalTitleFormat string `json:"journal_title_format,omitempty"`
+
+	// JournalTemplate is the name of the template used for new journal entries.
+	JournalTemplate string `json:"journal_template,omitempty"`
+
+	// DateFormat is how dates are shown in lists: "relative", "iso",
+	// "rfc3339" or a Go time layout. See dates.NewFormatter.
+	DateFormat string `json:"date_format,omitempty"`
+
+	// Timezone is the time zone dates are shown in, e.g. "UTC" or
+	// "Europe/Paris". Dates are shown in local time when it is unset.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Color is when output is colored: "auto" (the default), "always" or "never".
+	Color string `json:"color,omitempty"`
+
+	// Theme maps output roles, such as "header" or "overdue", to styles
+	// such as "bold red". See output.Theme.
+	Theme map[string]string `json:"theme,omitempty"`
+
+	// MirrorDirectory is the directory 'cli-note mirror sync' syncs by
+	// default, set by 'cli-note mirror <dir>'.
+	MirrorDirectory string `json:"mirror_directory,omitempty"`
+
+	// GitDirectory is the repository 'cli-note git sync' syncs by default,
+	// set by 'cli-note git init <dir>'.
+	GitDirectory string `json:"git_directory,omitempty"`
+}
+
+// JournalTitleLayout returns the configured journal title layout,
+// or DefaultJournalTitleFormat if none is set.
+func (c Config) JournalTitleLayout() string {
+	if c.JournalTitleFormat == "" {
+		return DefaultJournalTitleFormat
+	}
+	return c.JournalTitleFormat
+}
+
+// Path returns the location of the settings file within the notes directory.
+func Path(notesDirectory string) string {
+	return filepath.Join(notesDirectory, configFile)
+}
+
+// Load reads the settings file at path.
+// A missing file is not an error; it yields the default settings.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the settings to the file at path, replacing its contents.
+func (c Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config as JSON: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), ReadWritePermissions); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
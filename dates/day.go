@@ -0,0 +1,54 @@
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DayFormat   = "2006-01-02"
+	MonthFormat = "2006-01"
+)
+
+// StartOfDay returns midnight at the start of t's day, in t's location.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// ParseDay parses a day given as "today", "yesterday", "tomorrow" or
+// YYYY-MM-DD, relative to now and in now's location.
+// The returned time is midnight at the start of that day.
+func ParseDay(input string, now time.Time) (time.Time, error) {
+	today := StartOfDay(now)
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	day, err := time.ParseInLocation(DayFormat, strings.TrimSpace(input), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use today, yesterday, tomorrow or YYYY-MM-DD", input)
+	}
+	return day, nil
+}
+
+// ParseMonth parses a month given as YYYY-MM, in now's location; an empty
+// input means the current month. The returned time is the start of the month.
+func ParseMonth(input string, now time.Time) (time.Time, error) {
+	if strings.TrimSpace(input) == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), nil
+	}
+
+	month, err := time.ParseInLocation(MonthFormat, strings.TrimSpace(input), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q: use YYYY-MM", input)
+	}
+	return month, nil
+}
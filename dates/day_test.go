@@ -0,0 +1,54 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "today", want: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)},
+		{input: "", want: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)},
+		{input: "Yesterday", want: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)},
+		{input: "tomorrow", want: time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)},
+		{input: "2026-02-28", want: time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{input: "2026/02/28", wantErr: true},
+		{input: "someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDay(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDay(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDay(%q) = %v; want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 15, 4, 0, 0, time.UTC)
+
+	got, err := ParseMonth("", now)
+	if err != nil || !got.Equal(time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseMonth(\"\") = %v, %v; want start of current month", got, err)
+	}
+
+	got, err = ParseMonth("2025-02", now)
+	if err != nil || !got.Equal(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseMonth(%q) = %v, %v", "2025-02", got, err)
+	}
+
+	if _, err := ParseMonth("02-2025", now); err == nil {
+		t.Error("Expected error for invalid month")
+	}
+}
@@ -0,0 +1,55 @@
+// Package dates provides parsing helpers for the human-friendly dates and
+// durations accepted on the command line.
+package dates
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// calendarUnits maps the units that time.ParseDuration does not understand
+// to their length. Months and years are approximations.
+var calendarUnits = []struct {
+	suffix string
+	length time.Duration
+}{
+	{"mo", 30 * Day},
+	{"d", Day},
+	{"w", Week},
+	{"y", 365 * Day},
+}
+
+// ParseDuration parses a duration such as "30d", "2w" or "12h".
+// In addition to the units accepted by time.ParseDuration, it accepts
+// d (days), w (weeks), mo (30-day months) and y (365-day years).
+func ParseDuration(input string) (time.Duration, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	for _, unit := range calendarUnits {
+		if !strings.HasSuffix(input, unit.suffix) {
+			continue
+		}
+		amount, err := strconv.Atoi(strings.TrimSuffix(input, unit.suffix))
+		if err != nil || amount < 0 {
+			return 0, fmt.Errorf("invalid duration %q", input)
+		}
+		if int64(amount) > math.MaxInt64/int64(unit.length) {
+			return 0, fmt.Errorf("duration %q is too long", input)
+		}
+		return time.Duration(amount) * unit.length, nil
+	}
+
+	duration, err := time.ParseDuration(input)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q: use a number followed by h, d, w, mo or y (e.g. 30d)", input)
+	}
+	return duration, nil
+}
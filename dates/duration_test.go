@@ -0,0 +1,41 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30d", want: 30 * Day},
+		{input: "2w", want: 2 * Week},
+		{input: "3mo", want: 90 * Day},
+		{input: "1y", want: 365 * Day},
+		{input: "12h", want: 12 * time.Hour},
+		{input: "90m", want: 90 * time.Minute},
+		{input: " 1D ", want: Day},
+		{input: "", wantErr: true},
+		{input: "d", wantErr: true},
+		{input: "-1d", wantErr: true},
+		{input: "soon", wantErr: true},
+		{input: "292y", want: 292 * 365 * Day},
+		{input: "293y", wantErr: true},
+		{input: "999999y", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v; want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Date format presets accepted by NewFormatter.
+const (
+	FormatDefault  = "default"
+	FormatRelative = "relative"
+	FormatISO      = "iso"
+	FormatRFC3339  = "rfc3339"
+
+	defaultLayout = "Jan 02, 2006 15:04"
+	isoLayout     = "2006-01-02 15:04"
+)
+
+// Formatter formats the dates shown in tables.
+// The zero value formats dates with the default layout in local time.
+type Formatter struct {
+	// Layout is the Go time layout used, unless Relative is set.
+	Layout string
+	// Relative formats dates relative to Now, such as "3h ago".
+	Relative bool
+	// Location is the time zone dates are shown in; nil means local time.
+	Location *time.Location
+	// Now is the time relative dates are measured from; zero means time.Now().
+	Now time.Time
+}
+
+// NewFormatter returns a Formatter for a format and a time zone.
+// The format is a preset (default, relative, iso or rfc3339) or a custom
+// Go time layout such as "02/01/2006 15:04"; an empty format is the default.
+// The time zone is "UTC", "Local" or an IANA name such as "Europe/Paris";
+// an empty time zone is local time.
+func NewFormatter(format, timezone string) (Formatter, error) {
+	var formatter Formatter
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatDefault:
+		formatter.Layout = defaultLayout
+	case FormatRelative:
+		formatter.Relative = true
+	case FormatISO:
+		formatter.Layout = isoLayout
+	case FormatRFC3339:
+		formatter.Layout = time.RFC3339
+	default:
+		// A layout without any layout elements formats every date the same way
+		reference := time.Date(2001, time.November, 12, 13, 14, 15, 0, time.UTC)
+		if reference.Format(format) == format {
+			return Formatter{}, fmt.Errorf("invalid date format %q: use relative, iso, rfc3339 or a Go time layout such as \"2006-01-02 15:04\"", format)
+		}
+		formatter.Layout = format
+	}
+
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return Formatter{}, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+		}
+		formatter.Location = location
+	}
+	return formatter, nil
+}
+
+// Format formats t.
+func (f Formatter) Format(t time.Time) string {
+	if f.Relative {
+		now := f.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		return Relative(t, now)
+	}
+
+	if f.Location != nil {
+		t = t.In(f.Location)
+	} else {
+		t = t.Local()
+	}
+	layout := f.Layout
+	if layout == "" {
+		layout = defaultLayout
+	}
+	return t.Format(layout)
+}
+
+// Relative describes t relative to now in the largest whole unit,
+// such as "just now", "5m ago", "3h ago", "2d ago" or "in 3w".
+func Relative(t, now time.Time) string {
+	difference := now.Sub(t)
+	future := difference < 0
+	if future {
+		difference = -difference
+	}
+	if difference < time.Minute {
+		return "just now"
+	}
+
+	var amount string
+	switch {
+	case difference < time.Hour:
+		amount = fmt.Sprintf("%dm", difference/time.Minute)
+	case difference < Day:
+		amount = fmt.Sprintf("%dh", difference/time.Hour)
+	case difference < Week:
+		amount = fmt.Sprintf("%dd", difference/Day)
+	case difference < 30*Day:
+		amount = fmt.Sprintf("%dw", difference/Week)
+	case difference < 365*Day:
+		amount = fmt.Sprintf("%dmo", difference/(30*Day))
+	default:
+		amount = fmt.Sprintf("%dy", difference/(365*Day))
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
@@ -0,0 +1,65 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatter(t *testing.T) {
+	at := time.Date(2026, time.October, 19, 13, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		format   string
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{format: "", timezone: "UTC", want: "Oct 19, 2026 13:04"},
+		{format: "iso", timezone: "UTC", want: "2026-10-19 13:04"},
+		{format: "RFC3339", timezone: "UTC", want: "2026-10-19T13:04:00Z"},
+		{format: "02/01/2006", timezone: "UTC", want: "19/10/2026"},
+		{format: "iso", timezone: "Asia/Tokyo", want: "2026-10-19 22:04"},
+		{format: "relative", want: "3h ago"},
+		{format: "no layout here", wantErr: true},
+		{format: "iso", timezone: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.timezone, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, tt.timezone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewFormatter(%q, %q) error = %v, wantErr %v", tt.format, tt.timezone, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			formatter.Now = at.Add(3 * time.Hour)
+			if got := formatter.Format(at); got != tt.want {
+				t.Errorf("Format() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelative(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 13, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{at: now.Add(-30 * time.Second), want: "just now"},
+		{at: now.Add(-5 * time.Minute), want: "5m ago"},
+		{at: now.Add(-26 * time.Hour), want: "1d ago"},
+		{at: now.AddDate(0, 0, -15), want: "2w ago"},
+		{at: now.AddDate(0, -3, 0), want: "3mo ago"},
+		{at: now.AddDate(-2, 0, 0), want: "2y ago"},
+		{at: now.Add(3 * time.Hour), want: "in 3h"},
+	}
+
+	for _, tt := range tests {
+		if got := Relative(tt.at, now); got != tt.want {
+			t.Errorf("Relative(%v) = %q; want %q", tt.at, got, tt.want)
+		}
+	}
+}
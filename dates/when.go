@@ -0,0 +1,138 @@
+package dates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdays maps lower-case weekday names and their abbreviations to weekdays.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseWhen parses a point in time relative to now, in now's location.
+// It accepts:
+//
+//   - an offset from now: "+3d", "+2w", "+90m" (see ParseDuration)
+//   - a day: "today", "tomorrow", "2026-11-01" (see ParseDay) or a weekday
+//     name such as "friday", meaning the next one after today
+//   - a time of day: "9am", "5:30pm", "14:00", "noon"
+//   - a day followed by a time of day: "tomorrow 9am", "2026-11-01 14:00"
+//
+// A day without a time means the start of that day, and a time without
+// a day means that time today.
+func ParseWhen(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("missing date or time")
+	}
+
+	if offset, ok := strings.CutPrefix(input, "+"); ok {
+		duration, err := ParseDuration(offset)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(duration), nil
+	}
+
+	fields := strings.Fields(input)
+	if len(fields) > 2 {
+		return time.Time{}, fmt.Errorf("invalid date %q: use a day, a time, or a day and a time", input)
+	}
+
+	if len(fields) == 1 {
+		if hour, minute, err := parseTimeOfDay(fields[0]); err == nil {
+			return atTime(StartOfDay(now), hour, minute), nil
+		}
+		return parseWhenDay(fields[0], now)
+	}
+
+	day, err := parseWhenDay(fields[0], now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, err := parseTimeOfDay(fields[1])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return atTime(day, hour, minute), nil
+}
+
+// parseWhenDay parses a day as accepted by ParseDay, or a weekday name.
+func parseWhenDay(input string, now time.Time) (time.Time, error) {
+	weekday, ok := weekdays[input]
+	if !ok {
+		return ParseDay(input, now)
+	}
+
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return StartOfDay(now).AddDate(0, 0, days), nil
+}
+
+// parseTimeOfDay parses "9am", "9:30pm", "14:00", "noon" or "midnight"
+// into an hour and minute.
+func parseTimeOfDay(input string) (hour, minute int, err error) {
+	switch input {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	invalid := fmt.Errorf("invalid time %q: use e.g. 9am, 5:30pm or 14:00", input)
+
+	clock, meridiem := input, ""
+	for _, suffix := range []string{"am", "pm"} {
+		if trimmed, ok := strings.CutSuffix(input, suffix); ok {
+			clock, meridiem = trimmed, suffix
+		}
+	}
+
+	hourText, minuteText, hasMinutes := strings.Cut(clock, ":")
+	if !hasMinutes && meridiem == "" {
+		return 0, 0, invalid
+	}
+	if hour, err = strconv.Atoi(hourText); err != nil {
+		return 0, 0, invalid
+	}
+	if hasMinutes {
+		if len(minuteText) != 2 {
+			return 0, 0, invalid
+		}
+		if minute, err = strconv.Atoi(minuteText); err != nil || minute > 59 {
+			return 0, 0, invalid
+		}
+	}
+
+	switch meridiem {
+	case "":
+		if hour < 0 || hour > 23 {
+			return 0, 0, invalid
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0, invalid
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, nil
+}
+
+// atTime returns the given time of day on day, which must be the start of a day.
+func atTime(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,47 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	// A Monday afternoon
+	now := time.Date(2026, time.October, 19, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "+3d", want: now.Add(3 * Day)},
+		{input: "+90m", want: now.Add(90 * time.Minute)},
+		{input: "tomorrow", want: time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)},
+		{input: "2026-11-01", want: time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "Tomorrow 9am", want: time.Date(2026, time.October, 20, 9, 0, 0, 0, time.UTC)},
+		{input: "2026-11-01 14:30", want: time.Date(2026, time.November, 1, 14, 30, 0, 0, time.UTC)},
+		{input: "5:30pm", want: time.Date(2026, time.October, 19, 17, 30, 0, 0, time.UTC)},
+		{input: "12am", want: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)},
+		{input: "today noon", want: time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)},
+		{input: "friday", want: time.Date(2026, time.October, 23, 0, 0, 0, 0, time.UTC)},
+		{input: "mon 8am", want: time.Date(2026, time.October, 26, 8, 0, 0, 0, time.UTC)},
+		{input: "", wantErr: true},
+		{input: "+soon", wantErr: true},
+		{input: "tomorrow 25:00", wantErr: true},
+		{input: "13pm", wantErr: true},
+		{input: "9", wantErr: true},
+		{input: "next tuesday at 9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWhen(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWhen(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseWhen(%q) = %v; want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -16,13 +16,18 @@ const (
 	ReadWritePermissions = 0600
 	NotesBucket          = "Notes"
 	NotesTitleBucket     = "NotesTitle"
+	NotebooksBucket      = "Notebooks"
+	TemplatesBucket      = "Templates"
+	LinksBucket          = "Links"
+	RecurrencesBucket    = "Recurrences"
+	MetaBucket           = "Meta"
 )
 
 // Initialize sets up and returns a new BoltDB instance for storing notes.
 // It creates the directory structure and database file if they don't exist.
 // If userPath is empty, it defaults to the user's home directory.
 func Initialize(userPath string) (*bolt.DB, error) {
-	notesDirectory, err := defineNotesDirectory(userPath)
+	notesDirectory, err := NotesDirectory(userPath)
 	if err != nil {
 		return nil, fmt.Errorf("error creating notes directory: %w", err)
 	}
@@ -39,10 +44,10 @@ func Initialize(userPath string) (*bolt.DB, error) {
 	return db, nil
 }
 
-// defineNotesDirectory determines the directory path where notes will be stored.
+// NotesDirectory determines the directory path where notes will be stored.
 // If userPath is empty, it uses the user's home directory with a '.notes' subdirectory.
 // Otherwise, it creates a '.notes' subdirectory in the specified userPath.
-func defineNotesDirectory(userPath string) (string, error) {
+func NotesDirectory(userPath string) (string, error) {
 	if userPath == "" {
 		userHomeDir, err := os.UserHomeDir()
 		if err != nil {
@@ -66,6 +71,9 @@ func setupNotesDB(dbFile string) (*bolt.DB, error) {
 	if err := createNoteTitleBucket(db); err != nil {
 		return nil, err
 	}
+	if err := createTemplatesBucket(db); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
 
@@ -90,3 +98,13 @@ func createNoteTitleBucket(db *bolt.DB) error {
 		return nil
 	})
 }
+
+func createTemplatesBucket(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(TemplatesBucket))
+		if err != nil {
+			return fmt.Errorf("error creating %q bucket: %w", TemplatesBucket, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,296 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/links"
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// danglingPrefix marks link keys that don't point at an existing note.
+// Note IDs never start with it, so dangling and resolved keys can't clash.
+const danglingPrefix = "?"
+
+// NoteLink is a [[link]] stored in the link index.
+type NoteLink struct {
+	// Target is the link target as written, e.g. "Standup" or "work/Standup".
+	Target string
+	// TargetID is the ID of the linked note, or empty if the link is dangling.
+	TargetID string
+}
+
+// Dangling reports whether the link points at a note that doesn't exist.
+func (l NoteLink) Dangling() bool {
+	return l.TargetID == ""
+}
+
+// IndexLinks parses the [[links]] in a note's content and replaces the
+// note's entry in the LinksBucket. Each source note has a nested bucket
+// mapping target note IDs (or dangling targets) to the link as written.
+func IndexLinks(tx *bolt.Tx, note models.Note) error {
+	linksBucket, err := linksBucket(tx)
+	if err != nil {
+		return err
+	}
+	if linksBucket.Bucket([]byte(note.ID)) != nil {
+		if err := linksBucket.DeleteBucket([]byte(note.ID)); err != nil {
+			return fmt.Errorf("error clearing links of note %q: %w", note.Title, err)
+		}
+	}
+
+	noteLinks := links.Parse(note.Content, NormalizeTitle)
+	if len(noteLinks) == 0 {
+		return nil
+	}
+
+	sourceBucket, err := linksBucket.CreateBucket([]byte(note.ID))
+	if err != nil {
+		return fmt.Errorf("error storing links of note %q: %w", note.Title, err)
+	}
+	for _, link := range noteLinks {
+		key, err := linkKey(tx, note, link)
+		if err != nil {
+			return err
+		}
+		if err := sourceBucket.Put(key, []byte(link.Target())); err != nil {
+			return fmt.Errorf("error storing link %s: %w", link, err)
+		}
+	}
+	return nil
+}
+
+// linkKey returns the key under which a link from note is indexed:
+// the target note's ID, or a dangling key naming its notebook and title.
+func linkKey(tx *bolt.Tx, note models.Note, link links.Link) ([]byte, error) {
+	notebook := link.Notebook
+	if notebook == "" {
+		notebook = note.Notebook
+	}
+
+	titles, err := NotebookTitles(tx, notebook)
+	if err == nil {
+		if targetID := titles.Get(TitleKey(link.Title)); targetID != nil {
+			return targetID, nil
+		}
+	} else if !errors.Is(err, ErrNotebookNotFound) {
+		return nil, err
+	}
+	return danglingKey(notebook, link.Title), nil
+}
+
+// danglingKey returns the key of a dangling link to title in notebook.
+func danglingKey(notebook, title string) []byte {
+	return []byte(danglingPrefix + NormalizeTitle(notebook) + "/" + NormalizeTitle(title))
+}
+
+// OutboundLinks returns the links from the note with the given ID.
+func OutboundLinks(tx *bolt.Tx, noteID string) ([]NoteLink, error) {
+	linksBucket, err := linksBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+	sourceBucket := linksBucket.Bucket([]byte(noteID))
+	if sourceBucket == nil {
+		return nil, nil
+	}
+
+	var noteLinks []NoteLink
+	err = sourceBucket.ForEach(func(k, v []byte) error {
+		link := NoteLink{Target: string(v)}
+		if !bytes.HasPrefix(k, []byte(danglingPrefix)) {
+			link.TargetID = string(k)
+		}
+		noteLinks = append(noteLinks, link)
+		return nil
+	})
+	return noteLinks, err
+}
+
+// Backlinks returns the IDs of the notes that link to the note with the given ID.
+func Backlinks(tx *bolt.Tx, noteID string) ([]string, error) {
+	linksBucket, err := linksBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceIDs []string
+	err = linksBucket.ForEachBucket(func(sourceID []byte) error {
+		if linksBucket.Bucket(sourceID).Get([]byte(noteID)) != nil {
+			sourceIDs = append(sourceIDs, string(sourceID))
+		}
+		return nil
+	})
+	return sourceIDs, err
+}
+
+// DanglingLinks returns, for every note with dangling links, the targets
+// of those links as written, keyed by the note's ID.
+func DanglingLinks(tx *bolt.Tx) (map[string][]string, error) {
+	linksBucket, err := linksBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	dangling := make(map[string][]string)
+	err = linksBucket.ForEachBucket(func(sourceID []byte) error {
+		return linksBucket.Bucket(sourceID).ForEach(func(k, v []byte) error {
+			if bytes.HasPrefix(k, []byte(danglingPrefix)) {
+				dangling[string(sourceID)] = append(dangling[string(sourceID)], string(v))
+			}
+			return nil
+		})
+	})
+	return dangling, err
+}
+
+// ResolveDanglingLinks points dangling links that name the note's notebook
+// and title at the note. It is called whenever a note gains a title mapping.
+func ResolveDanglingLinks(tx *bolt.Tx, note models.Note) error {
+	return relink(tx, danglingKey(note.Notebook, note.Title), []byte(note.ID))
+}
+
+// UnlinkNote removes a note's outbound links and turns links pointing at it
+// into dangling links. It is called when a note is deleted.
+func UnlinkNote(tx *bolt.Tx, note models.Note) error {
+	linksBucket, err := linksBucket(tx)
+	if err != nil {
+		return err
+	}
+	if linksBucket.Bucket([]byte(note.ID)) != nil {
+		if err := linksBucket.DeleteBucket([]byte(note.ID)); err != nil {
+			return fmt.Errorf("error removing links of note %q: %w", note.Title, err)
+		}
+	}
+	return relink(tx, []byte(note.ID), danglingKey(note.Notebook, note.Title))
+}
+
+// relink replaces the key oldKey with newKey in every source note's links.
+func relink(tx *bolt.Tx, oldKey, newKey []byte) error {
+	linksBucket, err := linksBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	var sources [][]byte
+	err = linksBucket.ForEachBucket(func(sourceID []byte) error {
+		if linksBucket.Bucket(sourceID).Get(oldKey) != nil {
+			sources = append(sources, append([]byte(nil), sourceID...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sourceID := range sources {
+		sourceBucket := linksBucket.Bucket(sourceID)
+		target := append([]byte(nil), sourceBucket.Get(oldKey)...)
+		if err := sourceBucket.Delete(oldKey); err != nil {
+			return fmt.Errorf("error updating links of note %s: %w", sourceID, err)
+		}
+		if err := sourceBucket.Put(newKey, target); err != nil {
+			return fmt.Errorf("error updating links of note %s: %w", sourceID, err)
+		}
+	}
+	return nil
+}
+
+// RenameNote changes a note's title within its notebook and rewrites the
+// [[links]] in every note linking to it, so they keep showing the right title.
+// It returns the renamed note and the notes whose content was rewritten.
+func RenameNote(tx *bolt.Tx, note models.Note, newTitle string) (models.Note, []models.Note, error) {
+	titles, err := NotebookTitles(tx, note.Notebook)
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+	if existing := titles.Get(TitleKey(newTitle)); existing != nil && string(existing) != note.ID {
+		return models.Note{}, nil, fmt.Errorf("note %q already exists in notebook %q", newTitle, note.Notebook)
+	}
+
+	oldTitle := note.Title
+	if string(titles.Get(TitleKey(oldTitle))) == note.ID {
+		if err := titles.Delete(TitleKey(oldTitle)); err != nil {
+			return models.Note{}, nil, fmt.Errorf("error removing title mapping for %q: %w", oldTitle, err)
+		}
+	}
+	if err := titles.Put(TitleKey(newTitle), []byte(note.ID)); err != nil {
+		return models.Note{}, nil, fmt.Errorf("error storing title %q: %w", newTitle, err)
+	}
+
+	note.Title = newTitle
+	note.ModifiedAt = time.Now()
+	if err := PutNote(tx, note); err != nil {
+		return models.Note{}, nil, err
+	}
+
+	sourceIDs, err := Backlinks(tx, note.ID)
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+	rewritten, err := rewriteLinks(tx, sourceIDs, func(source models.Note, link links.Link) (links.Link, bool) {
+		if !linksTo(source, link, note.Notebook, oldTitle) {
+			return link, false
+		}
+		link.Title = newTitle
+		return link, true
+	})
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+
+	if err := ResolveDanglingLinks(tx, note); err != nil {
+		return models.Note{}, nil, err
+	}
+	return note, rewritten, nil
+}
+
+// linksTo reports whether a link in source names the note titled title in
+// notebook. Links without a notebook name a note in source's notebook.
+func linksTo(source models.Note, link links.Link, notebook, title string) bool {
+	linkNotebook := link.Notebook
+	if linkNotebook == "" {
+		linkNotebook = source.Notebook
+	}
+	return NormalizeTitle(link.Title) == NormalizeTitle(title) &&
+		NormalizeTitle(linkNotebook) == NormalizeTitle(notebook)
+}
+
+// rewriteLinks rewrites the [[links]] in the notes with the given IDs with
+// rewrite, as links.RewriteFunc does, and stores the notes that changed.
+// It returns the notes whose content was rewritten.
+func rewriteLinks(tx *bolt.Tx, noteIDs []string, rewrite func(source models.Note, link links.Link) (links.Link, bool)) ([]models.Note, error) {
+	var rewritten []models.Note
+	for _, noteID := range noteIDs {
+		source, err := GetNote(tx, noteID)
+		if err != nil {
+			return nil, err
+		}
+
+		content := links.RewriteFunc(source.Content, func(link links.Link) (links.Link, bool) {
+			return rewrite(source, link)
+		})
+		if content == source.Content {
+			continue
+		}
+		source.Content = content
+		source.ModifiedAt = time.Now()
+		if err := PutNote(tx, source); err != nil {
+			return nil, err
+		}
+		rewritten = append(rewritten, source)
+	}
+	return rewritten, nil
+}
+
+// linksBucket returns the LinksBucket, or an error if it doesn't exist.
+func linksBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	bucket := tx.Bucket([]byte(LinksBucket))
+	if bucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", LinksBucket)
+	}
+	return bucket, nil
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestMigrateIndexesLinks(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{
+		{ID: "1", Title: "plan", Content: "See [[Budget]] and [[Risks]]."},
+		{ID: "2", Title: "budget"},
+	})
+
+	err := database.View(func(tx *bolt.Tx) error {
+		noteLinks, err := OutboundLinks(tx, "1")
+		if err != nil {
+			return err
+		}
+		if len(noteLinks) != 2 {
+			t.Fatalf("Got %d links; want 2", len(noteLinks))
+		}
+		for _, link := range noteLinks {
+			switch link.Target {
+			case "Budget":
+				if link.TargetID != "2" {
+					t.Errorf("Link to Budget points at %q; want %q", link.TargetID, "2")
+				}
+			case "Risks":
+				if !link.Dangling() {
+					t.Errorf("Link to Risks should be dangling; points at %q", link.TargetID)
+				}
+			default:
+				t.Errorf("Unexpected link target %q", link.Target)
+			}
+		}
+
+		sources, err := Backlinks(tx, "2")
+		if err != nil {
+			return err
+		}
+		if len(sources) != 1 || sources[0] != "1" {
+			t.Errorf("Backlinks = %v; want [1]", sources)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't read links: %v", err)
+	}
+}
+
+func TestDanglingLinksResolveAndUnlink(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{
+		{ID: "1", Title: "plan", Content: "See [[work/Standup]]."},
+	})
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		dangling, err := DanglingLinks(tx)
+		if err != nil {
+			return err
+		}
+		if len(dangling["1"]) != 1 || dangling["1"][0] != "work/Standup" {
+			t.Errorf("DanglingLinks = %v; want work/Standup from note 1", dangling)
+		}
+
+		// Creating the target note resolves the link
+		if _, err := CreateNotebook(tx, "work"); err != nil {
+			return err
+		}
+		standup := models.Note{ID: "2", Title: "standup", Notebook: "work"}
+		if err := PutNote(tx, standup); err != nil {
+			return err
+		}
+		titles, err := NotebookTitles(tx, "work")
+		if err != nil {
+			return err
+		}
+		if err := titles.Put(TitleKey(standup.Title), []byte(standup.ID)); err != nil {
+			return err
+		}
+		if err := ResolveDanglingLinks(tx, standup); err != nil {
+			return err
+		}
+		if sources, _ := Backlinks(tx, "2"); len(sources) != 1 {
+			t.Errorf("Expected the dangling link to resolve; backlinks = %v", sources)
+		}
+
+		// Deleting it makes the link dangle again
+		if err := UnlinkNote(tx, standup); err != nil {
+			return err
+		}
+		dangling, err = DanglingLinks(tx)
+		if err != nil {
+			return err
+		}
+		if len(dangling["1"]) != 1 {
+			t.Errorf("Expected the link to dangle after unlinking; got %v", dangling)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't update links: %v", err)
+	}
+}
+
+func TestRenameNoteRewritesLinks(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{
+		{ID: "1", Title: "plan", Content: "See [[Budget]] and [[budget|the money]]."},
+		{ID: "2", Title: "budget"},
+		{ID: "3", Title: "other", Content: "Unrelated [[Plan]]."},
+	})
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		note, err := GetNote(tx, "2")
+		if err != nil {
+			return err
+		}
+		if _, _, err := RenameNote(tx, note, "plan"); err == nil {
+			t.Error("Expected error renaming a note to an existing title")
+		}
+
+		renamed, rewritten, err := RenameNote(tx, note, "Finances")
+		if err != nil {
+			return err
+		}
+		if renamed.Title != "Finances" {
+			t.Errorf("Renamed title = %q; want %q", renamed.Title, "Finances")
+		}
+		if len(rewritten) != 1 || rewritten[0].ID != "1" {
+			t.Fatalf("Rewritten notes = %v; want note 1", rewritten)
+		}
+
+		plan, err := GetNote(tx, "1")
+		if err != nil {
+			return err
+		}
+		want := "See [[Finances]] and [[Finances|the money]]."
+		if plan.Content != want {
+			t.Errorf("Rewritten content = %q; want %q", plan.Content, want)
+		}
+
+		if _, err := LookupNote(tx, DefaultNotebook, "finances"); err != nil {
+			t.Errorf("Expected note under its new title; got %v", err)
+		}
+		if sources, _ := Backlinks(tx, "2"); len(sources) != 1 {
+			t.Errorf("Expected the link to survive the rename; backlinks = %v", sources)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't rename note: %v", err)
+	}
+}
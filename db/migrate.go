@@ -0,0 +1,240 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+const schemaVersionKey = "schema_version"
+
+// migration upgrades the database by one schema version.
+// It returns human-readable warnings about records that need the user's attention.
+type migration func(tx *bolt.Tx) ([]string, error)
+
+// migrations lists every schema migration in the order it must be applied.
+// The schema version stored in the MetaBucket is the number of migrations
+// that have already run, so new migrations must only ever be appended.
+var migrations = []migration{
+	rebuildTitleIndex,
+	assignDefaultNotebook,
+	indexLinks,
+	addNoteStates,
+	createRecurrencesBucket,
+}
+
+// Migrate applies any outstanding schema migrations in a single transaction.
+// It returns the warnings reported by the migrations that ran.
+func Migrate(database *bolt.DB) ([]string, error) {
+	var warnings []string
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists([]byte(MetaBucket))
+		if err != nil {
+			return fmt.Errorf("error creating %q bucket: %w", MetaBucket, err)
+		}
+
+		version, err := schemaVersion(meta)
+		if err != nil {
+			return err
+		}
+
+		for i := version; i < len(migrations); i++ {
+			migrationWarnings, err := migrations[i](tx)
+			if err != nil {
+				return fmt.Errorf("error applying migration %d: %w", i+1, err)
+			}
+			warnings = append(warnings, migrationWarnings...)
+		}
+
+		return meta.Put([]byte(schemaVersionKey), []byte(strconv.Itoa(len(migrations))))
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error migrating database: %w", err)
+	}
+	return warnings, nil
+}
+
+// schemaVersion reads the current schema version from the meta bucket.
+// A database without a stored version is at version 0.
+func schemaVersion(meta *bolt.Bucket) (int, error) {
+	value := meta.Get([]byte(schemaVersionKey))
+	if value == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", value, err)
+	}
+	return version, nil
+}
+
+// rebuildTitleIndex re-keys the NotesTitleBucket using normalized titles.
+// Notes whose titles only differ by case or Unicode normalization now collide;
+// the oldest note keeps the title mapping and the others are reported.
+func rebuildTitleIndex(tx *bolt.Tx) ([]string, error) {
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	if notesBucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", NotesBucket)
+	}
+
+	notesByKey := make(map[string][]models.Note)
+	err := notesBucket.ForEach(func(k, v []byte) error {
+		var note models.Note
+		if err := json.Unmarshal(v, &note); err != nil {
+			return fmt.Errorf("error reading note %s: %w", k, err)
+		}
+		key := NormalizeTitle(note.Title)
+		notesByKey[key] = append(notesByKey[key], note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Bucket([]byte(NotesTitleBucket)) != nil {
+		if err := tx.DeleteBucket([]byte(NotesTitleBucket)); err != nil {
+			return nil, fmt.Errorf("error clearing %q bucket: %w", NotesTitleBucket, err)
+		}
+	}
+	titleBucket, err := tx.CreateBucket([]byte(NotesTitleBucket))
+	if err != nil {
+		return nil, fmt.Errorf("error creating %q bucket: %w", NotesTitleBucket, err)
+	}
+
+	keys := make([]string, 0, len(notesByKey))
+	for key := range notesByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, key := range keys {
+		notes := notesByKey[key]
+		sort.Slice(notes, func(a, b int) bool {
+			return notes[a].CreatedAt.Before(notes[b].CreatedAt)
+		})
+
+		if err := titleBucket.Put([]byte(key), []byte(notes[0].ID)); err != nil {
+			return nil, fmt.Errorf("error storing title %q: %w", notes[0].Title, err)
+		}
+		for _, duplicate := range notes[1:] {
+			warnings = append(warnings, fmt.Sprintf(
+				"note %q (ID %s) has the same title as %q and can no longer be opened by title",
+				duplicate.Title, duplicate.ID, notes[0].Title))
+		}
+	}
+	return warnings, nil
+}
+
+// assignDefaultNotebook creates the default notebook and moves every existing
+// note into it, turning the flat title index into one nested bucket per notebook.
+func assignDefaultNotebook(tx *bolt.Tx) ([]string, error) {
+	if _, err := tx.CreateBucketIfNotExists([]byte(NotebooksBucket)); err != nil {
+		return nil, fmt.Errorf("error creating %q bucket: %w", NotebooksBucket, err)
+	}
+	titleBucket := tx.Bucket([]byte(NotesTitleBucket))
+	if titleBucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", NotesTitleBucket)
+	}
+
+	// Collect the flat title mappings first; a bucket can't be modified while iterating it.
+	flatTitles := make(map[string][]byte)
+	err := titleBucket.ForEach(func(k, v []byte) error {
+		if v != nil {
+			flatTitles[string(k)] = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for title := range flatTitles {
+		if err := titleBucket.Delete([]byte(title)); err != nil {
+			return nil, fmt.Errorf("error removing title mapping for %q: %w", title, err)
+		}
+	}
+
+	if _, err := GetNotebook(tx, DefaultNotebook); err != nil {
+		if _, err := CreateNotebook(tx, DefaultNotebook); err != nil {
+			return nil, err
+		}
+	}
+	defaultTitles, err := NotebookTitles(tx, DefaultNotebook)
+	if err != nil {
+		return nil, err
+	}
+	for title, noteID := range flatTitles {
+		if err := defaultTitles.Put([]byte(title), noteID); err != nil {
+			return nil, fmt.Errorf("error storing title %q: %w", title, err)
+		}
+	}
+
+	var notes []models.Note
+	err = tx.Bucket([]byte(NotesBucket)).ForEach(func(k, v []byte) error {
+		var note models.Note
+		if err := json.Unmarshal(v, &note); err != nil {
+			return fmt.Errorf("error reading note %s: %w", k, err)
+		}
+		if note.Notebook == "" {
+			note.Notebook = DefaultNotebook
+			notes = append(notes, note)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if err := storeNote(tx, note); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// indexLinks creates the LinksBucket and indexes the [[links]] of every existing note.
+func indexLinks(tx *bolt.Tx) ([]string, error) {
+	if _, err := tx.CreateBucketIfNotExists([]byte(LinksBucket)); err != nil {
+		return nil, fmt.Errorf("error creating %q bucket: %w", LinksBucket, err)
+	}
+
+	var notes []models.Note
+	err := tx.Bucket([]byte(NotesBucket)).ForEach(func(k, v []byte) error {
+		var note models.Note
+		if err := json.Unmarshal(v, &note); err != nil {
+			return fmt.Errorf("error reading note %s: %w", k, err)
+		}
+		notes = append(notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		if err := IndexLinks(tx, note); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// addNoteStates does nothing: notes stored before they could be pinned or
+// archived decode as neither. It only keeps its place in migrations, since
+// removing it would renumber the schema versions of later migrations.
+func addNoteStates(tx *bolt.Tx) ([]string, error) {
+	return nil, nil
+}
+
+// createRecurrencesBucket creates the bucket holding recurring note schedules.
+func createRecurrencesBucket(tx *bolt.Tx) ([]string, error) {
+	if _, err := tx.CreateBucketIfNotExists([]byte(RecurrencesBucket)); err != nil {
+		return nil, fmt.Errorf("error creating %q bucket: %w", RecurrencesBucket, err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// setupMigrationDB opens a database containing the given notes,
+// indexed by their raw titles as older versions of the app did.
+func setupMigrationDB(t *testing.T, notes []models.Note) *bolt.DB {
+	database, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), ReadWritePermissions, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		t.Fatalf("Couldn't create test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := database.Close(); err != nil {
+			t.Errorf("Couldn't close test database: %v", err)
+		}
+	})
+
+	err = database.Update(func(tx *bolt.Tx) error {
+		notesBucket, err := tx.CreateBucketIfNotExists([]byte(NotesBucket))
+		if err != nil {
+			return err
+		}
+		titleBucket, err := tx.CreateBucketIfNotExists([]byte(NotesTitleBucket))
+		if err != nil {
+			return err
+		}
+		for _, note := range notes {
+			noteJSON, err := json.Marshal(note)
+			if err != nil {
+				return err
+			}
+			if err := notesBucket.Put([]byte(note.ID), noteJSON); err != nil {
+				return err
+			}
+			if err := titleBucket.Put([]byte(note.Title), []byte(note.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't populate test database: %v", err)
+	}
+	return database
+}
+
+func TestMigrateReportsTitleCollisions(t *testing.T) {
+	now := time.Now()
+	database := setupMigrationDB(t, []models.Note{
+		{ID: "1", Title: "Groceries", CreatedAt: now.Add(-time.Hour)},
+		{ID: "2", Title: "groceries", CreatedAt: now},
+		{ID: "3", Title: "Work", CreatedAt: now},
+	})
+
+	warnings, err := Migrate(database)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 collision warning; got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], `"groceries"`) {
+		t.Errorf("Warning should mention the newer note; got %q", warnings[0])
+	}
+
+	err = database.View(func(tx *bolt.Tx) error {
+		titleBucket, err := NotebookTitles(tx, DefaultNotebook)
+		if err != nil {
+			return err
+		}
+		if id := titleBucket.Get(TitleKey("GROCERIES")); string(id) != "1" {
+			t.Errorf("Expected normalized title to map to the oldest note; got %q", id)
+		}
+		if id := titleBucket.Get(TitleKey("work")); string(id) != "3" {
+			t.Errorf("Expected %q to map to note 3; got %q", "work", id)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't read test database: %v", err)
+	}
+
+	// A second run is a no-op because the schema version is up to date.
+	warnings, err = Migrate(database)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings on second run; got %v", warnings)
+	}
+}
+
+func TestMigrateReadsNotesWithoutStates(t *testing.T) {
+	database := setupMigrationDB(t, []models.Note{{ID: "1", Title: "old"}})
+	// Notes stored before they could be pinned or archived lack both fields
+	err := database.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(NotesBucket)).Put([]byte("1"), []byte(`{"id":"1","title":"old","content":"text"}`))
+	})
+	if err != nil {
+		t.Fatalf("Couldn't store old note: %v", err)
+	}
+	if _, err := Migrate(database); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	err = database.View(func(tx *bolt.Tx) error {
+		note, err := GetNote(tx, "1")
+		if err != nil {
+			return err
+		}
+		if note.Pinned || note.Archived || note.Content != "text" {
+			t.Errorf("Migrated note = %+v; want it unpinned, unarchived and unchanged", note)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't read migrated note: %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/links"
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultNotebook is the notebook notes belong to unless another one is chosen.
+// It always exists and cannot be deleted.
+const DefaultNotebook = "default"
+
+// ErrNotebookNotFound is returned when a notebook does not exist.
+var ErrNotebookNotFound = errors.New("notebook not found")
+
+// NotebookKey returns the key under which a notebook is stored, both in the
+// NotebooksBucket and as the name of its nested bucket in the NotesTitleBucket.
+// Notebook names are normalized like note titles.
+func NotebookKey(name string) []byte {
+	return TitleKey(name)
+}
+
+// NotebookTitles returns the nested bucket mapping normalized note titles
+// to note IDs for the given notebook.
+func NotebookTitles(tx *bolt.Tx, notebook string) (*bolt.Bucket, error) {
+	titleBucket := tx.Bucket([]byte(NotesTitleBucket))
+	if titleBucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", NotesTitleBucket)
+	}
+	notebookBucket := titleBucket.Bucket(NotebookKey(notebook))
+	if notebookBucket == nil {
+		return nil, fmt.Errorf("notebook %q does not exist: %w", notebook, ErrNotebookNotFound)
+	}
+	return notebookBucket, nil
+}
+
+// GetNotebook retrieves the notebook with the given name.
+func GetNotebook(tx *bolt.Tx, name string) (models.Notebook, error) {
+	notebooksBucket := tx.Bucket([]byte(NotebooksBucket))
+	if notebooksBucket == nil {
+		return models.Notebook{}, fmt.Errorf("bucket %s does not exist", NotebooksBucket)
+	}
+
+	notebookJSON := notebooksBucket.Get(NotebookKey(name))
+	if notebookJSON == nil {
+		return models.Notebook{}, fmt.Errorf("notebook %q does not exist: %w", name, ErrNotebookNotFound)
+	}
+
+	var notebook models.Notebook
+	if err := json.Unmarshal(notebookJSON, &notebook); err != nil {
+		return models.Notebook{}, fmt.Errorf("error reading notebook %q: %w", name, err)
+	}
+	return notebook, nil
+}
+
+// ListNotebooks returns every notebook, sorted by name.
+func ListNotebooks(tx *bolt.Tx) ([]models.Notebook, error) {
+	notebooksBucket := tx.Bucket([]byte(NotebooksBucket))
+	if notebooksBucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", NotebooksBucket)
+	}
+
+	var notebooks []models.Notebook
+	err := notebooksBucket.ForEach(func(k, v []byte) error {
+		var notebook models.Notebook
+		if err := json.Unmarshal(v, &notebook); err != nil {
+			return fmt.Errorf("error reading notebook %q: %w", k, err)
+		}
+		notebooks = append(notebooks, notebook)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(notebooks, func(a, b int) bool {
+		return NormalizeTitle(notebooks[a].Name) < NormalizeTitle(notebooks[b].Name)
+	})
+	return notebooks, nil
+}
+
+// CreateNotebook creates an empty notebook with the given name.
+// It fails if a notebook with an equivalent name already exists.
+func CreateNotebook(tx *bolt.Tx, name string) (models.Notebook, error) {
+	if _, err := GetNotebook(tx, name); err == nil {
+		return models.Notebook{}, fmt.Errorf("notebook %q already exists", name)
+	} else if !errors.Is(err, ErrNotebookNotFound) {
+		return models.Notebook{}, err
+	}
+
+	notebook := models.Notebook{Name: name, CreatedAt: time.Now()}
+	if err := putNotebook(tx, notebook); err != nil {
+		return models.Notebook{}, err
+	}
+
+	titleBucket := tx.Bucket([]byte(NotesTitleBucket))
+	if titleBucket == nil {
+		return models.Notebook{}, fmt.Errorf("bucket %s does not exist", NotesTitleBucket)
+	}
+	if _, err := titleBucket.CreateBucket(NotebookKey(name)); err != nil {
+		return models.Notebook{}, fmt.Errorf("error creating titles for notebook %q: %w", name, err)
+	}
+	return notebook, nil
+}
+
+// RenameNotebook gives a notebook a new name, keeping its notes, and
+// rewrites [[links]] naming the old notebook to name the new one.
+// Renaming only the case of a name is allowed. It returns the notes
+// whose content was rewritten.
+func RenameNotebook(tx *bolt.Tx, oldName, newName string) ([]models.Note, error) {
+	notebook, err := GetNotebook(tx, oldName)
+	if err != nil {
+		return nil, err
+	}
+	if NormalizeTitle(oldName) == NormalizeTitle(DefaultNotebook) {
+		return nil, fmt.Errorf("the %q notebook cannot be renamed", DefaultNotebook)
+	}
+
+	sameKey := NormalizeTitle(oldName) == NormalizeTitle(newName)
+	if !sameKey {
+		if _, err := GetNotebook(tx, newName); err == nil {
+			return nil, fmt.Errorf("notebook %q already exists", newName)
+		}
+	}
+
+	notes, err := NotesInNotebook(tx, oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameKey {
+		titleBucket := tx.Bucket([]byte(NotesTitleBucket))
+		oldTitles := titleBucket.Bucket(NotebookKey(oldName))
+		newTitles, err := titleBucket.CreateBucket(NotebookKey(newName))
+		if err != nil {
+			return nil, fmt.Errorf("error creating titles for notebook %q: %w", newName, err)
+		}
+		err = oldTitles.ForEach(func(k, v []byte) error {
+			return newTitles.Put(k, v)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error moving titles to notebook %q: %w", newName, err)
+		}
+		if err := titleBucket.DeleteBucket(NotebookKey(oldName)); err != nil {
+			return nil, fmt.Errorf("error removing titles of notebook %q: %w", oldName, err)
+		}
+		if err := tx.Bucket([]byte(NotebooksBucket)).Delete(NotebookKey(oldName)); err != nil {
+			return nil, fmt.Errorf("error removing notebook %q: %w", oldName, err)
+		}
+	}
+
+	notebook.Name = newName
+	if err := putNotebook(tx, notebook); err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		note.Notebook = newName
+		if err := PutNote(tx, note); err != nil {
+			return nil, err
+		}
+	}
+
+	allNotes, err := AllNotes(tx)
+	if err != nil {
+		return nil, err
+	}
+	noteIDs := make([]string, 0, len(allNotes))
+	for _, note := range allNotes {
+		noteIDs = append(noteIDs, note.ID)
+	}
+	return rewriteLinks(tx, noteIDs, func(source models.Note, link links.Link) (links.Link, bool) {
+		if link.Notebook == "" || NormalizeTitle(link.Notebook) != NormalizeTitle(oldName) {
+			return link, false
+		}
+		link.Notebook = qualifier(source, newName)
+		return link, true
+	})
+}
+
+// DeleteNotebook removes a notebook together with all of its notes,
+// and returns the notes that were deleted.
+func DeleteNotebook(tx *bolt.Tx, name string) ([]models.Note, error) {
+	if _, err := GetNotebook(tx, name); err != nil {
+		return nil, err
+	}
+	if NormalizeTitle(name) == NormalizeTitle(DefaultNotebook) {
+		return nil, fmt.Errorf("the %q notebook cannot be deleted", DefaultNotebook)
+	}
+
+	notes, err := NotesInNotebook(tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	for _, note := range notes {
+		if err := notesBucket.Delete([]byte(note.ID)); err != nil {
+			return nil, fmt.Errorf("error deleting note %q: %w", note.Title, err)
+		}
+		if err := UnlinkNote(tx, note); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Bucket([]byte(NotesTitleBucket)).DeleteBucket(NotebookKey(name)); err != nil {
+		return nil, fmt.Errorf("error removing titles of notebook %q: %w", name, err)
+	}
+	if err := tx.Bucket([]byte(NotebooksBucket)).Delete(NotebookKey(name)); err != nil {
+		return nil, fmt.Errorf("error removing notebook %q: %w", name, err)
+	}
+	return notes, nil
+}
+
+// NotesInNotebook returns every note that belongs to the given notebook.
+// Notes are found by their Notebook field rather than the title index, so
+// that notes left without a title mapping by a title collision are included.
+func NotesInNotebook(tx *bolt.Tx, name string) ([]models.Note, error) {
+	if _, err := NotebookTitles(tx, name); err != nil {
+		return nil, err
+	}
+
+	all, err := AllNotes(tx)
+	if err != nil {
+		return nil, err
+	}
+	var notes []models.Note
+	for _, note := range all {
+		if NormalizeTitle(note.Notebook) == NormalizeTitle(name) {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// MoveNote moves a note into another notebook and returns the updated note
+// and the other notes whose content was rewritten. It fails if the target
+// notebook already has a note with the same title.
+//
+// [[Links]] to the note are rewritten to name its new notebook, and links
+// in the note without a notebook are qualified with its old one, so every
+// link keeps pointing at the same note.
+func MoveNote(tx *bolt.Tx, note models.Note, target string) (models.Note, []models.Note, error) {
+	targetNotebook, err := GetNotebook(tx, target)
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+	targetTitles, err := NotebookTitles(tx, target)
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+	if existing := targetTitles.Get(TitleKey(note.Title)); existing != nil {
+		if string(existing) == note.ID {
+			return note, nil, nil
+		}
+		return models.Note{}, nil, fmt.Errorf("notebook %q already has a note titled %q", targetNotebook.Name, note.Title)
+	}
+
+	sourceTitles, err := NotebookTitles(tx, note.Notebook)
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+	if string(sourceTitles.Get(TitleKey(note.Title))) == note.ID {
+		if err := sourceTitles.Delete(TitleKey(note.Title)); err != nil {
+			return models.Note{}, nil, fmt.Errorf("error removing title mapping for %q: %w", note.Title, err)
+		}
+	}
+	if err := targetTitles.Put(TitleKey(note.Title), []byte(note.ID)); err != nil {
+		return models.Note{}, nil, fmt.Errorf("error storing title %q: %w", note.Title, err)
+	}
+
+	oldNotebook := note.Notebook
+	content := links.RewriteFunc(note.Content, func(link links.Link) (links.Link, bool) {
+		switch {
+		case linksTo(note, link, oldNotebook, note.Title):
+			// A link to the note itself follows it
+			if link.Notebook == "" {
+				return link, false
+			}
+			link.Notebook = ""
+		case link.Notebook == "":
+			link.Notebook = oldNotebook
+		default:
+			return link, false
+		}
+		return link, true
+	})
+	if content != note.Content {
+		note.Content = content
+		note.ModifiedAt = time.Now()
+	}
+	note.Notebook = targetNotebook.Name
+	if err := PutNote(tx, note); err != nil {
+		return models.Note{}, nil, err
+	}
+
+	sourceIDs, err := Backlinks(tx, note.ID)
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+	sourceIDs = slices.DeleteFunc(sourceIDs, func(id string) bool { return id == note.ID })
+	rewritten, err := rewriteLinks(tx, sourceIDs, func(source models.Note, link links.Link) (links.Link, bool) {
+		if !linksTo(source, link, oldNotebook, note.Title) {
+			return link, false
+		}
+		link.Notebook = qualifier(source, note.Notebook)
+		return link, true
+	})
+	if err != nil {
+		return models.Note{}, nil, err
+	}
+
+	if err := ResolveDanglingLinks(tx, note); err != nil {
+		return models.Note{}, nil, err
+	}
+	return note, rewritten, nil
+}
+
+// qualifier returns the notebook a link in source names to reach a note in
+// notebook: none if it is source's own notebook.
+func qualifier(source models.Note, notebook string) string {
+	if NormalizeTitle(source.Notebook) == NormalizeTitle(notebook) {
+		return ""
+	}
+	return notebook
+}
+
+// putNotebook stores a notebook's metadata in the NotebooksBucket.
+func putNotebook(tx *bolt.Tx, notebook models.Notebook) error {
+	notebooksBucket := tx.Bucket([]byte(NotebooksBucket))
+	if notebooksBucket == nil {
+		return fmt.Errorf("bucket %s does not exist", NotebooksBucket)
+	}
+	notebookJSON, err := json.Marshal(notebook)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notebook as JSON: %w", err)
+	}
+	if err := notebooksBucket.Put(NotebookKey(notebook.Name), notebookJSON); err != nil {
+		return fmt.Errorf("failed to store notebook %q: %w", notebook.Name, err)
+	}
+	return nil
+}
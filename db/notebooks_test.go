@@ -0,0 +1,281 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// setupNotebookDB returns a migrated database containing the given notes
+// in the default notebook.
+func setupNotebookDB(t *testing.T, notes []models.Note) *bolt.DB {
+	database := setupMigrationDB(t, notes)
+	if _, err := Migrate(database); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	return database
+}
+
+func TestMigrateAssignsDefaultNotebook(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{{ID: "1", Title: "groceries"}})
+
+	err := database.View(func(tx *bolt.Tx) error {
+		note, err := LookupNote(tx, DefaultNotebook, "groceries")
+		if err != nil {
+			return err
+		}
+		if note.Notebook != DefaultNotebook {
+			t.Errorf("Note notebook = %q; want %q", note.Notebook, DefaultNotebook)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't look up migrated note: %v", err)
+	}
+}
+
+func TestTitlesAreUniquePerNotebook(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{
+		{ID: "1", Title: "standup"},
+		{ID: "2", Title: "other"},
+	})
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		if _, err := CreateNotebook(tx, "Work"); err != nil {
+			return err
+		}
+		if _, err := CreateNotebook(tx, "work"); err == nil {
+			t.Error("Expected error creating a notebook with an equivalent name")
+		}
+
+		note, err := GetNote(tx, "1")
+		if err != nil {
+			return err
+		}
+		moved, _, err := MoveNote(tx, note, "work")
+		if err != nil {
+			return err
+		}
+		if moved.Notebook != "Work" {
+			t.Errorf("Moved note notebook = %q; want %q", moved.Notebook, "Work")
+		}
+
+		if _, err := LookupNote(tx, DefaultNotebook, "standup"); !errors.Is(err, ErrNoteNotFound) {
+			t.Errorf("Expected note to be gone from the default notebook; got %v", err)
+		}
+		if _, err := LookupNote(tx, "Work", "standup"); err != nil {
+			t.Errorf("Expected note in the Work notebook; got %v", err)
+		}
+
+		// Another note with the same title can't be moved into the notebook
+		other, err := GetNote(tx, "2")
+		if err != nil {
+			return err
+		}
+		other.Title = "standup"
+		if _, _, err := MoveNote(tx, other, "work"); err == nil {
+			t.Error("Expected error moving a note into a notebook with the same title")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Notebook operations failed: %v", err)
+	}
+}
+
+func TestRenameAndDeleteNotebook(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{{ID: "1", Title: "standup"}})
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		if _, err := CreateNotebook(tx, "work"); err != nil {
+			return err
+		}
+		note, err := GetNote(tx, "1")
+		if err != nil {
+			return err
+		}
+		if _, _, err := MoveNote(tx, note, "work"); err != nil {
+			return err
+		}
+
+		if _, err := RenameNotebook(tx, "work", "job"); err != nil {
+			return err
+		}
+		renamed, err := LookupNote(tx, "job", "standup")
+		if err != nil {
+			t.Fatalf("Expected note in renamed notebook; got %v", err)
+		}
+		if renamed.Notebook != "job" {
+			t.Errorf("Note notebook = %q; want %q", renamed.Notebook, "job")
+		}
+		if _, err := GetNotebook(tx, "work"); !errors.Is(err, ErrNotebookNotFound) {
+			t.Errorf("Expected old notebook to be gone; got %v", err)
+		}
+
+		if _, err := RenameNotebook(tx, DefaultNotebook, "main"); err == nil {
+			t.Error("Expected error renaming the default notebook")
+		}
+
+		deleted, err := DeleteNotebook(tx, "job")
+		if err != nil {
+			return err
+		}
+		if len(deleted) != 1 {
+			t.Errorf("Expected 1 deleted note; got %d", len(deleted))
+		}
+		if _, err := GetNote(tx, "1"); !errors.Is(err, ErrNoteNotFound) {
+			t.Errorf("Expected note to be deleted with its notebook; got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Notebook operations failed: %v", err)
+	}
+}
+
+func TestRenameAndDeleteNotebookFindNotesWithoutTitles(t *testing.T) {
+	database := setupNotebookDB(t, nil)
+
+	// "Café" and "cafe\u0301" collide, so only the first has a title mapping
+	notes := []models.Note{
+		{ID: "1", Title: "Café", Notebook: "work"},
+		{ID: "2", Title: "cafe\u0301", Notebook: "work"},
+	}
+	err := database.Update(func(tx *bolt.Tx) error {
+		if _, err := CreateNotebook(tx, "work"); err != nil {
+			return err
+		}
+		for _, note := range notes {
+			if err := PutNote(tx, note); err != nil {
+				return err
+			}
+		}
+		if err := PutNoteTitle(tx, notes[0]); err != nil {
+			return err
+		}
+
+		if _, err := RenameNotebook(tx, "work", "job"); err != nil {
+			return err
+		}
+		orphan, err := GetNote(tx, "2")
+		if err != nil {
+			return err
+		}
+		if orphan.Notebook != "job" {
+			t.Errorf("Notebook of the note without a title = %q; want %q", orphan.Notebook, "job")
+		}
+
+		deleted, err := DeleteNotebook(tx, "job")
+		if err != nil {
+			return err
+		}
+		if len(deleted) != 2 {
+			t.Errorf("DeleteNotebook() deleted %d notes; want 2", len(deleted))
+		}
+		if _, err := GetNote(tx, "2"); !errors.Is(err, ErrNoteNotFound) {
+			t.Errorf("GetNote() of the note without a title error = %v; want ErrNoteNotFound", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't update test database: %v", err)
+	}
+}
+
+func TestMoveNoteRewritesLinks(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{
+		{ID: "1", Title: "standup", Content: "Ask about [[Budget]], see [[standup|this note]]."},
+		{ID: "2", Title: "budget"},
+		{ID: "3", Title: "plan", Content: "After [[Standup]] and [[default/standup|the meeting]]."},
+	})
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		work, err := CreateNotebook(tx, "work")
+		if err != nil {
+			return err
+		}
+		note, err := GetNote(tx, "1")
+		if err != nil {
+			return err
+		}
+		moved, rewritten, err := MoveNote(tx, note, work.Name)
+		if err != nil {
+			return err
+		}
+
+		// The moved note's links keep pointing at the same notes
+		if want := "Ask about [[default/Budget]], see [[standup|this note]]."; moved.Content != want {
+			t.Errorf("Moved note content = %q; want %q", moved.Content, want)
+		}
+		if len(rewritten) != 1 || rewritten[0].ID != "3" {
+			t.Fatalf("Rewritten notes = %v; want note 3", rewritten)
+		}
+		if want := "After [[work/Standup]] and [[work/standup|the meeting]]."; rewritten[0].Content != want {
+			t.Errorf("Linking note content = %q; want %q", rewritten[0].Content, want)
+		}
+
+		for id, want := range map[string]int{"1": 2, "3": 1} {
+			noteLinks, err := OutboundLinks(tx, id)
+			if err != nil {
+				return err
+			}
+			for _, link := range noteLinks {
+				if link.Dangling() {
+					t.Errorf("Link %q from note %s is dangling", link.Target, id)
+				}
+			}
+			if len(noteLinks) != want {
+				t.Errorf("Note %s has %d links; want %d", id, len(noteLinks), want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Moving note failed: %v", err)
+	}
+}
+
+func TestRenameNotebookRewritesLinks(t *testing.T) {
+	database := setupNotebookDB(t, []models.Note{
+		{ID: "1", Title: "standup"},
+		{ID: "2", Title: "plan", Content: "See [[work/Standup]] and [[Work/Retro]]."},
+	})
+
+	err := database.Update(func(tx *bolt.Tx) error {
+		if _, err := CreateNotebook(tx, "work"); err != nil {
+			return err
+		}
+		note, err := GetNote(tx, "1")
+		if err != nil {
+			return err
+		}
+		if _, _, err := MoveNote(tx, note, "work"); err != nil {
+			return err
+		}
+
+		rewritten, err := RenameNotebook(tx, "work", "job")
+		if err != nil {
+			return err
+		}
+		if len(rewritten) != 1 {
+			t.Fatalf("Rewritten notes = %v; want note 2", rewritten)
+		}
+		if want := "See [[job/Standup]] and [[job/Retro]]."; rewritten[0].Content != want {
+			t.Errorf("Linking note content = %q; want %q", rewritten[0].Content, want)
+		}
+
+		sources, err := Backlinks(tx, "1")
+		if err != nil {
+			return err
+		}
+		if len(sources) != 1 || sources[0] != "2" {
+			t.Errorf("Backlinks = %v; want [2]", sources)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Renaming notebook failed: %v", err)
+	}
+}
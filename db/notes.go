@@ -0,0 +1,251 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// MinIDPrefixLength is the shortest ID prefix accepted as a note handle.
+// Shorter prefixes would too easily be confused with note titles.
+const MinIDPrefixLength = 4
+
+// ErrNoteNotFound is returned when a handle matches neither a title nor an ID prefix.
+var ErrNoteNotFound = errors.New("note not found")
+
+// AmbiguousPrefixError is returned when an ID prefix matches more than one note.
+type AmbiguousPrefixError struct {
+	Prefix  string
+	Matches []models.NoteTitle
+}
+
+func (e *AmbiguousPrefixError) Error() string {
+	candidates := make([]string, 0, len(e.Matches))
+	for _, match := range e.Matches {
+		candidates = append(candidates, fmt.Sprintf("  %s  %s", match.ID, match.Title))
+	}
+	return fmt.Sprintf("ID prefix %q is ambiguous; it matches:\n%s\nPlease use a longer prefix",
+		e.Prefix, strings.Join(candidates, "\n"))
+}
+
+// ResolveNoteID finds the ID of the note identified by handle.
+// The handle is first looked up as a title within the given notebook; if no
+// note there has that title, it is treated as a git-style ID prefix, which
+// must match exactly one note in any notebook.
+func ResolveNoteID(tx *bolt.Tx, notebook, handle string) (string, error) {
+	titleBucket, err := NotebookTitles(tx, notebook)
+	if err != nil {
+		return "", err
+	}
+	if noteID := titleBucket.Get(TitleKey(handle)); noteID != nil {
+		return string(noteID), nil
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(handle))
+	if len(prefix) < MinIDPrefixLength {
+		return "", fmt.Errorf("note %q does not exist: %w", handle, ErrNoteNotFound)
+	}
+
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	if notesBucket == nil {
+		return "", fmt.Errorf("bucket %s does not exist", NotesBucket)
+	}
+
+	var matches []models.NoteTitle
+	cursor := notesBucket.Cursor()
+	for k, v := cursor.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = cursor.Next() {
+		var note models.Note
+		if err := json.Unmarshal(v, &note); err != nil {
+			return "", fmt.Errorf("error reading note %s: %w", k, err)
+		}
+		matches = append(matches, models.NoteTitle{Title: note.Title, ID: note.ID})
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("note %q does not exist: %w", handle, ErrNoteNotFound)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", &AmbiguousPrefixError{Prefix: prefix, Matches: matches}
+	}
+}
+
+// GetNote retrieves the note stored under the given ID.
+func GetNote(tx *bolt.Tx, noteID string) (models.Note, error) {
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	if notesBucket == nil {
+		return models.Note{}, fmt.Errorf("bucket %s does not exist", NotesBucket)
+	}
+
+	noteJSON := notesBucket.Get([]byte(noteID))
+	if noteJSON == nil {
+		return models.Note{}, fmt.Errorf("noteID %s does not exist: %w", noteID, ErrNoteNotFound)
+	}
+
+	var note models.Note
+	if err := json.Unmarshal(noteJSON, &note); err != nil {
+		return models.Note{}, fmt.Errorf("error reading note %s: %w", noteID, err)
+	}
+	return note, nil
+}
+
+// PutNote stores a note under its ID, replacing any previous version,
+// and re-indexes its [[links]]. It does not touch the note's title mapping.
+func PutNote(tx *bolt.Tx, note models.Note) error {
+	if err := storeNote(tx, note); err != nil {
+		return err
+	}
+	return IndexLinks(tx, note)
+}
+
+// PutNoteTitle maps the note's title to its ID in its notebook's titles
+// bucket, so the note can be looked up by title, and resolves dangling
+// [[links]] to the title to the note.
+func PutNoteTitle(tx *bolt.Tx, note models.Note) error {
+	titles, err := NotebookTitles(tx, note.Notebook)
+	if err != nil {
+		return err
+	}
+	if err := titles.Put(TitleKey(note.Title), []byte(note.ID)); err != nil {
+		return fmt.Errorf("error storing title %q: %w", note.Title, err)
+	}
+	return ResolveDanglingLinks(tx, note)
+}
+
+// RemoveNote deletes the content, the title mapping and the links of a note.
+// Links from other notes to it are kept as dangling links.
+func RemoveNote(tx *bolt.Tx, note models.Note) error {
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	if notesBucket == nil {
+		return fmt.Errorf("bucket %s does not exist", NotesBucket)
+	}
+	if err := notesBucket.Delete([]byte(note.ID)); err != nil {
+		return fmt.Errorf("error deleting note %q: %w", note.Title, err)
+	}
+	if err := removeNoteTitle(tx, note); err != nil {
+		return err
+	}
+	if err := UnlinkNote(tx, note); err != nil {
+		return fmt.Errorf("error deleting links of note %q: %w", note.Title, err)
+	}
+	return nil
+}
+
+// removeNoteTitle removes the title-to-ID mapping from the note's notebook.
+// The mapping is only removed if it points at this note; notes that were
+// left without a title mapping by a title collision have nothing to remove.
+func removeNoteTitle(tx *bolt.Tx, note models.Note) error {
+	titles, err := NotebookTitles(tx, note.Notebook)
+	if err != nil {
+		return err
+	}
+	if string(titles.Get(TitleKey(note.Title))) != note.ID {
+		return nil
+	}
+	if err := titles.Delete(TitleKey(note.Title)); err != nil {
+		return fmt.Errorf("error removing title mapping for %q: %w", note.Title, err)
+	}
+	return nil
+}
+
+// storeNote marshals a note and stores it under its ID.
+func storeNote(tx *bolt.Tx, note models.Note) error {
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	if notesBucket == nil {
+		return fmt.Errorf("bucket %s does not exist", NotesBucket)
+	}
+	noteJSON, err := json.Marshal(note)
+	if err != nil {
+		return fmt.Errorf("failed to marshal note as JSON: %w", err)
+	}
+	if err := notesBucket.Put([]byte(note.ID), noteJSON); err != nil {
+		return fmt.Errorf("failed to store note %q: %w", note.Title, err)
+	}
+	return nil
+}
+
+// AllNotes returns every note in every notebook, in ID order.
+func AllNotes(tx *bolt.Tx) ([]models.Note, error) {
+	notesBucket := tx.Bucket([]byte(NotesBucket))
+	if notesBucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", NotesBucket)
+	}
+
+	var notes []models.Note
+	err := notesBucket.ForEach(func(k, v []byte) error {
+		var note models.Note
+		if err := json.Unmarshal(v, &note); err != nil {
+			return fmt.Errorf("error reading note %s: %w", k, err)
+		}
+		notes = append(notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// LookupNote retrieves the note identified by a title in the given notebook
+// or by a unique ID prefix.
+func LookupNote(tx *bolt.Tx, notebook, handle string) (models.Note, error) {
+	noteID, err := ResolveNoteID(tx, notebook, handle)
+	if err != nil {
+		return models.Note{}, err
+	}
+	return GetNote(tx, noteID)
+}
+
+// MatchNotes returns the notes in notebook, or in every notebook if it is empty,
+// whose title or content contains query. Text is compared in its normalized form.
+func MatchNotes(notes []models.Note, query, notebook string) []models.Note {
+	needle := NormalizeTitle(query)
+	var matches []models.Note
+	for _, note := range notes {
+		if notebook != "" && NormalizeTitle(note.Notebook) != NormalizeTitle(notebook) {
+			continue
+		}
+		if strings.Contains(NormalizeTitle(note.Title), needle) ||
+			strings.Contains(NormalizeTitle(note.Content), needle) {
+			matches = append(matches, note)
+		}
+	}
+	return matches
+}
+
+// ShortestUniquePrefixes returns, for every ID, the shortest prefix that
+// identifies it unambiguously among the given IDs. Prefixes are never
+// shorter than MinIDPrefixLength, so they can always be used as handles.
+func ShortestUniquePrefixes(ids []string) map[string]string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+
+	prefixes := make(map[string]string, len(sorted))
+	for i, id := range sorted {
+		length := MinIDPrefixLength
+		if i > 0 {
+			length = max(length, commonPrefixLength(id, sorted[i-1])+1)
+		}
+		if i < len(sorted)-1 {
+			length = max(length, commonPrefixLength(id, sorted[i+1])+1)
+		}
+		prefixes[id] = id[:min(length, len(id))]
+	}
+	return prefixes
+}
+
+// commonPrefixLength returns the number of leading bytes a and b share.
+func commonPrefixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
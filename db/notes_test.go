@@ -0,0 +1,146 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestResolveNoteID(t *testing.T) {
+	database := setupMigrationDB(t, []models.Note{
+		{ID: "3f2a91c0-aaaa", Title: "groceries"},
+		{ID: "3f2b17d4-bbbb", Title: "work"},
+		{ID: "9c01ee37-cccc", Title: "3f2a"},
+	})
+	if _, err := Migrate(database); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		handle  string
+		wantID  string
+		wantErr error
+	}{
+		{name: "Title", handle: "Groceries", wantID: "3f2a91c0-aaaa"},
+		{name: "Title Takes Precedence Over Prefix", handle: "3f2a", wantID: "9c01ee37-cccc"},
+		{name: "Unique Prefix", handle: "3f2b", wantID: "3f2b17d4-bbbb"},
+		{name: "Uppercase Prefix", handle: "9C01", wantID: "9c01ee37-cccc"},
+		{name: "Longer Prefix", handle: "3f2a9", wantID: "3f2a91c0-aaaa"},
+		{name: "Prefix Below Minimum Length", handle: "3f2", wantErr: ErrNoteNotFound},
+		{name: "Unknown", handle: "ffff", wantErr: ErrNoteNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := database.View(func(tx *bolt.Tx) error {
+				id, err := ResolveNoteID(tx, DefaultNotebook, tt.handle)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("ResolveNoteID(%q) error = %v; want %v", tt.handle, err, tt.wantErr)
+					}
+					return nil
+				}
+				if err != nil {
+					t.Errorf("ResolveNoteID(%q) error = %v", tt.handle, err)
+				}
+				if id != tt.wantID {
+					t.Errorf("ResolveNoteID(%q) = %q; want %q", tt.handle, id, tt.wantID)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Couldn't read test database: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveNoteIDAmbiguousPrefix(t *testing.T) {
+	database := setupMigrationDB(t, []models.Note{
+		{ID: "3f2a91c0-aaaa", Title: "groceries"},
+		{ID: "3f2a17d4-bbbb", Title: "work"},
+	})
+	if _, err := Migrate(database); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	err := database.View(func(tx *bolt.Tx) error {
+		_, err := ResolveNoteID(tx, DefaultNotebook, "3f2a")
+		var ambiguous *AmbiguousPrefixError
+		if !errors.As(err, &ambiguous) {
+			t.Fatalf("Expected AmbiguousPrefixError; got %v", err)
+		}
+		if len(ambiguous.Matches) != 2 {
+			t.Errorf("Expected 2 matches; got %v", ambiguous.Matches)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't read test database: %v", err)
+	}
+}
+
+func TestShortestUniquePrefixes(t *testing.T) {
+	prefixes := ShortestUniquePrefixes([]string{
+		"3f2a91c0",
+		"3f2a17d4",
+		"9c01ee37",
+		"3f2b0000",
+	})
+
+	expected := map[string]string{
+		"3f2a91c0": "3f2a9",
+		"3f2a17d4": "3f2a1",
+		"9c01ee37": "9c01",
+		"3f2b0000": "3f2b",
+	}
+	for id, want := range expected {
+		if prefixes[id] != want {
+			t.Errorf("Prefix for %q = %q; want %q", id, prefixes[id], want)
+		}
+	}
+}
+
+func TestRemoveNoteKeepsLinksToItDangling(t *testing.T) {
+	database := setupMigrationDB(t, nil)
+	if _, err := Migrate(database); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	target := models.Note{ID: "3f2a91c0-aaaa", Title: "Groceries", Notebook: DefaultNotebook}
+	source := models.Note{ID: "9c01ee37-cccc", Title: "Plans", Notebook: DefaultNotebook, Content: "see [[Groceries]]"}
+	err := database.Update(func(tx *bolt.Tx) error {
+		for _, note := range []models.Note{target, source} {
+			if err := PutNote(tx, note); err != nil {
+				return err
+			}
+			if err := PutNoteTitle(tx, note); err != nil {
+				return err
+			}
+		}
+		return RemoveNote(tx, target)
+	})
+	if err != nil {
+		t.Fatalf("Couldn't update test database: %v", err)
+	}
+
+	err = database.View(func(tx *bolt.Tx) error {
+		if _, err := LookupNote(tx, DefaultNotebook, "Groceries"); !errors.Is(err, ErrNoteNotFound) {
+			t.Errorf("LookupNote() of the removed note error = %v; want ErrNoteNotFound", err)
+		}
+		dangling, err := DanglingLinks(tx)
+		if err != nil {
+			return err
+		}
+		if got := dangling[source.ID]; len(got) != 1 || got[0] != "Groceries" {
+			t.Errorf("dangling links of %q = %v; want [Groceries]", source.Title, got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Couldn't read test database: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrRecurrenceNotFound is returned when a recurrence does not exist.
+var ErrRecurrenceNotFound = errors.New("recurrence not found")
+
+// GetRecurrence retrieves the recurrence with the given name.
+// Recurrence names are matched like note titles.
+func GetRecurrence(tx *bolt.Tx, name string) (models.Recurrence, error) {
+	recurrencesBucket, err := recurrencesBucket(tx)
+	if err != nil {
+		return models.Recurrence{}, err
+	}
+
+	recurrenceJSON := recurrencesBucket.Get(TitleKey(name))
+	if recurrenceJSON == nil {
+		return models.Recurrence{}, fmt.Errorf("recurrence %q does not exist: %w", name, ErrRecurrenceNotFound)
+	}
+
+	var recurrence models.Recurrence
+	if err := json.Unmarshal(recurrenceJSON, &recurrence); err != nil {
+		return models.Recurrence{}, fmt.Errorf("error reading recurrence %q: %w", name, err)
+	}
+	return recurrence, nil
+}
+
+// PutRecurrence stores a recurrence, replacing any recurrence with an equivalent name.
+func PutRecurrence(tx *bolt.Tx, recurrence models.Recurrence) error {
+	recurrencesBucket, err := recurrencesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	recurrenceJSON, err := json.Marshal(recurrence)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recurrence as JSON: %w", err)
+	}
+	if err := recurrencesBucket.Put(TitleKey(recurrence.Name), recurrenceJSON); err != nil {
+		return fmt.Errorf("failed to store recurrence %q: %w", recurrence.Name, err)
+	}
+	return nil
+}
+
+// DeleteRecurrence removes the recurrence with the given name.
+// Notes it already created are kept.
+func DeleteRecurrence(tx *bolt.Tx, name string) error {
+	if _, err := GetRecurrence(tx, name); err != nil {
+		return err
+	}
+	recurrencesBucket, err := recurrencesBucket(tx)
+	if err != nil {
+		return err
+	}
+	if err := recurrencesBucket.Delete(TitleKey(name)); err != nil {
+		return fmt.Errorf("error deleting recurrence %q: %w", name, err)
+	}
+	return nil
+}
+
+// ListRecurrences returns every recurrence, sorted by name.
+func ListRecurrences(tx *bolt.Tx) ([]models.Recurrence, error) {
+	recurrencesBucket, err := recurrencesBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var recurrences []models.Recurrence
+	err = recurrencesBucket.ForEach(func(k, v []byte) error {
+		var recurrence models.Recurrence
+		if err := json.Unmarshal(v, &recurrence); err != nil {
+			return fmt.Errorf("error reading recurrence %q: %w", k, err)
+		}
+		recurrences = append(recurrences, recurrence)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(recurrences, func(a, b int) bool {
+		return NormalizeTitle(recurrences[a].Name) < NormalizeTitle(recurrences[b].Name)
+	})
+	return recurrences, nil
+}
+
+// recurrencesBucket returns the RecurrencesBucket, or an error if it doesn't exist.
+func recurrencesBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	bucket := tx.Bucket([]byte(RecurrencesBucket))
+	if bucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", RecurrencesBucket)
+	}
+	return bucket, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrTemplateNotFound is returned when a template does not exist.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// GetTemplate retrieves the template with the given name.
+// Template names are matched like note titles.
+func GetTemplate(tx *bolt.Tx, name string) (models.Template, error) {
+	templatesBucket, err := templatesBucket(tx)
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	templateJSON := templatesBucket.Get(TitleKey(name))
+	if templateJSON == nil {
+		return models.Template{}, fmt.Errorf("template %q does not exist: %w", name, ErrTemplateNotFound)
+	}
+
+	var template models.Template
+	if err := json.Unmarshal(templateJSON, &template); err != nil {
+		return models.Template{}, fmt.Errorf("error reading template %q: %w", name, err)
+	}
+	return template, nil
+}
+
+// PutTemplate stores a template, replacing any template with an equivalent name.
+func PutTemplate(tx *bolt.Tx, template models.Template) error {
+	templatesBucket, err := templatesBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template as JSON: %w", err)
+	}
+	if err := templatesBucket.Put(TitleKey(template.Name), templateJSON); err != nil {
+		return fmt.Errorf("failed to store template %q: %w", template.Name, err)
+	}
+	return nil
+}
+
+// DeleteTemplate removes the template with the given name.
+func DeleteTemplate(tx *bolt.Tx, name string) error {
+	if _, err := GetTemplate(tx, name); err != nil {
+		return err
+	}
+	templatesBucket, err := templatesBucket(tx)
+	if err != nil {
+		return err
+	}
+	if err := templatesBucket.Delete(TitleKey(name)); err != nil {
+		return fmt.Errorf("error deleting template %q: %w", name, err)
+	}
+	return nil
+}
+
+// ListTemplates returns every template, sorted by name.
+func ListTemplates(tx *bolt.Tx) ([]models.Template, error) {
+	templatesBucket, err := templatesBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []models.Template
+	err = templatesBucket.ForEach(func(k, v []byte) error {
+		var template models.Template
+		if err := json.Unmarshal(v, &template); err != nil {
+			return fmt.Errorf("error reading template %q: %w", k, err)
+		}
+		templates = append(templates, template)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(templates, func(a, b int) bool {
+		return NormalizeTitle(templates[a].Name) < NormalizeTitle(templates[b].Name)
+	})
+	return templates, nil
+}
+
+// templatesBucket returns the TemplatesBucket, or an error if it doesn't exist.
+func templatesBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	bucket := tx.Bucket([]byte(TemplatesBucket))
+	if bucket == nil {
+		return nil, fmt.Errorf("bucket %s does not exist", TemplatesBucket)
+	}
+	return bucket, nil
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeTitle returns the canonical lookup form of a note title.
+// The title is trimmed, converted to Unicode NFC and case-folded, so that
+// "Groceries", "groceries" and differently normalized forms of "Café"
+// all map to the same key. The original casing is kept on the note itself
+// for display purposes.
+func NormalizeTitle(title string) string {
+	normalized := norm.NFC.String(strings.TrimSpace(title))
+
+	// A Caser holds state, so a new one is created for every call.
+	return norm.NFC.String(cases.Fold().String(normalized))
+}
+
+// TitleKey returns the key under which a note title is stored in the
+// NotesTitleBucket.
+func TitleKey(title string) []byte {
+	return []byte(NormalizeTitle(title))
+}
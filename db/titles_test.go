@@ -0,0 +1,41 @@
+package db
+
+import "testing"
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{
+			name: "Different Case",
+			a:    "Groceries",
+			b:    "groceries",
+		},
+		{
+			name: "Surrounding Whitespace",
+			a:    "  Groceries ",
+			b:    "groceries",
+		},
+		{
+			name: "NFC And NFD Forms",
+			a:    "Caf\u00e9",
+			b:    "Cafe\u0301",
+		},
+		{
+			name: "Case Folding Beyond ASCII",
+			a:    "STRASSE",
+			b:    "straße",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if NormalizeTitle(tt.a) != NormalizeTitle(tt.b) {
+				t.Errorf("NormalizeTitle(%q) = %q; NormalizeTitle(%q) = %q; expected equal",
+					tt.a, NormalizeTitle(tt.a), tt.b, NormalizeTitle(tt.b))
+			}
+		})
+	}
+}
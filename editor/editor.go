@@ -0,0 +1,86 @@
+// Package editor opens text in the user's preferred text editor.
+package editor
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Edit writes content to a temporary file, opens it in the user's editor,
+// and returns the file's content once the editor exits.
+func Edit(content string) (string, error) {
+	// Create temporary file to write data
+	tempFile, err := os.CreateTemp("", "temp-file-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file: %w", err)
+	}
+
+	defer func() {
+		if err := tempFile.Close(); err != nil {
+			log.Printf("error closing temp file: %v", err)
+		}
+		if err = os.Remove(tempFile.Name()); err != nil {
+			log.Printf("error removing temp file: %v", err)
+		}
+	}()
+
+	// Copy content to temp file
+	if _, err := tempFile.WriteString(content); err != nil {
+		return "", fmt.Errorf("error writing to temp file: %w", err)
+	}
+
+	// The editor may include arguments, e.g. EDITOR="code --wait"
+	editorCommand := strings.Fields(determineEditor())
+	command := exec.Command(editorCommand[0], append(editorCommand[1:], tempFile.Name())...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("error running editor: %w", err)
+	}
+
+	// Read back the edited file
+	editedContent, err := os.ReadFile(tempFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("error reading edited file: %w", err)
+	}
+	return string(editedContent), nil
+}
+
+func determineEditor() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		return "notepad"
+	case "darwin": // macOS
+		for _, editor := range []string{"nano", "vim", "vi"} {
+			if _, err := exec.LookPath(editor); err == nil {
+				return editor
+			}
+		}
+
+		// Fall back to TextEdit if available
+		if _, err := exec.LookPath("TextEdit"); err == nil {
+			return "open -a TextEdit"
+		}
+
+		// Last resort
+		return "nano"
+	default: // Linux and others
+		// Try common editors
+		for _, editor := range []string{"nano", "vim", "vi", "emacs"} {
+			if _, err := exec.LookPath(editor); err == nil {
+				return editor
+			}
+		}
+		return "nano" // Default
+	}
+}
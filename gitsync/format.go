@@ -0,0 +1,113 @@
+package gitsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// frontMatterDelimiter starts and ends the front matter of a note file.
+const frontMatterDelimiter = "---\n"
+
+// frontMatter holds the fields of a note stored above its content.
+// Values are written as JSON, which is also valid YAML.
+type frontMatter struct {
+	Title      string     `json:"title"`
+	Notebook   string     `json:"notebook"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ModifiedAt time.Time  `json:"modified_at"`
+	Tags       []string   `json:"tags"`
+	Pinned     bool       `json:"pinned"`
+	Archived   bool       `json:"archived"`
+	Due        *time.Time `json:"due"`
+}
+
+// render returns the file a note is stored as: its fields as front matter,
+// one per line so that diffs show which changed, then its content. Times
+// are written in UTC so that every clone writes the same file.
+func render(note models.Note) []byte {
+	var b bytes.Buffer
+	b.WriteString(frontMatterDelimiter)
+	field := func(name string, value any) {
+		data, _ := json.Marshal(value)
+		fmt.Fprintf(&b, "%s: %s\n", name, data)
+	}
+
+	field("title", note.Title)
+	field("notebook", note.Notebook)
+	field("created_at", note.CreatedAt.UTC())
+	field("modified_at", note.ModifiedAt.UTC())
+	tags := note.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	field("tags", tags)
+	if note.Pinned {
+		field("pinned", true)
+	}
+	if note.Archived {
+		field("archived", true)
+	}
+	if note.Due != nil {
+		field("due", note.Due.UTC())
+	}
+
+	b.WriteString(frontMatterDelimiter)
+	b.WriteString(note.Content)
+	return b.Bytes()
+}
+
+// parse reads a note file written by render. The note's ID is not part of
+// the file; it is the file's name.
+func parse(id string, data []byte) (models.Note, error) {
+	text := string(data)
+	rest, ok := strings.CutPrefix(text, frontMatterDelimiter)
+	if !ok {
+		return models.Note{}, errors.New("missing front matter")
+	}
+	header, content, ok := strings.Cut(rest, "\n"+frontMatterDelimiter)
+	if !ok {
+		return models.Note{}, errors.New("unterminated front matter")
+	}
+
+	// Rebuild the fields as a JSON object, since every value is JSON
+	var object strings.Builder
+	object.WriteString("{")
+	for i, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return models.Note{}, fmt.Errorf("invalid front matter line %q", line)
+		}
+		if i > 0 {
+			object.WriteString(",")
+		}
+		fmt.Fprintf(&object, "%q:%s", name, value)
+	}
+	object.WriteString("}")
+
+	var fields frontMatter
+	if err := json.Unmarshal([]byte(object.String()), &fields); err != nil {
+		return models.Note{}, fmt.Errorf("invalid front matter: %w", err)
+	}
+	if fields.Title == "" || fields.Notebook == "" {
+		return models.Note{}, errors.New("front matter must have a title and a notebook")
+	}
+
+	return models.Note{
+		ID:         id,
+		Title:      fields.Title,
+		Notebook:   fields.Notebook,
+		Content:    content,
+		CreatedAt:  fields.CreatedAt,
+		ModifiedAt: fields.ModifiedAt,
+		Tags:       fields.Tags,
+		Pinned:     fields.Pinned,
+		Archived:   fields.Archived,
+		Due:        fields.Due,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package gitsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// fallbackIdentity is used for commits when git has no user configured.
+var fallbackIdentity = []string{"-c", "user.name=cli-note", "-c", "user.email=cli-note@localhost"}
+
+// git runs a git command in the repository and returns its output, trimmed.
+func (r *Repo) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, message)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// succeeds reports whether a git command exits successfully, for commands
+// that answer a question with their exit status.
+func (r *Repo) succeeds(args ...string) bool {
+	_, err := r.git(args...)
+	return err == nil
+}
+
+// gitAs runs a git command that creates commits, as the configured user
+// or, if there is none, as cli-note.
+func (r *Repo) gitAs(args ...string) (string, error) {
+	if !r.succeeds("config", "user.email") {
+		args = append(append([]string{}, fallbackIdentity...), args...)
+	}
+	return r.git(args...)
+}
+
+// checkGit returns an error if git isn't installed.
+func checkGit() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git is not installed or not in your PATH")
+	}
+	return nil
+}
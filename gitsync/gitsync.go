@@ -0,0 +1,567 @@
+// Package gitsync keeps notes in a local git repository, to version them
+// and share them between machines through any git remote, such as a bare
+// repository on a USB drive or a server reachable over SSH.
+//
+// Every note is stored in notes/<id>.md, its fields as front matter above
+// its content, so file names never change when notes are renamed or moved.
+// A sync commits the notes as they are in the database, merges the
+// remote's branch, imports the merged notes into the database, and pushes.
+//
+// When a note changed on both sides and git can't merge the changes, the
+// local version wins; the other stays in the history of the repository.
+package gitsync
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/validator"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// notesDir is the directory of the repository holding the notes.
+	notesDir = "notes"
+	// remote is the name of the remote notes are pulled from and pushed to.
+	remote = "origin"
+
+	dirPermissions  = 0700
+	filePermissions = 0600
+)
+
+// ErrNotRepository is returned when syncing a directory that isn't a git repository.
+var ErrNotRepository = errors.New("not a git repository")
+
+// Kind is how a note changed.
+type Kind int
+
+const (
+	Created Kind = iota
+	Edited
+	Deleted
+)
+
+var kindNames = map[Kind]string{
+	Created: "created",
+	Edited:  "edited",
+	Deleted: "deleted",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Change is a note that changed.
+type Change struct {
+	Kind  Kind
+	Title string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%-8s %s", c.Kind, c.Title)
+}
+
+// Result describes what a sync did.
+type Result struct {
+	// Commits are the subjects of the commits made.
+	Commits []string
+	// Pulled reports whether the remote's changes were fetched and merged.
+	Pulled bool
+	// Imported are the notes changed in the database by the merge.
+	Imported []Change
+	// Pushed reports whether the branch was pushed to the remote.
+	Pushed bool
+	// Warnings are conflicts and files that couldn't be imported.
+	Warnings []string
+}
+
+// Repo is a git repository of notes.
+type Repo struct {
+	dir string
+	db  *bolt.DB
+}
+
+// Open returns the repository in dir, for the notes in database.
+func Open(dir string, database *bolt.DB) (*Repo, error) {
+	if err := checkGit(); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotRepository, dir)
+	}
+	return &Repo{dir: dir, db: database}, nil
+}
+
+// Init creates a git repository in dir, unless there is one, for the notes
+// in database. If remoteURL isn't empty, it is set as the remote to sync with.
+func Init(dir string, database *bolt.DB, remoteURL string) (*Repo, error) {
+	if err := checkGit(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, notesDir), dirPermissions); err != nil {
+		return nil, fmt.Errorf("error creating repository directory: %w", err)
+	}
+	r := &Repo{dir: dir, db: database}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); errors.Is(err, fs.ErrNotExist) {
+		if _, err := r.git("init", "--quiet", "--initial-branch=main"); err != nil {
+			return nil, err
+		}
+	}
+
+	if remoteURL != "" {
+		command := "add"
+		if r.succeeds("remote", "get-url", remote) {
+			command = "set-url"
+		}
+		if _, err := r.git("remote", command, remote, remoteURL); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Sync commits the notes, merges the remote's changes into them, and
+// pushes the result, if the repository has a remote.
+func (r *Repo) Sync() (Result, error) {
+	var result Result
+	if err := r.commitNotes(&result); err != nil {
+		return result, err
+	}
+
+	hasRemote := r.succeeds("remote", "get-url", remote)
+	if hasRemote {
+		if err := r.pull(&result); err != nil {
+			return result, err
+		}
+	}
+
+	imported, warnings, err := r.importNotes()
+	result.Imported = imported
+	result.Warnings = append(result.Warnings, warnings...)
+	if err != nil {
+		return result, err
+	}
+	// Importing may have renamed notes whose titles clashed
+	if err := r.commitNotes(&result); err != nil {
+		return result, err
+	}
+
+	if hasRemote {
+		if err := r.push(&result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// push pushes the current branch to the remote, unless the remote already
+// has all its commits.
+func (r *Repo) push(result *Result) error {
+	if !r.succeeds("rev-parse", "--verify", "--quiet", "HEAD") {
+		// Nothing was committed yet
+		return nil
+	}
+	branch, err := r.branch()
+	if err != nil {
+		return err
+	}
+	if r.succeeds("merge-base", "--is-ancestor", "HEAD", "refs/remotes/"+remote+"/"+branch) {
+		return nil
+	}
+	if _, err := r.git("push", "--quiet", "--set-upstream", remote, branch); err != nil {
+		return err
+	}
+	result.Pushed = true
+	return nil
+}
+
+// branch returns the name of the current branch.
+func (r *Repo) branch() (string, error) {
+	return r.git("symbolic-ref", "--short", "HEAD")
+}
+
+// notePath returns the path of a note's file relative to the repository.
+func notePath(id string) string {
+	return notesDir + "/" + id + ".md"
+}
+
+// readFiles returns the content of the note files by note ID.
+func (r *Repo) readFiles() (map[string][]byte, error) {
+	entries, err := os.ReadDir(filepath.Join(r.dir, notesDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error reading notes directory: %w", err)
+	}
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(r.dir, notesDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", notePath(id), err)
+		}
+		files[id] = data
+	}
+	return files, nil
+}
+
+// commitNotes writes every note to its file, removes the files of deleted
+// notes, and commits the changes, if any.
+func (r *Repo) commitNotes(result *Result) error {
+	var notes []models.Note
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		notes, err = db.AllNotes(tx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error listing notes: %w", err)
+	}
+
+	files, err := r.readFiles()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(r.dir, notesDir), dirPermissions); err != nil {
+		return fmt.Errorf("error creating notes directory: %w", err)
+	}
+
+	titles := make(map[string]string, len(notes))
+	for _, note := range notes {
+		titles[note.ID] = note.Title
+		data := render(note)
+		if string(files[note.ID]) == string(data) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(r.dir, filepath.FromSlash(notePath(note.ID))), data, filePermissions); err != nil {
+			return fmt.Errorf("error writing note %q: %w", note.Title, err)
+		}
+	}
+	for id := range files {
+		if _, ok := titles[id]; !ok {
+			if err := os.Remove(filepath.Join(r.dir, filepath.FromSlash(notePath(id)))); err != nil {
+				return fmt.Errorf("error removing %s: %w", notePath(id), err)
+			}
+		}
+	}
+
+	if _, err := r.git("add", "--all", "--", notesDir); err != nil {
+		return err
+	}
+	changes, err := r.stagedChanges(titles)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	subject, body := commitMessage(changes)
+	if _, err := r.gitAs("commit", "--quiet", "-m", subject, "-m", body); err != nil {
+		return err
+	}
+	result.Commits = append(result.Commits, subject)
+	return nil
+}
+
+// stagedChanges returns the notes changed in the index. Deleted notes are
+// named by their title in the last commit.
+func (r *Repo) stagedChanges(titles map[string]string) ([]Change, error) {
+	output, err := r.git("diff", "--cached", "--name-status", "--no-renames", "--", notesDir)
+	if err != nil || output == "" {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, line := range strings.Split(output, "\n") {
+		status, path, _ := strings.Cut(line, "\t")
+		id := strings.TrimSuffix(strings.TrimPrefix(path, notesDir+"/"), ".md")
+		switch status {
+		case "A":
+			changes = append(changes, Change{Kind: Created, Title: titles[id]})
+		case "D":
+			title := id
+			if data, err := r.git("show", "HEAD:"+path); err == nil {
+				if note, err := parse(id, []byte(data+"\n")); err == nil {
+					title = note.Title
+				}
+			}
+			changes = append(changes, Change{Kind: Deleted, Title: title})
+		default:
+			changes = append(changes, Change{Kind: Edited, Title: titles[id]})
+		}
+	}
+	sortChanges(changes)
+	return changes, nil
+}
+
+func sortChanges(changes []Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Title < changes[j].Title
+	})
+}
+
+// commitMessage describes changes: a single change in the subject, more in
+// the body, grouped by kind.
+func commitMessage(changes []Change) (subject, body string) {
+	verbs := map[Kind]string{Created: "Create", Edited: "Edit", Deleted: "Delete"}
+	if len(changes) == 1 {
+		return fmt.Sprintf("%s %q", verbs[changes[0].Kind], changes[0].Title), ""
+	}
+
+	var b strings.Builder
+	for i, change := range changes {
+		if i == 0 || change.Kind != changes[i-1].Kind {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s:\n", strings.ToUpper(change.Kind.String()[:1])+change.Kind.String()[1:])
+		}
+		fmt.Fprintf(&b, "  - %s\n", change.Title)
+	}
+	return fmt.Sprintf("Update %d notes", len(changes)), strings.TrimSuffix(b.String(), "\n")
+}
+
+// pull fetches the remote and merges its branch, if it has one. Files both
+// sides changed in ways git can't merge are resolved in favor of the local
+// version, or of whichever side didn't delete the note.
+func (r *Repo) pull(result *Result) error {
+	branch, err := r.branch()
+	if err != nil {
+		return err
+	}
+	if _, err := r.git("fetch", "--quiet", remote); err != nil {
+		return err
+	}
+	remoteBranch := remote + "/" + branch
+	if !r.succeeds("rev-parse", "--verify", "--quiet", "refs/remotes/"+remoteBranch) {
+		// Nothing was pushed yet
+		return nil
+	}
+
+	if !r.succeeds("rev-parse", "--verify", "--quiet", "HEAD") {
+		// Nothing was committed yet, so the remote's branch can be taken as is
+		result.Pulled = true
+		_, err := r.git("reset", "--quiet", "--hard", remoteBranch)
+		return err
+	}
+	if r.succeeds("merge-base", "--is-ancestor", remoteBranch, "HEAD") {
+		// Already merged
+		return nil
+	}
+	result.Pulled = true
+
+	_, mergeErr := r.gitAs("merge", "--quiet", "--no-edit", "--allow-unrelated-histories", remoteBranch)
+	if mergeErr == nil {
+		return nil
+	}
+	conflicts, err := r.git("ls-files", "--unmerged", "--", notesDir)
+	if err != nil || conflicts == "" {
+		// The merge failed for another reason than conflicts
+		r.git("merge", "--abort")
+		return mergeErr
+	}
+
+	theirs, err := r.git("rev-parse", "--short", "MERGE_HEAD")
+	if err != nil {
+		return err
+	}
+	for path, stages := range unmergedStages(conflicts) {
+		side := "--ours"
+		if !stages[2] {
+			side = "--theirs"
+		}
+		if _, err := r.git("checkout", side, "--", path); err != nil {
+			return err
+		}
+		if _, err := r.git("add", "--", path); err != nil {
+			return err
+		}
+
+		title := path
+		if data, err := os.ReadFile(filepath.Join(r.dir, filepath.FromSlash(path))); err == nil {
+			id := strings.TrimSuffix(strings.TrimPrefix(path, notesDir+"/"), ".md")
+			if note, err := parse(id, data); err == nil {
+				title = note.Title
+			}
+		}
+		if side == "--ours" && stages[3] {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%q was changed on both sides; kept this version. The other one is in commit %s: git -C %s show %s:%s", title, theirs, r.dir, theirs, path))
+		} else if side == "--ours" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%q was deleted on the other side but changed here; kept it", title))
+		} else {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%q was deleted here but changed on the other side; restored it", title))
+		}
+	}
+	_, err = r.gitAs("commit", "--quiet", "--no-edit")
+	return err
+}
+
+// unmergedStages maps each unmerged path in the output of 'git ls-files
+// --unmerged' to its stages: 1 for the common version, 2 for ours and 3
+// for theirs.
+func unmergedStages(output string) map[string]map[int]bool {
+	paths := make(map[string]map[int]bool)
+	for _, line := range strings.Split(output, "\n") {
+		info, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			continue
+		}
+		if paths[path] == nil {
+			paths[path] = make(map[int]bool)
+		}
+		paths[path][int(fields[2][0]-'0')] = true
+	}
+	return paths
+}
+
+// importNotes updates the database to match the note files: notes whose
+// file changed are replaced, notes without a file are deleted, and files
+// without a note are created as notes, keeping their IDs. An imported note
+// whose title is taken in its notebook gets a number after its title.
+func (r *Repo) importNotes() ([]Change, []string, error) {
+	files, err := r.readFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []Change
+	var warnings []string
+	err = r.db.Update(func(tx *bolt.Tx) error {
+		notes, err := db.AllNotes(tx)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]models.Note, len(notes))
+		for _, note := range notes {
+			existing[note.ID] = note
+		}
+
+		// Remove changed and deleted notes first, so that their titles are free
+		var incoming []models.Note
+		for _, note := range notes {
+			data, ok := files[note.ID]
+			if ok && string(data) == string(render(note)) {
+				continue
+			}
+			if err := db.RemoveNote(tx, note); err != nil {
+				return err
+			}
+			if !ok {
+				changes = append(changes, Change{Kind: Deleted, Title: note.Title})
+			}
+		}
+		ids := make([]string, 0, len(files))
+		for id := range files {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if note, ok := existing[id]; ok && string(files[id]) == string(render(note)) {
+				continue
+			}
+			note, err := parse(id, files[id])
+			if err == nil {
+				err = validateNames(note)
+			}
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped %s: %v", notePath(id), err))
+				if old, ok := existing[id]; ok {
+					// Keep the note rather than lose it to an unreadable file
+					incoming = append(incoming, old)
+				}
+				continue
+			}
+			incoming = append(incoming, note)
+		}
+
+		for _, note := range incoming {
+			old, existed := existing[note.ID]
+			note.EditCount = old.EditCount
+			title, err := freeTitle(tx, note)
+			if err != nil {
+				return err
+			}
+			if title != note.Title {
+				warnings = append(warnings, fmt.Sprintf("notebook %q already has a note titled %q; renamed the pulled one to %q", note.Notebook, note.Title, title))
+				note.Title = title
+			}
+			if err := db.PutNote(tx, note); err != nil {
+				return err
+			}
+			if err := db.PutNoteTitle(tx, note); err != nil {
+				return err
+			}
+
+			kind := Created
+			if existed {
+				kind = Edited
+			}
+			if !existed || string(render(old)) != string(render(note)) {
+				changes = append(changes, Change{Kind: kind, Title: note.Title})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, warnings, fmt.Errorf("error importing notes: %w", err)
+	}
+	sortChanges(changes)
+	return changes, warnings, nil
+}
+
+// validateNames checks that a pulled note's title and notebook name are
+// ones 'new' and 'notebook create' would accept.
+func validateNames(note models.Note) error {
+	if err := validator.ValidateName("note", note.Title); err != nil {
+		return err
+	}
+	return validator.ValidateName("notebook", note.Notebook)
+}
+
+// freeTitle returns the note's title, or if another note in its notebook
+// has it, the title followed by the first free number. The title is cut
+// short if needed so that it stays within validator.MaxNameLength. The
+// notebook is created if it doesn't exist.
+func freeTitle(tx *bolt.Tx, note models.Note) (string, error) {
+	if _, err := db.GetNotebook(tx, note.Notebook); errors.Is(err, db.ErrNotebookNotFound) {
+		if _, err := db.CreateNotebook(tx, note.Notebook); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	titles, err := db.NotebookTitles(tx, note.Notebook)
+	if err != nil {
+		return "", err
+	}
+	title := note.Title
+	for i := 2; ; i++ {
+		owner := titles.Get(db.TitleKey(title))
+		if owner == nil || string(owner) == note.ID {
+			return title, nil
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		title = truncate(note.Title, validator.MaxNameLength-utf8.RuneCountInString(suffix)) + suffix
+	}
+}
+
+// truncate returns title cut to at most n characters, without trailing spaces.
+func truncate(title string, n int) string {
+	runes := []rune(strings.TrimSpace(title))
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+	return strings.TrimSpace(string(runes))
+}
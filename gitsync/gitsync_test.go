@@ -0,0 +1,260 @@
+package gitsync
+
+import (
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+	"github.com/rhysmah/CLI-Note-App/validator"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// clone is a notes database with its repository.
+type clone struct {
+	store *store.Store
+	repo  *Repo
+}
+
+// newRemote returns the path of a new bare repository, skipping the test
+// if git isn't installed. Git's user and system settings are ignored.
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	return remote
+}
+
+// newClone returns a new database with notes titled titles, in a
+// repository syncing with remote.
+func newClone(t *testing.T, remote string, titles ...string) clone {
+	t.Helper()
+	testDB, _ := testutil.SetupTestDB(t)
+	noteStore := store.New(testDB, db.DefaultNotebook)
+	for _, title := range titles {
+		if _, err := noteStore.CreateNote(api.NoteInput{Title: &title, Content: ptr(title + " content")}); err != nil {
+			t.Fatalf("CreateNote() error = %v", err)
+		}
+	}
+	repo, err := Init(filepath.Join(t.TempDir(), "repo"), testDB, remote)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	return clone{store: noteStore, repo: repo}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func (c clone) sync(t *testing.T) Result {
+	t.Helper()
+	result, err := c.repo.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	return result
+}
+
+// titles returns the titles of the clone's notes, sorted.
+func (c clone) titles(t *testing.T) string {
+	t.Helper()
+	notes, err := c.store.Notes("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, note := range notes {
+		titles = append(titles, note.Title)
+	}
+	sort.Strings(titles)
+	return strings.Join(titles, ", ")
+}
+
+func TestRenderParse(t *testing.T) {
+	due := time.Date(2025, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	note := models.Note{
+		ID:         "abc",
+		Title:      "Groceries",
+		Notebook:   "home",
+		Content:    "---\n- milk\n",
+		CreatedAt:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		ModifiedAt: time.Date(2025, 2, 1, 0, 0, 0, 5, time.UTC),
+		Tags:       []string{"food"},
+		Pinned:     true,
+		Due:        &due,
+	}
+
+	data := render(note)
+	parsed, err := parse("abc", data)
+	if err != nil {
+		t.Fatalf("parse() error = %v\n%s", err, data)
+	}
+	if string(render(parsed)) != string(data) {
+		t.Errorf("render(parse(file)) =\n%s\nwant\n%s", render(parsed), data)
+	}
+	if parsed.Content != note.Content || !parsed.Due.Equal(due) || !parsed.Pinned || parsed.Archived {
+		t.Errorf("parse() = %+v; want %+v", parsed, note)
+	}
+
+	if _, err := parse("abc", []byte("no front matter")); err == nil {
+		t.Error("parse() of a file without front matter succeeded")
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	subject, body := commitMessage([]Change{{Kind: Edited, Title: "Ideas"}})
+	if subject != `Edit "Ideas"` || body != "" {
+		t.Errorf("commitMessage() = %q, %q", subject, body)
+	}
+
+	subject, body = commitMessage([]Change{{Created, "A"}, {Created, "B"}, {Deleted, "C"}})
+	want := "Created:\n  - A\n  - B\n\nDeleted:\n  - C"
+	if subject != "Update 3 notes" || body != want {
+		t.Errorf("commitMessage() = %q, %q; want body %q", subject, body, want)
+	}
+}
+
+func TestSyncBetweenClones(t *testing.T) {
+	remote := newRemote(t)
+	a := newClone(t, remote, "Groceries", "Ideas")
+	if result := a.sync(t); len(result.Commits) != 1 || !result.Pushed {
+		t.Fatalf("first Sync() = %+v; want a commit pushed", result)
+	}
+
+	b := newClone(t, remote, "Todo")
+	result := b.sync(t)
+	if !result.Pulled || len(result.Imported) != 2 || b.titles(t) != "Groceries, Ideas, Todo" {
+		t.Fatalf("Sync() of the second clone = %+v; notes %s", result, b.titles(t))
+	}
+
+	// Changes made in the second clone reach the first
+	if _, err := b.store.UpdateNote("", "Groceries", "", api.NoteInput{Title: ptr("Shopping"), Content: ptr("bread")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.store.DeleteNote("", "Ideas", ""); err != nil {
+		t.Fatal(err)
+	}
+	if result := b.sync(t); len(result.Commits) != 1 || result.Commits[0] != "Update 2 notes" {
+		t.Fatalf("Sync() commits = %v", result.Commits)
+	}
+
+	result = a.sync(t)
+	var imported []string
+	for _, change := range result.Imported {
+		imported = append(imported, change.String())
+	}
+	want := []string{"created  Todo", "edited   Shopping", "deleted  Ideas"}
+	if strings.Join(imported, "\n") != strings.Join(want, "\n") {
+		t.Errorf("imported:\n%s\nwant:\n%s", strings.Join(imported, "\n"), strings.Join(want, "\n"))
+	}
+	if note, err := a.store.Note("", "Shopping"); err != nil || note.Content != "bread" {
+		t.Errorf("Shopping = %+v, %v; want it renamed with its new content", note, err)
+	}
+
+	// Nothing changed since
+	if result := a.sync(t); len(result.Commits) != 0 || len(result.Imported) != 0 || result.Pushed || result.Pulled {
+		t.Errorf("Sync() without changes = %+v", result)
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	remote := newRemote(t)
+	a := newClone(t, remote, "Groceries")
+	a.sync(t)
+	b := newClone(t, remote)
+	b.sync(t)
+
+	if _, err := a.store.UpdateNote("", "Groceries", "", api.NoteInput{Content: ptr("from a")}); err != nil {
+		t.Fatal(err)
+	}
+	a.sync(t)
+	if _, err := b.store.UpdateNote("", "Groceries", "", api.NoteInput{Content: ptr("from b")}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := b.sync(t)
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `"Groceries" was changed on both sides`) {
+		t.Fatalf("warnings = %v; want a conflict", result.Warnings)
+	}
+	if note, _ := b.store.Note("", "Groceries"); note.Content != "from b" {
+		t.Errorf("content = %q; want the local version kept", note.Content)
+	}
+
+	a.sync(t)
+	if note, _ := a.store.Note("", "Groceries"); note.Content != "from b" {
+		t.Errorf("content in the first clone = %q; want the merged version", note.Content)
+	}
+}
+
+func TestSyncRenamesClashingTitles(t *testing.T) {
+	remote := newRemote(t)
+	a := newClone(t, remote, "Ideas")
+	a.sync(t)
+	b := newClone(t, remote, "Ideas")
+
+	result := b.sync(t)
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `renamed the pulled one to "Ideas (2)"`) {
+		t.Fatalf("warnings = %v; want the pulled note renamed", result.Warnings)
+	}
+	a.sync(t)
+	if got := a.titles(t); got != "Ideas, Ideas (2)" {
+		t.Errorf("notes in the first clone = %s", got)
+	}
+}
+
+func TestSyncSkipsInvalidNames(t *testing.T) {
+	remote := newRemote(t)
+	a := newClone(t, remote)
+	// Written behind the validator's back, as another tool could
+	err := a.repo.db.Update(func(tx *bolt.Tx) error {
+		note := models.Note{ID: "abc", Title: "a/b", Notebook: db.DefaultNotebook}
+		if err := db.PutNote(tx, note); err != nil {
+			return err
+		}
+		return db.PutNoteTitle(tx, note)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.sync(t)
+
+	b := newClone(t, remote)
+	result := b.sync(t)
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "skipped notes/abc.md") {
+		t.Fatalf("warnings = %v; want the file skipped", result.Warnings)
+	}
+	if got := b.titles(t); got != "" {
+		t.Errorf("notes = %s; want none imported", got)
+	}
+}
+
+func TestSyncRenamesLongClashingTitles(t *testing.T) {
+	remote := newRemote(t)
+	title := strings.Repeat("x", validator.MaxNameLength)
+	a := newClone(t, remote, title)
+	a.sync(t)
+	b := newClone(t, remote, title)
+
+	b.sync(t)
+	want := title[:validator.MaxNameLength-4] + " (2), " + title
+	if got := b.titles(t); got != want {
+		t.Errorf("notes = %s; want %s", got, want)
+	}
+}
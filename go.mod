@@ -2,12 +2,16 @@ module github.com/rhysmah/CLI-Note-App
 
 go 1.23.4
 
-require github.com/spf13/cobra v1.9.1
+require (
+	github.com/google/uuid v1.6.0
+	github.com/spf13/cobra v1.9.1
+	go.etcd.io/bbolt v1.4.0
+	golang.org/x/term v0.28.0
+	golang.org/x/text v0.21.0
+)
 
 require (
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
-	go.etcd.io/bbolt v1.4.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 )
@@ -0,0 +1,178 @@
+// Package graph builds the graph of notes, their [[links]] and their tags,
+// and encodes it as Graphviz DOT or JSON.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// NodeKind tells notes and tags apart.
+type NodeKind string
+
+const (
+	NoteNode NodeKind = "note"
+	TagNode  NodeKind = "tag"
+)
+
+// EdgeKind tells links between notes and tag memberships apart.
+type EdgeKind string
+
+const (
+	LinkEdge EdgeKind = "link"
+	TagEdge  EdgeKind = "tag"
+)
+
+// tagIDPrefix is prepended to tag names to form their node IDs,
+// so they can't clash with note IDs.
+const tagIDPrefix = "tag:"
+
+// Node is a note or a tag.
+type Node struct {
+	ID       string   `json:"id"`
+	Kind     NodeKind `json:"kind"`
+	Label    string   `json:"label"`
+	Notebook string   `json:"notebook,omitempty"`
+}
+
+// Edge is a link from one note to another, or from a note to one of its tags.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+// Graph is a set of nodes and the edges between them.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Options controls which notes and edges a graph contains.
+type Options struct {
+	// Tags adds a node for every tag and an edge from each note to its tags.
+	Tags bool
+	// FilterTags keeps only the notes that have all of these tags.
+	FilterTags []string
+}
+
+// Build returns the graph of the given notes. links maps note IDs to the IDs
+// of the notes they link to; links to notes that aren't in the graph are left out.
+// Nodes are sorted by kind and label, and edges by their endpoints.
+func Build(notes []models.Note, links map[string][]string, opts Options) Graph {
+	var graph Graph
+	included := make(map[string]bool)
+	tags := make(map[string]bool)
+
+	for _, note := range notes {
+		if !hasTags(note, opts.FilterTags) {
+			continue
+		}
+		included[note.ID] = true
+		graph.Nodes = append(graph.Nodes, Node{ID: note.ID, Kind: NoteNode, Label: note.Title, Notebook: note.Notebook})
+
+		if opts.Tags {
+			for _, tag := range note.Tags {
+				tags[tag] = true
+				graph.Edges = append(graph.Edges, Edge{From: note.ID, To: tagIDPrefix + tag, Kind: TagEdge})
+			}
+		}
+	}
+
+	for tag := range tags {
+		graph.Nodes = append(graph.Nodes, Node{ID: tagIDPrefix + tag, Kind: TagNode, Label: tag})
+	}
+
+	for from, targets := range links {
+		if !included[from] {
+			continue
+		}
+		for _, to := range targets {
+			if included[to] {
+				graph.Edges = append(graph.Edges, Edge{From: from, To: to, Kind: LinkEdge})
+			}
+		}
+	}
+
+	sort.Slice(graph.Nodes, func(a, b int) bool {
+		if graph.Nodes[a].Kind != graph.Nodes[b].Kind {
+			return graph.Nodes[a].Kind == NoteNode
+		}
+		if graph.Nodes[a].Label != graph.Nodes[b].Label {
+			return strings.ToLower(graph.Nodes[a].Label) < strings.ToLower(graph.Nodes[b].Label)
+		}
+		return graph.Nodes[a].ID < graph.Nodes[b].ID
+	})
+	sort.Slice(graph.Edges, func(a, b int) bool {
+		if graph.Edges[a].From != graph.Edges[b].From {
+			return graph.Edges[a].From < graph.Edges[b].From
+		}
+		return graph.Edges[a].To < graph.Edges[b].To
+	})
+	return graph
+}
+
+// hasTags reports whether a note has every one of the given tags.
+func hasTags(note models.Note, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(note.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteJSON writes the graph as an indented JSON object with "nodes" and "edges".
+func (g Graph) WriteJSON(w io.Writer) error {
+	if g.Nodes == nil {
+		g.Nodes = []Node{}
+	}
+	if g.Edges == nil {
+		g.Edges = []Edge{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph in Graphviz DOT syntax. Notes are drawn as
+// ellipses and tags as boxes; tag memberships are drawn dashed.
+func (g Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph notes {\n")
+	for _, node := range g.Nodes {
+		switch node.Kind {
+		case TagNode:
+			fmt.Fprintf(&b, "  %s [label=%s, shape=box];\n", dotQuote(node.ID), dotQuote("#"+node.Label))
+		default:
+			fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(node.ID), dotQuote(node.Label))
+		}
+	}
+	for _, edge := range g.Edges {
+		switch edge.Kind {
+		case TagEdge:
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed];\n", dotQuote(edge.From), dotQuote(edge.To))
+		default:
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote returns s as a double-quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+var testNotes = []models.Note{
+	{ID: "1", Title: "Plan", Notebook: "work", Tags: []string{"project"}},
+	{ID: "2", Title: "Budget", Notebook: "work", Tags: []string{"project", "money"}},
+	{ID: "3", Title: "Groceries", Notebook: "home"},
+}
+
+var testLinks = map[string][]string{
+	"1": {"2", "3"},
+	"3": {"missing"},
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		wantNodes []string
+		wantEdges int
+	}{
+		{
+			name:      "Notes And Links",
+			opts:      Options{},
+			wantNodes: []string{"2", "3", "1"},
+			wantEdges: 2,
+		},
+		{
+			name:      "With Tags",
+			opts:      Options{Tags: true},
+			wantNodes: []string{"2", "3", "1", "tag:money", "tag:project"},
+			wantEdges: 5,
+		},
+		{
+			name:      "Filtered By Tag",
+			opts:      Options{FilterTags: []string{"project"}},
+			wantNodes: []string{"2", "1"},
+			wantEdges: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := Build(testNotes, testLinks, tt.opts)
+
+			var ids []string
+			for _, node := range graph.Nodes {
+				ids = append(ids, node.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantNodes, ",") {
+				t.Errorf("Nodes = %v; want %v", ids, tt.wantNodes)
+			}
+			if len(graph.Edges) != tt.wantEdges {
+				t.Errorf("Got %d edges; want %d: %v", len(graph.Edges), tt.wantEdges, graph.Edges)
+			}
+		})
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	graph := Build([]models.Note{
+		{ID: "1", Title: `Say "hi"`, Tags: []string{"x"}},
+		{ID: "2", Title: "Other"},
+	}, map[string][]string{"1": {"2"}}, Options{Tags: true})
+
+	var out bytes.Buffer
+	if err := graph.WriteDOT(&out); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+
+	want := `digraph notes {
+  "2" [label="Other"];
+  "1" [label="Say \"hi\""];
+  "tag:x" [label="#x", shape=box];
+  "1" -> "2";
+  "1" -> "tag:x" [style=dashed];
+}
+`
+	if out.String() != want {
+		t.Errorf("WriteDOT() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := Build(nil, nil, Options{}).WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded map[string][]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() produced invalid JSON: %v", err)
+	}
+	if decoded["nodes"] == nil || decoded["edges"] == nil {
+		t.Errorf("Expected empty node and edge arrays; got %s", out.String())
+	}
+}
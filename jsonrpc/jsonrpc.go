@@ -0,0 +1,413 @@
+// Package jsonrpc serves notes over JSON-RPC 2.0 on a stream, such as the
+// standard input and output of 'cli-note rpc', for editor integrations.
+//
+// Messages are JSON objects, or arrays of them for batches, one per line.
+// Every request is answered on one line, in order; notifications (requests
+// without an id) are not answered. Params are passed by name.
+//
+// Methods:
+//
+//	list    {notebook?}                        notes in notebook, or all notes
+//	get     {id, notebook?}                    a note by ID, ID prefix or title
+//	create  {title, notebook?, content?, tags?, pinned?, archived?, due?}
+//	update  {id, notebook?, if_match?, changes: {title?, notebook?, content?, ...}}
+//	delete  {id, notebook?, if_match?}         returns null
+//	search  {query, notebook?}                 notes whose title or content matches
+//	tag     {id, notebook?, if_match?, add?, remove?}  add and remove tags of a note
+//	tags    {notebook?}                        tags and how many notes have them
+//
+// Notes are returned with an "etag" field. Passing it back as if_match makes
+// update, delete and tag fail with CodePreconditionFailed if the note changed
+// in the meantime.
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// Error codes: the ones defined by JSON-RPC 2.0, and the app's own.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	CodeNotFound           = -32001
+	CodeConflict           = -32002
+	CodePreconditionFailed = -32003
+)
+
+const version = "2.0"
+
+// Note is a note as returned by the methods, with its ETag for if_match.
+type Note struct {
+	models.Note
+	ETag string `json:"etag"`
+}
+
+// Error is the error object of a failed request.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Server answers JSON-RPC requests from a Store.
+type Server struct {
+	store   api.Store
+	methods map[string]func(params json.RawMessage) (any, error)
+}
+
+// NewServer returns a Server for store.
+func NewServer(store api.Store) *Server {
+	s := &Server{store: store}
+	s.methods = map[string]func(json.RawMessage) (any, error){
+		"list":   s.list,
+		"get":    s.get,
+		"create": s.create,
+		"update": s.update,
+		"delete": s.delete,
+		"search": s.search,
+		"tag":    s.tag,
+		"tags":   s.tags,
+	}
+	return s
+}
+
+// Serve reads requests from in and writes responses to out, one request at
+// a time, until in ends.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if answer := s.handleMessage(line); answer != nil {
+				if _, err := out.Write(append(answer, '\n')); err != nil {
+					return fmt.Errorf("error writing response: %w", err)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading request: %w", err)
+		}
+	}
+}
+
+// handleMessage answers a request or a batch of requests, returning nil
+// if there is nothing to answer.
+func (s *Server) handleMessage(message []byte) []byte {
+	message = bytes.TrimSpace(message)
+	if !json.Valid(message) {
+		return encode(errorResponse(nil, CodeParseError, "parse error: invalid JSON"))
+	}
+
+	if message[0] != '[' {
+		answer := s.handle(message)
+		if answer == nil {
+			return nil
+		}
+		return encode(*answer)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(message, &batch); err != nil || len(batch) == 0 {
+		return encode(errorResponse(nil, CodeInvalidRequest, "invalid request: empty batch"))
+	}
+	var answers []response
+	for _, message := range batch {
+		if answer := s.handle(message); answer != nil {
+			answers = append(answers, *answer)
+		}
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+	return encode(answers)
+}
+
+// handle answers a single request, returning nil for notifications.
+func (s *Server) handle(message json.RawMessage) *response {
+	var req request
+	if err := json.Unmarshal(message, &req); err != nil || req.JSONRPC != version || req.Method == "" {
+		answer := errorResponse(nil, CodeInvalidRequest, `invalid request: expected {"jsonrpc": "2.0", "method": ..., "id": ...}`)
+		return &answer
+	}
+
+	result, err := s.call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		answer := response{JSONRPC: version, ID: req.ID, Error: toError(err)}
+		return &answer
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		answer := errorResponse(req.ID, CodeInternalError, fmt.Sprintf("error encoding result: %v", err))
+		return &answer
+	}
+	return &response{JSONRPC: version, ID: req.ID, Result: data}
+}
+
+func (s *Server) call(method string, params json.RawMessage) (any, error) {
+	handler, ok := s.methods[method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method %q not found", method)}
+	}
+	return handler(params)
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return response{JSONRPC: version, ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+func encode(v any) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// toError turns an error from a method into an error object.
+func toError(err error) *Error {
+	var rpcErr *Error
+	var ambiguous *db.AmbiguousPrefixError
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr
+	case errors.Is(err, db.ErrNoteNotFound), errors.Is(err, db.ErrNotebookNotFound):
+		return &Error{Code: CodeNotFound, Message: err.Error()}
+	case errors.Is(err, api.ErrConflict):
+		return &Error{Code: CodeConflict, Message: err.Error()}
+	case errors.Is(err, api.ErrPreconditionFailed):
+		return &Error{Code: CodePreconditionFailed, Message: err.Error()}
+	case errors.Is(err, api.ErrInvalidRequest), errors.As(err, &ambiguous):
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	default:
+		return &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+}
+
+// decodeParams decodes named params into v, rejecting unknown names.
+// Missing params count as an empty object.
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+// requireID returns an invalid params error if id is empty.
+func requireID(id string) error {
+	if id == "" {
+		return &Error{Code: CodeInvalidParams, Message: `invalid params: "id" is required`}
+	}
+	return nil
+}
+
+func withETag(note models.Note) Note {
+	return Note{Note: note, ETag: api.ETag(note)}
+}
+
+func withETags(notes []models.Note) []Note {
+	results := make([]Note, 0, len(notes))
+	for _, note := range notes {
+		results = append(results, withETag(note))
+	}
+	return results
+}
+
+type notebookParams struct {
+	Notebook string `json:"notebook"`
+}
+
+type noteParams struct {
+	ID       string `json:"id"`
+	Notebook string `json:"notebook"`
+	IfMatch  string `json:"if_match"`
+}
+
+func (s *Server) list(params json.RawMessage) (any, error) {
+	var p notebookParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	notes, err := s.store.Notes(p.Notebook)
+	if err != nil {
+		return nil, err
+	}
+	return withETags(notes), nil
+}
+
+func (s *Server) get(params json.RawMessage) (any, error) {
+	var p struct {
+		ID       string `json:"id"`
+		Notebook string `json:"notebook"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := requireID(p.ID); err != nil {
+		return nil, err
+	}
+	note, err := s.store.Note(p.Notebook, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	return withETag(note), nil
+}
+
+func (s *Server) create(params json.RawMessage) (any, error) {
+	var input api.NoteInput
+	if err := decodeParams(params, &input); err != nil {
+		return nil, err
+	}
+	note, err := s.store.CreateNote(input)
+	if err != nil {
+		return nil, err
+	}
+	return withETag(note), nil
+}
+
+func (s *Server) update(params json.RawMessage) (any, error) {
+	var p struct {
+		noteParams
+		Changes api.NoteInput `json:"changes"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := requireID(p.ID); err != nil {
+		return nil, err
+	}
+	note, err := s.store.UpdateNote(p.Notebook, p.ID, p.IfMatch, p.Changes)
+	if err != nil {
+		return nil, err
+	}
+	return withETag(note), nil
+}
+
+func (s *Server) delete(params json.RawMessage) (any, error) {
+	var p noteParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := requireID(p.ID); err != nil {
+		return nil, err
+	}
+	_, err := s.store.DeleteNote(p.Notebook, p.ID, p.IfMatch)
+	return nil, err
+}
+
+func (s *Server) search(params json.RawMessage) (any, error) {
+	var p struct {
+		Query    string `json:"query"`
+		Notebook string `json:"notebook"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Query == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: `invalid params: "query" is required`}
+	}
+	notes, err := s.store.Search(p.Query, p.Notebook)
+	if err != nil {
+		return nil, err
+	}
+	return withETags(notes), nil
+}
+
+// tag adds and removes tags of a note. Without if_match, the note is
+// only updated if it hasn't changed since its tags were read.
+func (s *Server) tag(params json.RawMessage) (any, error) {
+	var p struct {
+		noteParams
+		Add    []string `json:"add"`
+		Remove []string `json:"remove"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if err := requireID(p.ID); err != nil {
+		return nil, err
+	}
+
+	note, err := s.store.Note(p.Notebook, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if p.IfMatch == "" {
+		p.IfMatch = api.ETag(note)
+	}
+
+	tags := make([]string, 0, len(note.Tags)+len(p.Add))
+	for _, tag := range note.Tags {
+		if !slices.Contains(p.Remove, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range p.Add {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	note, err = s.store.UpdateNote(note.Notebook, note.ID, p.IfMatch, api.NoteInput{Tags: &tags})
+	if err != nil {
+		return nil, err
+	}
+	return withETag(note), nil
+}
+
+func (s *Server) tags(params json.RawMessage) (any, error) {
+	var p notebookParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	tags, err := s.store.Tags(p.Notebook)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []api.Tag{}
+	}
+	return tags, nil
+}
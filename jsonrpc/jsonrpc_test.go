@@ -0,0 +1,139 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+)
+
+// exchange sends lines of requests to a Server over a test database and
+// returns its answers, one per line.
+func exchange(t *testing.T, requests ...string) []string {
+	t.Helper()
+	testDB, _ := testutil.SetupTestDB(t)
+	server := NewServer(store.New(testDB, db.DefaultNotebook))
+
+	var out strings.Builder
+	if err := server.Serve(strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+// decode decodes an answer into a response with its result left raw.
+func decode(t *testing.T, answer string) response {
+	t.Helper()
+	var r response
+	if err := json.Unmarshal([]byte(answer), &r); err != nil {
+		t.Fatalf("answer %q isn't JSON: %v", answer, err)
+	}
+	return r
+}
+
+func TestServeMethods(t *testing.T) {
+	answers := exchange(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "create", "params": {"title": "Budget", "content": "rent", "tags": ["money"]}}`,
+		`{"jsonrpc": "2.0", "method": "create", "params": {"title": "Ideas"}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "search", "params": {"query": "RENT"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "tag", "params": {"id": "budget", "add": ["home"], "remove": ["money"]}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "tags"}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "delete", "params": {"id": "Ideas"}}`,
+		`{"jsonrpc": "2.0", "id": "last", "method": "list"}`,
+	)
+	// The notification creating "Ideas" isn't answered
+	if len(answers) != 6 {
+		t.Fatalf("got %d answers; want 6:\n%s", len(answers), strings.Join(answers, "\n"))
+	}
+
+	for _, answer := range answers {
+		if r := decode(t, answer); r.Error != nil {
+			t.Fatalf("answer %s has error %+v", r.ID, r.Error)
+		}
+	}
+
+	var found []Note
+	json.Unmarshal(decode(t, answers[1]).Result, &found)
+	if len(found) != 1 || found[0].Title != "Budget" || found[0].ETag == "" {
+		t.Errorf("search result = %+v; want Budget with an etag", found)
+	}
+
+	var tagged Note
+	json.Unmarshal(decode(t, answers[2]).Result, &tagged)
+	if strings.Join(tagged.Tags, ",") != "home" {
+		t.Errorf("tag result tags = %v; want [home]", tagged.Tags)
+	}
+
+	if got := string(decode(t, answers[3]).Result); got != `[{"name":"home","count":1}]` {
+		t.Errorf("tags result = %s", got)
+	}
+	if got := string(decode(t, answers[4]).Result); got != "null" {
+		t.Errorf("delete result = %s; want null", got)
+	}
+
+	last := decode(t, answers[5])
+	var notes []Note
+	json.Unmarshal(last.Result, &notes)
+	if string(last.ID) != `"last"` || len(notes) != 1 {
+		t.Errorf("list answer id %s with %d notes; want \"last\" with 1", last.ID, len(notes))
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	answers := exchange(t,
+		`not json`,
+		`{"id": 1, "method": "list"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "explode"}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "get", "params": {"id": "missing"}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "get", "params": {"title": "wrong name"}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "create", "params": {"title": "Plan"}}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "create", "params": {"title": "plan"}}`,
+		`{"jsonrpc": "2.0", "id": 7, "method": "update", "params": {"id": "Plan", "if_match": "\"stale\"", "changes": {"content": "x"}}}`,
+	)
+
+	want := []int{
+		CodeParseError,
+		CodeInvalidRequest,
+		CodeMethodNotFound,
+		CodeNotFound,
+		CodeInvalidParams,
+		0,
+		CodeConflict,
+		CodePreconditionFailed,
+	}
+	if len(answers) != len(want) {
+		t.Fatalf("got %d answers; want %d:\n%s", len(answers), len(want), strings.Join(answers, "\n"))
+	}
+	for i, answer := range answers {
+		r := decode(t, answer)
+		code := 0
+		if r.Error != nil {
+			code = r.Error.Code
+		}
+		if code != want[i] {
+			t.Errorf("answer %d = %s; want error code %d", i, answer, want[i])
+		}
+	}
+}
+
+func TestServeBatch(t *testing.T) {
+	answers := exchange(t,
+		`[{"jsonrpc": "2.0", "id": 1, "method": "tags"}, {"jsonrpc": "2.0", "method": "list"}, {"jsonrpc": "2.0", "id": 2, "method": "list"}]`,
+		`[{"jsonrpc": "2.0", "method": "list"}]`,
+		`[]`,
+	)
+	if len(answers) != 2 {
+		t.Fatalf("got %d answers; want 2:\n%s", len(answers), strings.Join(answers, "\n"))
+	}
+
+	var batch []response
+	if err := json.Unmarshal([]byte(answers[0]), &batch); err != nil || len(batch) != 2 {
+		t.Errorf("batch answer = %s; want two responses", answers[0])
+	}
+	if r := decode(t, answers[1]); r.Error == nil || r.Error.Code != CodeInvalidRequest {
+		t.Errorf("empty batch answer = %s; want an invalid request error", answers[1])
+	}
+}
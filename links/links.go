@@ -0,0 +1,133 @@
+// Package links parses wiki-style [[links]] between notes.
+//
+// A link names the title of the target note, optionally prefixed with its
+// notebook and followed by a label:
+//
+//	[[Groceries]]            a note in the same notebook
+//	[[work/Standup]]         a note in the "work" notebook
+//	[[Standup|this morning]] a note shown with a different label
+//
+// Titles and notebook names cannot contain '/' or '|', so these separators
+// are unambiguous.
+package links
+
+import (
+	"regexp"
+	"strings"
+)
+
+// linkPattern matches [[...]] without nested brackets or line breaks.
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+
+// Link is a single [[link]] found in a note's content.
+type Link struct {
+	// Notebook is the notebook named in the link, or empty if the link
+	// refers to a note in the same notebook as the linking note.
+	Notebook string
+	// Title is the title of the linked note.
+	Title string
+	// Label is the text after '|', if any.
+	Label string
+}
+
+// Target returns the link's target as written, without its label.
+func (l Link) Target() string {
+	if l.Notebook == "" {
+		return l.Title
+	}
+	return l.Notebook + "/" + l.Title
+}
+
+// String returns the link in [[...]] syntax.
+func (l Link) String() string {
+	if l.Label == "" {
+		return "[[" + l.Target() + "]]"
+	}
+	return "[[" + l.Target() + "|" + l.Label + "]]"
+}
+
+// ParseLink parses the text between the brackets of a link, such as "work/Standup".
+// It reports false if the link has no title.
+func ParseLink(inner string) (Link, bool) {
+	var link Link
+
+	target, label, _ := strings.Cut(inner, "|")
+	link.Label = strings.TrimSpace(label)
+
+	if notebook, title, found := strings.Cut(target, "/"); found {
+		link.Notebook = strings.TrimSpace(notebook)
+		link.Title = strings.TrimSpace(title)
+	} else {
+		link.Title = strings.TrimSpace(target)
+	}
+	return link, link.Title != ""
+}
+
+// Parse returns the links in content in the order they appear.
+// Links with the same target are only returned once; targets are compared
+// with the given normalize function, so that they match like note titles.
+func Parse(content string, normalize func(string) string) []Link {
+	var links []Link
+	seen := make(map[string]bool)
+
+	for _, match := range linkPattern.FindAllStringSubmatch(content, -1) {
+		link, ok := ParseLink(match[1])
+		if !ok {
+			continue
+		}
+		key := normalize(link.Notebook) + "/" + normalize(link.Title)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		links = append(links, link)
+	}
+	return links
+}
+
+// Occurrence is a link and where it appears in content.
+type Occurrence struct {
+	Link
+	// Start and End are the byte offsets of the "[[" and just past the "]]".
+	Start, End int
+}
+
+// Find returns every link in content with its position, including repeated links.
+func Find(content string) []Occurrence {
+	var found []Occurrence
+	for _, match := range linkPattern.FindAllStringSubmatchIndex(content, -1) {
+		link, ok := ParseLink(content[match[2]:match[3]])
+		if !ok {
+			continue
+		}
+		found = append(found, Occurrence{Link: link, Start: match[0], End: match[1]})
+	}
+	return found
+}
+
+// Rewrite replaces the title of every link in content for which matches
+// returns true, keeping the link's notebook and label.
+func Rewrite(content string, matches func(Link) bool, newTitle string) string {
+	return RewriteFunc(content, func(link Link) (Link, bool) {
+		if !matches(link) {
+			return link, false
+		}
+		link.Title = newTitle
+		return link, true
+	})
+}
+
+// RewriteFunc replaces every link in content with the link rewrite returns
+// for it, unless it returns false.
+func RewriteFunc(content string, rewrite func(Link) (Link, bool)) string {
+	return linkPattern.ReplaceAllStringFunc(content, func(raw string) string {
+		link, ok := ParseLink(raw[2 : len(raw)-2])
+		if !ok {
+			return raw
+		}
+		if link, ok = rewrite(link); !ok {
+			return raw
+		}
+		return link.String()
+	})
+}
@@ -0,0 +1,56 @@
+package links
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `See [[Groceries]] and [[work/Standup|this morning]].
+Also [[groceries]] again, [[ ]] is empty and [[broken is not closed.`
+
+	got := Parse(content, strings.ToLower)
+
+	want := []Link{
+		{Title: "Groceries"},
+		{Notebook: "work", Title: "Standup", Label: "this morning"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Parse() returned %d links; want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Link %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	content := "[[Old]], [[work/Old|label]] and [[Other]]"
+
+	got := Rewrite(content, func(link Link) bool {
+		return link.Title == "Old"
+	}, "New")
+
+	if want := "[[New]], [[work/New|label]] and [[Other]]"; got != want {
+		t.Errorf("Rewrite() = %q; want %q", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	content := "[[A]] then [[work/B|b]], [[ ]] and [[A]]"
+	got := Find(content)
+	want := []Occurrence{
+		{Link: Link{Title: "A"}, Start: 0, End: 5},
+		{Link: Link{Notebook: "work", Title: "B", Label: "b"}, Start: 11, End: 23},
+		{Link: Link{Title: "A"}, Start: 35, End: 40},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Find() returned %d links; want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Occurrence %d = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package lsp
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/notefile"
+)
+
+// notePath returns the file a note is written to by go to definition:
+// <cacheDir>/<notebook>/<title>.md.
+func (s *Server) notePath(note models.Note) string {
+	return filepath.Join(s.cacheDir, filepath.FromSlash(notefile.Path(note)))
+}
+
+// writeNote writes a note's content to its file, for the editor to open.
+func (s *Server) writeNote(note models.Note) (string, error) {
+	path := s.notePath(note)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("error creating directory for note %q: %w", note.Title, err)
+	}
+	if err := os.WriteFile(path, []byte(note.Content), 0600); err != nil {
+		return "", fmt.Errorf("error writing note %q: %w", note.Title, err)
+	}
+	return path, nil
+}
+
+// pathURI returns the file:// URI of path.
+func pathURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths such as C:/notes
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// uriPath returns the file path of a file:// URI, or false for other URIs.
+func uriPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		// Windows paths such as /C:/notes
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), true
+}
@@ -0,0 +1,545 @@
+// Package lsp is a language server for notes, run by 'cli-note lsp' for
+// editors speaking the Language Server Protocol over standard input and
+// output. It supports a subset of the protocol for Markdown files:
+//
+//   - completion of [[note titles]] and #tags
+//   - go to definition on a [[link]], which writes the linked note to a
+//     file and opens it; saving that file updates the note
+//   - hover previews of linked notes
+//   - diagnostics for dangling links, to notes that don't exist
+//
+// Links in files that aren't notes are resolved in the server's default
+// notebook.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/jsonrpc"
+	"github.com/rhysmah/CLI-Note-App/links"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// Error codes defined by the Language Server Protocol.
+const (
+	codeServerNotInitialized = -32002
+	codeRequestFailed        = -32803
+)
+
+// previewLines is how many lines of a note hover shows.
+const previewLines = 20
+
+// diagnosticSource names the server in diagnostics.
+const diagnosticSource = "cli-note"
+
+// openNote is a note written to a file by go to definition.
+type openNote struct {
+	ID       string
+	Notebook string
+	Title    string
+	// Content and ETag are those of the note when the file was last
+	// written or saved, to save changes only if nobody else made any.
+	Content string
+	ETag    string
+}
+
+// Server answers Language Server Protocol requests from a Store.
+type Server struct {
+	store api.Store
+	// notebook is the notebook of links in documents that aren't notes.
+	notebook string
+	// cacheDir is where go to definition writes notes.
+	cacheDir string
+
+	out         io.Writer
+	initialized bool
+	shutdown    bool
+	// documents holds the text of open documents by URI.
+	documents map[string]string
+	// notes holds the notes written to files, by URI.
+	notes map[string]openNote
+
+	handlers map[string]func(params json.RawMessage) (any, error)
+}
+
+// NewServer returns a Server for store. Links in documents that aren't
+// notes refer to notebook; notes are written to files under cacheDir.
+func NewServer(store api.Store, notebook, cacheDir string) *Server {
+	s := &Server{
+		store:     store,
+		notebook:  notebook,
+		cacheDir:  cacheDir,
+		documents: make(map[string]string),
+		notes:     make(map[string]openNote),
+	}
+	s.handlers = map[string]func(json.RawMessage) (any, error){
+		"initialize":              s.initialize,
+		"initialized":             ignore,
+		"shutdown":                s.shutdownServer,
+		"$/cancelRequest":         ignore,
+		"$/setTrace":              ignore,
+		"textDocument/didOpen":    s.didOpen,
+		"textDocument/didChange":  s.didChange,
+		"textDocument/didSave":    s.didSave,
+		"textDocument/didClose":   s.didClose,
+		"textDocument/completion": s.completion,
+		"textDocument/definition": s.definition,
+		"textDocument/hover":      s.hover,
+	}
+	return s
+}
+
+func ignore(json.RawMessage) (any, error) {
+	return nil, nil
+}
+
+// Serve reads messages from in and writes responses and notifications to
+// out until in ends or the client sends "exit".
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	for {
+		msg, err := readMessage(reader)
+		var rpcErr *jsonrpc.Error
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case errors.As(err, &rpcErr):
+			if err := s.send(message{ID: json.RawMessage("null"), Error: rpcErr}); err != nil {
+				return err
+			}
+			continue
+		case err != nil:
+			return fmt.Errorf("error reading message: %w", err)
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+		if msg.Method == "" {
+			// A response to a request of ours; the server sends none.
+			continue
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle calls the handler of a request or notification and answers
+// requests.
+func (s *Server) handle(msg message) error {
+	result, err := s.call(msg)
+	if msg.ID == nil {
+		// Notifications can't be answered with an error, so show it
+		var rpcErr *jsonrpc.Error
+		if err != nil && !errors.As(err, &rpcErr) {
+			return s.showMessage(MessageError, "%v", err)
+		}
+		return nil
+	}
+	if err != nil {
+		var rpcErr *jsonrpc.Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &jsonrpc.Error{Code: codeRequestFailed, Message: err.Error()}
+		}
+		return s.send(message{ID: msg.ID, Error: rpcErr})
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return s.send(message{ID: msg.ID, Error: &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: fmt.Sprintf("error encoding result: %v", err)}})
+	}
+	return s.send(message{ID: msg.ID, Result: data})
+}
+
+func (s *Server) call(msg message) (any, error) {
+	switch {
+	case !s.initialized && msg.Method != "initialize":
+		return nil, &jsonrpc.Error{Code: codeServerNotInitialized, Message: "server not initialized"}
+	case s.shutdown:
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	handler, ok := s.handlers[msg.Method]
+	if !ok {
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: fmt.Sprintf("method %q not supported", msg.Method)}
+	}
+	return handler(msg.Params)
+}
+
+// send writes a message to the client.
+func (s *Server) send(msg message) error {
+	return writeMessage(s.out, msg)
+}
+
+// notify sends a notification to the client.
+func (s *Server) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", method, err)
+	}
+	return s.send(message{Method: method, Params: data})
+}
+
+// showMessage asks the client to show a message to the user.
+func (s *Server) showMessage(messageType int, format string, args ...any) error {
+	return s.notify("window/showMessage", ShowMessageParams{Type: messageType, Message: fmt.Sprintf(format, args...)})
+}
+
+func decodeParams(params json.RawMessage, v any) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	s.initialized = true
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync": map[string]any{
+				"openClose": true,
+				"change":    1, // Full: every change sends the whole text
+				"save":      map[string]any{"includeText": true},
+			},
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"[", "#"},
+			},
+			"definitionProvider": true,
+			"hoverProvider":      true,
+		},
+		"serverInfo": map[string]any{"name": "cli-note"},
+	}, nil
+}
+
+func (s *Server) shutdownServer(json.RawMessage) (any, error) {
+	s.shutdown = true
+	return nil, nil
+}
+
+// noteIndex looks up notes by notebook and title, the way links name them.
+type noteIndex struct {
+	notes   []models.Note
+	byTitle map[string]models.Note
+}
+
+func indexKey(notebook, title string) string {
+	return db.NormalizeTitle(notebook) + "/" + db.NormalizeTitle(title)
+}
+
+// index returns the notes of every notebook.
+func (s *Server) index() (noteIndex, error) {
+	notes, err := s.store.Notes("")
+	if err != nil {
+		return noteIndex{}, fmt.Errorf("error listing notes: %w", err)
+	}
+	idx := noteIndex{notes: notes, byTitle: make(map[string]models.Note, len(notes))}
+	for _, note := range notes {
+		idx.byTitle[indexKey(note.Notebook, note.Title)] = note
+	}
+	return idx, nil
+}
+
+// resolve returns the note a link points to from a document in notebook.
+func (idx noteIndex) resolve(link links.Link, notebook string) (models.Note, bool) {
+	if link.Notebook != "" {
+		notebook = link.Notebook
+	}
+	note, ok := idx.byTitle[indexKey(notebook, link.Title)]
+	return note, ok
+}
+
+// notebookOf returns the notebook links in a document refer to: the note's
+// own notebook if it is a note, otherwise the server's default notebook.
+func (s *Server) notebookOf(uri string) string {
+	if note, ok := s.notes[uri]; ok {
+		return note.Notebook
+	}
+	return s.notebook
+}
+
+func (s *Server) didOpen(params json.RawMessage) (any, error) {
+	var p DidOpenTextDocumentParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	uri := p.TextDocument.URI
+	s.documents[uri] = p.TextDocument.Text
+	if err := s.attach(uri, p.TextDocument.Text); err != nil {
+		return nil, err
+	}
+	return nil, s.publishDiagnostics(uri)
+}
+
+// attach recognizes a note file opened in the editor, so that saving it
+// updates the note. Files left from earlier sessions are only attached if
+// they still hold the note's content, so old text never overwrites newer.
+func (s *Server) attach(uri, text string) error {
+	path, ok := uriPath(uri)
+	if !ok || !strings.HasPrefix(path, filepath.Clean(s.cacheDir)+string(filepath.Separator)) {
+		return nil
+	}
+	idx, err := s.index()
+	if err != nil {
+		return err
+	}
+	for _, note := range idx.notes {
+		if s.notePath(note) != path {
+			continue
+		}
+		if note.Content != text {
+			delete(s.notes, uri)
+			return s.showMessage(MessageWarning, "%q changed since this file was written, so saving it won't update the note. Go to a link to the note to open its latest version.", note.Title)
+		}
+		s.notes[uri] = openNote{ID: note.ID, Notebook: note.Notebook, Title: note.Title, Content: note.Content, ETag: api.ETag(note)}
+		return nil
+	}
+	delete(s.notes, uri)
+	return nil
+}
+
+func (s *Server) didChange(params json.RawMessage) (any, error) {
+	var p DidChangeTextDocumentParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.ContentChanges) == 0 {
+		return nil, nil
+	}
+	uri := p.TextDocument.URI
+	s.documents[uri] = p.ContentChanges[len(p.ContentChanges)-1].Text
+	return nil, s.publishDiagnostics(uri)
+}
+
+// didSave saves the content of a note file to the note, unless the note
+// changed since the file was written.
+func (s *Server) didSave(params json.RawMessage) (any, error) {
+	var p DidSaveTextDocumentParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	uri := p.TextDocument.URI
+	if p.Text != nil {
+		s.documents[uri] = *p.Text
+	}
+	opened, ok := s.notes[uri]
+	text := s.documents[uri]
+	if !ok || text == opened.Content {
+		return nil, nil
+	}
+
+	note, err := s.store.UpdateNote(opened.Notebook, opened.ID, opened.ETag, api.NoteInput{Content: &text})
+	switch {
+	case errors.Is(err, api.ErrPreconditionFailed):
+		return nil, s.showMessage(MessageError, "%q was changed elsewhere since this file was written, so your changes weren't saved to it. Copy them, then go to a link to the note to open its latest version.", opened.Title)
+	case errors.Is(err, db.ErrNoteNotFound):
+		delete(s.notes, uri)
+		return nil, s.showMessage(MessageError, "%q no longer exists, so your changes weren't saved to it.", opened.Title)
+	case err != nil:
+		return nil, s.showMessage(MessageError, "Error saving %q: %v", opened.Title, err)
+	}
+
+	opened.Content = note.Content
+	opened.ETag = api.ETag(note)
+	s.notes[uri] = opened
+	return nil, nil
+}
+
+func (s *Server) didClose(params json.RawMessage) (any, error) {
+	var p DidCloseTextDocumentParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	delete(s.documents, p.TextDocument.URI)
+	return nil, s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+}
+
+// publishDiagnostics reports the dangling links of an open document.
+func (s *Server) publishDiagnostics(uri string) error {
+	text := s.documents[uri]
+	idx, err := s.index()
+	if err != nil {
+		return err
+	}
+
+	notebook := s.notebookOf(uri)
+	diagnostics := []Diagnostic{}
+	for _, found := range links.Find(text) {
+		if _, ok := idx.resolve(found.Link, notebook); ok {
+			continue
+		}
+		target := notebook
+		if found.Notebook != "" {
+			target = found.Notebook
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    rangeOf(text, found.Start, found.End),
+			Severity: SeverityWarning,
+			Source:   diagnosticSource,
+			Message:  fmt.Sprintf("dangling link: no note titled %q in notebook %q", found.Title, target),
+		})
+	}
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+// linkUnder returns the link at a position in an open document.
+func (s *Server) linkUnder(p TextDocumentPositionParams) (string, links.Occurrence, bool) {
+	text := s.documents[p.TextDocument.URI]
+	offset := offsetAt(text, p.Position)
+	for _, found := range links.Find(text) {
+		if found.Start <= offset && offset < found.End {
+			return text, found, true
+		}
+	}
+	return text, links.Occurrence{}, false
+}
+
+func (s *Server) completion(params json.RawMessage) (any, error) {
+	var p TextDocumentPositionParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	text := s.documents[p.TextDocument.URI]
+	offset := offsetAt(text, p.Position)
+
+	if ctx, ok := linkAt(text, offset); ok {
+		return s.completeLink(text, ctx, s.notebookOf(p.TextDocument.URI))
+	}
+	if hash, partial, ok := tagAt(text, offset); ok {
+		return s.completeTag(text, hash, offset, partial)
+	}
+	return CompletionList{Items: []CompletionItem{}}, nil
+}
+
+// completeLink offers the titles of notes for a [[link]], naming the
+// notebook of notes in other notebooks than the document's.
+func (s *Server) completeLink(text string, ctx linkContext, notebook string) (any, error) {
+	idx, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	editRange := rangeOf(text, ctx.Start, ctx.End)
+	closing := "]]"
+	if ctx.Closed {
+		closing = ""
+	}
+
+	items := []CompletionItem{}
+	for _, note := range idx.notes {
+		target := note.Title
+		if db.NormalizeTitle(note.Notebook) != db.NormalizeTitle(notebook) {
+			target = note.Notebook + "/" + note.Title
+		}
+		items = append(items, CompletionItem{
+			Label:      target,
+			Kind:       KindReference,
+			Detail:     note.Notebook,
+			FilterText: target,
+			TextEdit:   &TextEdit{Range: editRange, NewText: target + closing},
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Label) < strings.ToLower(items[j].Label)
+	})
+	return CompletionList{Items: items}, nil
+}
+
+// completeTag offers the tags in use for a #tag.
+func (s *Server) completeTag(text string, hash, offset int, partial string) (any, error) {
+	tags, err := s.store.Tags("")
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags: %w", err)
+	}
+
+	editRange := rangeOf(text, hash, offset)
+	items := []CompletionItem{}
+	for _, tag := range tags {
+		detail := "1 note"
+		if tag.Count != 1 {
+			detail = fmt.Sprintf("%d notes", tag.Count)
+		}
+		items = append(items, CompletionItem{
+			Label:      "#" + tag.Name,
+			Kind:       KindKeyword,
+			Detail:     detail,
+			FilterText: "#" + tag.Name,
+			TextEdit:   &TextEdit{Range: editRange, NewText: "#" + tag.Name},
+		})
+	}
+	return CompletionList{Items: items}, nil
+}
+
+// definition writes the note a link points to to a file and returns its
+// location, for the editor to open it. A file already open in the editor
+// is not overwritten.
+func (s *Server) definition(params json.RawMessage) (any, error) {
+	var p TextDocumentPositionParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	_, found, ok := s.linkUnder(p)
+	if !ok {
+		return nil, nil
+	}
+	idx, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+	note, ok := idx.resolve(found.Link, s.notebookOf(p.TextDocument.URI))
+	if !ok {
+		return nil, nil
+	}
+
+	uri := pathURI(s.notePath(note))
+	if _, open := s.documents[uri]; !open {
+		path, err := s.writeNote(note)
+		if err != nil {
+			return nil, err
+		}
+		uri = pathURI(path)
+		s.notes[uri] = openNote{ID: note.ID, Notebook: note.Notebook, Title: note.Title, Content: note.Content, ETag: api.ETag(note)}
+	}
+	return Location{URI: uri}, nil
+}
+
+// hover previews the note a link points to.
+func (s *Server) hover(params json.RawMessage) (any, error) {
+	var p TextDocumentPositionParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	text, found, ok := s.linkUnder(p)
+	if !ok {
+		return nil, nil
+	}
+	idx, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	var value string
+	notebook := s.notebookOf(p.TextDocument.URI)
+	if note, ok := idx.resolve(found.Link, notebook); ok {
+		value = fmt.Sprintf("**%s**\n\n*%s · modified %s*\n\n---\n\n%s",
+			note.Title, note.Notebook, note.ModifiedAt.Local().Format("2006-01-02 15:04"), preview(note.Content, previewLines))
+	} else {
+		if found.Notebook != "" {
+			notebook = found.Notebook
+		}
+		value = fmt.Sprintf("No note titled %q in notebook %q", found.Title, notebook)
+	}
+
+	linkRange := rangeOf(text, found.Start, found.End)
+	return Hover{Contents: MarkupContent{Kind: "markdown", Value: value}, Range: &linkRange}, nil
+}
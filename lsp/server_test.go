@@ -0,0 +1,252 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+)
+
+// docURI is the URI of a document that isn't a note.
+const docURI = "file:///home/me/plan.md"
+
+// newTestServer returns an initialized Server over a test database with
+// a few notes, and its store.
+func newTestServer(t *testing.T) (*Server, api.Store) {
+	t.Helper()
+	testDB, _ := testutil.SetupTestDB(t)
+	noteStore := store.New(testDB, db.DefaultNotebook)
+	for _, input := range []api.NoteInput{
+		{Title: ptr("Groceries"), Content: ptr("- milk\n- eggs"), Tags: &[]string{"home"}},
+		{Title: ptr("Great Ideas"), Tags: &[]string{"home", "work"}},
+	} {
+		if _, err := noteStore.CreateNote(input); err != nil {
+			t.Fatalf("CreateNote() error = %v", err)
+		}
+	}
+
+	server := NewServer(noteStore, db.DefaultNotebook, t.TempDir())
+	session(t, server, request(0, "initialize", map[string]any{}))
+	return server, noteStore
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// request returns a request, or a notification if id is negative.
+func request(id int, method string, params any) message {
+	data, _ := json.Marshal(params)
+	msg := message{Method: method, Params: data}
+	if id >= 0 {
+		msg.ID = json.RawMessage(strconv.Itoa(id))
+	}
+	return msg
+}
+
+func notification(method string, params any) message {
+	return request(-1, method, params)
+}
+
+// session sends messages to server and returns the messages it writes.
+func session(t *testing.T, server *Server, messages ...message) []message {
+	t.Helper()
+	var in bytes.Buffer
+	for _, msg := range messages {
+		if err := writeMessage(&in, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := server.Serve(&in, &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var written []message
+	reader := bufio.NewReader(&out)
+	for {
+		msg, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return written
+		}
+		if err != nil {
+			t.Fatalf("error reading server output: %v", err)
+		}
+		written = append(written, msg)
+	}
+}
+
+// find returns the first message written with the given method, or the
+// response to the given id.
+func find(t *testing.T, messages []message, methodOrID string) message {
+	t.Helper()
+	for _, msg := range messages {
+		if msg.Method == methodOrID || (msg.Method == "" && string(msg.ID) == methodOrID) {
+			return msg
+		}
+	}
+	t.Fatalf("no message %s in %+v", methodOrID, messages)
+	return message{}
+}
+
+func open(uri, text string) message {
+	return notification("textDocument/didOpen", DidOpenTextDocumentParams{TextDocument: TextDocumentItem{URI: uri, Text: text}})
+}
+
+func at(id int, method, uri string, line, character int) message {
+	return request(id, method, TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: line, Character: character},
+	})
+}
+
+func TestNotInitialized(t *testing.T) {
+	testDB, _ := testutil.SetupTestDB(t)
+	server := NewServer(store.New(testDB, db.DefaultNotebook), db.DefaultNotebook, t.TempDir())
+
+	written := session(t, server, at(1, "textDocument/hover", docURI, 0, 0))
+	if r := find(t, written, "1"); r.Error == nil || r.Error.Code != codeServerNotInitialized {
+		t.Errorf("response = %+v; want server not initialized error", r)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	server, _ := newTestServer(t)
+	written := session(t, server, open(docURI, "Buy [[groceries]]\nand [[Missing]] or [[work/Groceries]]"))
+
+	var params PublishDiagnosticsParams
+	json.Unmarshal(find(t, written, "textDocument/publishDiagnostics").Params, &params)
+	if len(params.Diagnostics) != 2 {
+		t.Fatalf("diagnostics = %+v; want 2 dangling links", params.Diagnostics)
+	}
+	first := params.Diagnostics[0]
+	if first.Range != (Range{Start: Position{1, 4}, End: Position{1, 15}}) || first.Severity != SeverityWarning {
+		t.Errorf("diagnostic = %+v; want a warning on [[Missing]]", first)
+	}
+	if !strings.Contains(params.Diagnostics[1].Message, `notebook "work"`) {
+		t.Errorf("message = %q; want it to name the work notebook", params.Diagnostics[1].Message)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	server, _ := newTestServer(t)
+	written := session(t, server,
+		open(docURI, "see [[Gr\ntodo #"),
+		at(1, "textDocument/completion", docURI, 0, 8),
+		at(2, "textDocument/completion", docURI, 1, 6),
+	)
+
+	var titles CompletionList
+	json.Unmarshal(find(t, written, "1").Result, &titles)
+	if len(titles.Items) != 2 || titles.Items[0].Label != "Great Ideas" || titles.Items[1].Label != "Groceries" {
+		t.Fatalf("title completions = %+v", titles.Items)
+	}
+	edit := titles.Items[1].TextEdit
+	if edit.NewText != "Groceries]]" || edit.Range != (Range{Start: Position{0, 6}, End: Position{0, 8}}) {
+		t.Errorf("edit = %+v; want Groceries]] replacing Gr", edit)
+	}
+
+	var tags CompletionList
+	json.Unmarshal(find(t, written, "2").Result, &tags)
+	if len(tags.Items) != 2 || tags.Items[0].Label != "#home" || tags.Items[0].Detail != "2 notes" {
+		t.Errorf("tag completions = %+v", tags.Items)
+	}
+}
+
+func TestHover(t *testing.T) {
+	server, _ := newTestServer(t)
+	written := session(t, server,
+		open(docURI, "[[Groceries]] and [[Nope]]"),
+		at(1, "textDocument/hover", docURI, 0, 3),
+		at(2, "textDocument/hover", docURI, 0, 20),
+		at(3, "textDocument/hover", docURI, 0, 15),
+	)
+
+	var hover Hover
+	json.Unmarshal(find(t, written, "1").Result, &hover)
+	if !strings.Contains(hover.Contents.Value, "**Groceries**") || !strings.Contains(hover.Contents.Value, "- eggs") {
+		t.Errorf("hover = %q; want the title and content", hover.Contents.Value)
+	}
+
+	json.Unmarshal(find(t, written, "2").Result, &hover)
+	if !strings.Contains(hover.Contents.Value, `No note titled "Nope"`) {
+		t.Errorf("hover = %q; want a dangling link message", hover.Contents.Value)
+	}
+
+	if got := string(find(t, written, "3").Result); got != "null" {
+		t.Errorf("hover outside links = %s; want null", got)
+	}
+}
+
+func TestDefinitionAndSave(t *testing.T) {
+	server, store := newTestServer(t)
+	written := session(t, server,
+		open(docURI, "[[Groceries]]"),
+		at(1, "textDocument/definition", docURI, 0, 4),
+	)
+
+	var location Location
+	json.Unmarshal(find(t, written, "1").Result, &location)
+	path, ok := uriPath(location.URI)
+	if !ok || path != filepath.Join(server.cacheDir, "default", "Groceries.md") {
+		t.Fatalf("location = %+v", location)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "- milk\n- eggs" {
+		t.Fatalf("file content = %q", data)
+	}
+
+	text := "- milk\n- eggs\n- bread"
+	written = session(t, server,
+		open(location.URI, "- milk\n- eggs"),
+		notification("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: location.URI}, Text: &text}),
+	)
+	for _, msg := range written {
+		if msg.Method == "window/showMessage" {
+			t.Fatalf("unexpected message: %s", msg.Params)
+		}
+	}
+	if note, _ := store.Note("", "Groceries"); note.Content != text {
+		t.Errorf("note content = %q; want %q", note.Content, text)
+	}
+
+	// A save after the note changed elsewhere doesn't overwrite it
+	if _, err := store.UpdateNote("", "Groceries", "", api.NoteInput{Content: ptr("changed")}); err != nil {
+		t.Fatal(err)
+	}
+	text = "mine"
+	written = session(t, server,
+		notification("textDocument/didSave", DidSaveTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: location.URI}, Text: &text}),
+	)
+	var shown ShowMessageParams
+	json.Unmarshal(find(t, written, "window/showMessage").Params, &shown)
+	if shown.Type != MessageError || !strings.Contains(shown.Message, "changed elsewhere") {
+		t.Errorf("message = %+v; want a conflict error", shown)
+	}
+	if note, _ := store.Note("", "Groceries"); note.Content != "changed" {
+		t.Errorf("note content = %q; want it unchanged", note.Content)
+	}
+}
+
+func TestExit(t *testing.T) {
+	server, _ := newTestServer(t)
+	written := session(t, server,
+		request(1, "shutdown", nil),
+		notification("exit", nil),
+		at(2, "textDocument/hover", docURI, 0, 0),
+	)
+	if len(written) != 1 || string(written[0].ID) != "1" {
+		t.Errorf("written = %+v; want only the shutdown response", written)
+	}
+}
@@ -0,0 +1,134 @@
+package lsp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// offsetAt returns the byte offset in text of pos, clamped to the end of
+// its line, or of text.
+func offsetAt(text string, pos Position) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+
+	for units := 0; offset < len(text) && units < pos.Character; {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == '\n' {
+			break
+		}
+		units += utf16.RuneLen(r)
+		offset += size
+	}
+	return offset
+}
+
+// positionAt returns the position of the byte offset in text.
+func positionAt(text string, offset int) Position {
+	offset = min(offset, len(text))
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+
+	var pos Position
+	pos.Line = strings.Count(text[:lineStart], "\n")
+	for _, r := range text[lineStart:offset] {
+		pos.Character += utf16.RuneLen(r)
+	}
+	return pos
+}
+
+// rangeOf returns the range between two byte offsets in text.
+func rangeOf(text string, start, end int) Range {
+	return Range{Start: positionAt(text, start), End: positionAt(text, end)}
+}
+
+// linkContext describes a [[link]] being typed at the cursor.
+type linkContext struct {
+	// Partial is the text typed so far after the "[[".
+	Partial string
+	// Start is the byte offset just after the "[[", and End the offset the
+	// completion replaces up to: the cursor, or the end of the link target
+	// if the link is already closed with "]]".
+	Start, End int
+	// Closed reports whether the link is already closed with "]]".
+	Closed bool
+}
+
+// linkAt reports whether the cursor at offset is inside the target of an
+// unfinished or existing link, and if so returns what is typed so far.
+// The cursor is not in a link target once a '|' starts the label.
+func linkAt(text string, offset int) (linkContext, bool) {
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	before := text[lineStart:offset]
+
+	open := strings.LastIndex(before, "[[")
+	if open < 0 || strings.Contains(before[open:], "]]") {
+		return linkContext{}, false
+	}
+	partial := before[open+2:]
+	if strings.ContainsAny(partial, "[]|") {
+		return linkContext{}, false
+	}
+
+	ctx := linkContext{Partial: partial, Start: lineStart + open + 2, End: offset}
+
+	lineEnd := strings.IndexByte(text[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(text) - offset
+	}
+	after := text[offset : offset+lineEnd]
+	if close := strings.Index(after, "]]"); close >= 0 && !strings.Contains(after[:close], "[[") {
+		rest := after[:close]
+		if label := strings.IndexByte(rest, '|'); label >= 0 {
+			rest = rest[:label]
+		}
+		ctx.End = offset + len(rest)
+		ctx.Closed = true
+	}
+	return ctx, true
+}
+
+// isTagRune reports whether r can be part of a #tag.
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '/'
+}
+
+// tagAt reports whether the cursor at offset is just after a #tag being
+// typed, and if so returns the byte offset of the '#' and the tag so far.
+// The '#' must start the line or follow a space, so headings don't count.
+func tagAt(text string, offset int) (int, string, bool) {
+	start := offset
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if !isTagRune(r) {
+			break
+		}
+		start -= size
+	}
+	if start == 0 || text[start-1] != '#' {
+		return 0, "", false
+	}
+	hash := start - 1
+	if hash > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:hash]); !unicode.IsSpace(r) {
+			return 0, "", false
+		}
+	}
+	return hash, text[start:offset], true
+}
+
+// preview returns the first lines of content, marking where it was cut.
+func preview(content string, lines int) string {
+	content = strings.TrimSpace(content)
+	parts := strings.SplitN(content, "\n", lines+1)
+	if len(parts) <= lines {
+		return content
+	}
+	return strings.Join(parts[:lines], "\n") + "\n\n…"
+}
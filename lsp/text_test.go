@@ -0,0 +1,75 @@
+package lsp
+
+import "testing"
+
+func TestPositions(t *testing.T) {
+	// "é" is one UTF-16 unit in two bytes, "😀" two units in four bytes
+	text := "héllo\n😀 [[x]]\n"
+	tests := []struct {
+		pos    Position
+		offset int
+	}{
+		{Position{0, 0}, 0},
+		{Position{0, 2}, 3},
+		{Position{1, 0}, 7},
+		{Position{1, 3}, 12},
+		{Position{2, 0}, 18},
+	}
+	for _, tt := range tests {
+		if got := offsetAt(text, tt.pos); got != tt.offset {
+			t.Errorf("offsetAt(%v) = %d; want %d", tt.pos, got, tt.offset)
+		}
+		if got := positionAt(text, tt.offset); got != tt.pos {
+			t.Errorf("positionAt(%d) = %v; want %v", tt.offset, got, tt.pos)
+		}
+	}
+
+	// Positions past the end of a line are clamped to it
+	if got := offsetAt(text, Position{0, 99}); got != 6 {
+		t.Errorf("offsetAt past end of line = %d; want 6", got)
+	}
+}
+
+func TestLinkAt(t *testing.T) {
+	tests := []struct {
+		text   string
+		cursor int
+		want   linkContext
+		ok     bool
+	}{
+		{"see [[Gro", 9, linkContext{Partial: "Gro", Start: 6, End: 9}, true},
+		{"see [[Gro]] now", 9, linkContext{Partial: "Gro", Start: 6, End: 9, Closed: true}, true},
+		{"see [[Gro|groceries]]", 8, linkContext{Partial: "Gr", Start: 6, End: 9, Closed: true}, true},
+		{"see [[work/", 11, linkContext{Partial: "work/", Start: 6, End: 11}, true},
+		{"see [[Gro|gr", 12, linkContext{}, false},
+		{"see [[Gro]] now", 14, linkContext{}, false},
+		{"[[A\nB", 5, linkContext{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := linkAt(tt.text, tt.cursor)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("linkAt(%q, %d) = %+v, %v; want %+v, %v", tt.text, tt.cursor, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTagAt(t *testing.T) {
+	tests := []struct {
+		text    string
+		hash    int
+		partial string
+		ok      bool
+	}{
+		{"#", 0, "", true},
+		{"ideas #wor", 6, "wor", true},
+		{"## Heading", 0, "", false},
+		{"issue#12", 0, "", false},
+		{"plain text", 0, "", false},
+	}
+	for _, tt := range tests {
+		hash, partial, ok := tagAt(tt.text, len(tt.text))
+		if hash != tt.hash || partial != tt.partial || ok != tt.ok {
+			t.Errorf("tagAt(%q) = %d, %q, %v; want %d, %q, %v", tt.text, hash, partial, ok, tt.hash, tt.partial, tt.ok)
+		}
+	}
+}
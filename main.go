@@ -4,11 +4,33 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package main
 
 import (
+	_ "github.com/rhysmah/CLI-Note-App/cmd/agenda"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/archive"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/calendar"
 	_ "github.com/rhysmah/CLI-Note-App/cmd/delete"
 	_ "github.com/rhysmah/CLI-Note-App/cmd/edit"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/export"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/git"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/graph"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/journal"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/links"
 	_ "github.com/rhysmah/CLI-Note-App/cmd/list"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/lsp"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/mirror"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/move"
 	_ "github.com/rhysmah/CLI-Note-App/cmd/new"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/notebook"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/pin"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/recur"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/rename"
 	"github.com/rhysmah/CLI-Note-App/cmd/root"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/rpc"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/search"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/serve"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/show"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/stats"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/template"
+	_ "github.com/rhysmah/CLI-Note-App/cmd/todo"
 	_ "github.com/rhysmah/CLI-Note-App/cmd/version"
 )
 
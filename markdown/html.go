@@ -0,0 +1,202 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// HTMLOptions controls how Markdown is rendered as HTML.
+type HTMLOptions struct {
+	// NoteLinkURL returns the URL of the note a [[link]] points at, or false
+	// if there is no such note. Links it can't resolve, or all links if it is
+	// nil, are rendered as text marked with the class "dangling".
+	NoteLinkURL func(link NoteLink) (string, bool)
+}
+
+// safeSchemes are the URL schemes links may use. Other schemes, such as
+// "javascript:", are dropped so exported notes can't run scripts.
+var safeSchemes = []string{"http", "https", "mailto", "ftp"}
+
+// RenderHTML renders blocks as an HTML fragment.
+func RenderHTML(blocks []Block, opts HTMLOptions) string {
+	var out strings.Builder
+	r := htmlRenderer{out: &out, opts: opts}
+	r.blocks(blocks, false)
+	return out.String()
+}
+
+type htmlRenderer struct {
+	out  *strings.Builder
+	opts HTMLOptions
+}
+
+// blocks renders blocks. Paragraphs of tight list items aren't wrapped in <p>.
+func (r htmlRenderer) blocks(blocks []Block, tight bool) {
+	for _, block := range blocks {
+		if paragraph, ok := block.(*Paragraph); ok && tight {
+			r.inlines(paragraph.Content)
+			r.out.WriteString("\n")
+			continue
+		}
+		r.block(block)
+	}
+}
+
+func (r htmlRenderer) block(block Block) {
+	switch block := block.(type) {
+	case *Heading:
+		fmt.Fprintf(r.out, "<h%d>", block.Level)
+		r.inlines(block.Content)
+		fmt.Fprintf(r.out, "</h%d>\n", block.Level)
+
+	case *Paragraph:
+		r.out.WriteString("<p>")
+		r.inlines(block.Content)
+		r.out.WriteString("</p>\n")
+
+	case *List:
+		r.list(block)
+
+	case *CodeBlock:
+		r.out.WriteString("<pre><code")
+		if block.Language != "" {
+			language, _, _ := strings.Cut(block.Language, " ")
+			fmt.Fprintf(r.out, ` class="language-%s"`, html.EscapeString(language))
+		}
+		r.out.WriteString(">")
+		r.out.WriteString(html.EscapeString(block.Code))
+		r.out.WriteString("</code></pre>\n")
+
+	case *Quote:
+		r.out.WriteString("<blockquote>\n")
+		r.blocks(block.Blocks, false)
+		r.out.WriteString("</blockquote>\n")
+
+	case *Table:
+		r.table(block)
+
+	case *Rule:
+		r.out.WriteString("<hr>\n")
+	}
+}
+
+func (r htmlRenderer) list(list *List) {
+	tag := "ul"
+	if list.Ordered {
+		tag = "ol"
+	}
+
+	r.out.WriteString("<" + tag)
+	if list.Ordered && list.Start != 1 {
+		fmt.Fprintf(r.out, ` start="%d"`, list.Start)
+	}
+	r.out.WriteString(">\n")
+
+	for _, item := range list.Items {
+		if item.Task {
+			r.out.WriteString(`<li class="task"><input type="checkbox" disabled`)
+			if item.Checked {
+				r.out.WriteString(" checked")
+			}
+			r.out.WriteString("> ")
+		} else {
+			r.out.WriteString("<li>")
+		}
+		r.blocks(item.Blocks, list.Tight)
+		r.out.WriteString("</li>\n")
+	}
+	r.out.WriteString("</" + tag + ">\n")
+}
+
+func (r htmlRenderer) table(table *Table) {
+	cell := func(tag string, column int, content []Inline) {
+		r.out.WriteString("<" + tag)
+		if column < len(table.Align) {
+			switch table.Align[column] {
+			case AlignLeft:
+				r.out.WriteString(` style="text-align: left"`)
+			case AlignCenter:
+				r.out.WriteString(` style="text-align: center"`)
+			case AlignRight:
+				r.out.WriteString(` style="text-align: right"`)
+			}
+		}
+		r.out.WriteString(">")
+		r.inlines(content)
+		r.out.WriteString("</" + tag + ">")
+	}
+
+	r.out.WriteString("<table>\n<thead>\n<tr>")
+	for i, header := range table.Header {
+		cell("th", i, header)
+	}
+	r.out.WriteString("</tr>\n</thead>\n")
+
+	if len(table.Rows) > 0 {
+		r.out.WriteString("<tbody>\n")
+		for _, row := range table.Rows {
+			r.out.WriteString("<tr>")
+			for i, content := range row {
+				cell("td", i, content)
+			}
+			r.out.WriteString("</tr>\n")
+		}
+		r.out.WriteString("</tbody>\n")
+	}
+	r.out.WriteString("</table>\n")
+}
+
+func (r htmlRenderer) inlines(inlines []Inline) {
+	for _, inline := range inlines {
+		switch inline := inline.(type) {
+		case Text:
+			r.out.WriteString(html.EscapeString(string(inline)))
+		case Code:
+			r.out.WriteString("<code>" + html.EscapeString(string(inline)) + "</code>")
+		case Emphasis:
+			r.out.WriteString("<em>")
+			r.inlines(inline)
+			r.out.WriteString("</em>")
+		case Strong:
+			r.out.WriteString("<strong>")
+			r.inlines(inline)
+			r.out.WriteString("</strong>")
+		case Link:
+			fmt.Fprintf(r.out, `<a href="%s">`, html.EscapeString(safeURL(inline.URL)))
+			r.inlines(inline.Content)
+			r.out.WriteString("</a>")
+		case NoteLink:
+			r.noteLink(inline)
+		}
+	}
+}
+
+func (r htmlRenderer) noteLink(link NoteLink) {
+	label := html.EscapeString(noteLinkLabel(link))
+	if r.opts.NoteLinkURL != nil {
+		if url, ok := r.opts.NoteLinkURL(link); ok {
+			fmt.Fprintf(r.out, `<a class="note-link" href="%s">%s</a>`, html.EscapeString(url), label)
+			return
+		}
+	}
+	fmt.Fprintf(r.out, `<span class="note-link dangling">%s</span>`, label)
+}
+
+// safeURL returns url if it is relative or uses a safe scheme, and "#"
+// otherwise. Email addresses from autolinks get a mailto: scheme.
+func safeURL(url string) string {
+	scheme, _, found := strings.Cut(url, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		if strings.Contains(url, "@") && !strings.ContainsAny(url, "/?#") {
+			return "mailto:" + url
+		}
+		return url
+	}
+	for _, safe := range safeSchemes {
+		if strings.EqualFold(scheme, safe) {
+			return url
+		}
+	}
+	return "#"
+}
@@ -0,0 +1,44 @@
+package markdown
+
+import "testing"
+
+func TestRenderHTML(t *testing.T) {
+	source := "## Plan & *scope*\n" +
+		"\n" +
+		"See [[Roadmap]], [[Missing|a missing note]] and [this](javascript:alert(1)) <a@b.io>.\n" +
+		"\n" +
+		"- [x] done\n" +
+		"- [ ] `open` <tag>\n" +
+		"\n" +
+		"| A | B |\n" +
+		"|---|:-:|\n" +
+		"| 1 | 2 |\n"
+
+	opts := HTMLOptions{
+		NoteLinkURL: func(link NoteLink) (string, bool) {
+			return "roadmap.html", link.Title == "Roadmap"
+		},
+	}
+
+	want := `<h2>Plan &amp; <em>scope</em></h2>
+<p>See <a class="note-link" href="roadmap.html">Roadmap</a>, <span class="note-link dangling">a missing note</span> and <a href="#">this</a> <a href="mailto:a@b.io">a@b.io</a>.</p>
+<ul>
+<li class="task"><input type="checkbox" disabled checked> done
+</li>
+<li class="task"><input type="checkbox" disabled> <code>open</code> &lt;tag&gt;
+</li>
+</ul>
+<table>
+<thead>
+<tr><th>A</th><th style="text-align: center">B</th></tr>
+</thead>
+<tbody>
+<tr><td>1</td><td style="text-align: center">2</td></tr>
+</tbody>
+</table>
+`
+
+	if got := RenderHTML(Parse(source), opts); got != want {
+		t.Errorf("RenderHTML() =\n%s\nwant\n%s", got, want)
+	}
+}
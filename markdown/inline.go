@@ -0,0 +1,259 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rhysmah/CLI-Note-App/links"
+)
+
+// escapable are the characters a backslash makes literal.
+const escapable = "\\`*_{}[]()#+-.!|<>~"
+
+// parseInlines parses the text of a block into inlines.
+func parseInlines(text string) []Inline {
+	var inlines []Inline
+	var plain strings.Builder
+
+	flush := func() {
+		if plain.Len() > 0 {
+			inlines = append(inlines, Text(plain.String()))
+			plain.Reset()
+		}
+	}
+	emit := func(inline Inline, next int) int {
+		flush()
+		inlines = append(inlines, inline)
+		return next
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(escapable, rest[1]) >= 0:
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			if code, width, ok := parseCodeSpan(rest); ok {
+				i = emit(code, i+width)
+				continue
+			}
+
+		case strings.HasPrefix(rest, "[["):
+			if end := strings.Index(rest, "]]"); end > 2 && !strings.ContainsAny(rest[2:end], "[]\n") {
+				if link, ok := links.ParseLink(rest[2:end]); ok {
+					i = emit(NoteLink(link), i+end+2)
+					continue
+				}
+			}
+
+		case rest[0] == '[':
+			if link, width, ok := parseLink(rest); ok {
+				i = emit(link, i+width)
+				continue
+			}
+
+		case rest[0] == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 && isAutolink(rest[1:end]) {
+				url := rest[1:end]
+				i = emit(Link{URL: url, Content: []Inline{Text(url)}}, i+end+1)
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			if inline, width, ok := parseEmphasis(text, i); ok {
+				i = emit(inline, i+width)
+				continue
+			}
+			// A run of delimiters that doesn't open emphasis is literal
+			run := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+			plain.WriteString(rest[:run])
+			i += run
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		plain.WriteString(rest[:size])
+		i += size
+	}
+
+	flush()
+	return inlines
+}
+
+// parseCodeSpan parses a code span at the start of text, returning it and
+// its width in bytes.
+func parseCodeSpan(text string) (Code, int, bool) {
+	run := len(text) - len(strings.TrimLeft(text, "`"))
+	fence := text[:run]
+
+	for offset := run; offset < len(text); {
+		end := strings.Index(text[offset:], fence)
+		if end < 0 {
+			return "", 0, false
+		}
+		end += offset
+		// The closing run must be exactly as long as the opening one
+		closing := len(text[end:]) - len(strings.TrimLeft(text[end:], "`"))
+		if closing != run {
+			offset = end + closing
+			continue
+		}
+
+		code := strings.ReplaceAll(text[run:end], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		return Code(code), end + run, true
+	}
+	return "", 0, false
+}
+
+// parseLink parses a "[label](url)" link at the start of text, returning it
+// and its width in bytes.
+func parseLink(text string) (Link, int, bool) {
+	depth := 0
+	closing := -1
+	for i := 0; i < len(text) && closing < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closing = i
+			}
+		}
+	}
+	if closing < 0 || !strings.HasPrefix(text[closing+1:], "(") {
+		return Link{}, 0, false
+	}
+
+	// The destination may contain balanced parentheses
+	end, depth := -1, 1
+	for i := closing + 2; i < len(text) && end < 0; i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				end = i - (closing + 2)
+			}
+		case '\n':
+			return Link{}, 0, false
+		}
+	}
+	if end < 0 {
+		return Link{}, 0, false
+	}
+	destination := strings.TrimSpace(text[closing+2 : closing+2+end])
+	// Ignore an optional title: [label](url "title")
+	url, _, _ := strings.Cut(destination, " ")
+	url = strings.TrimSuffix(strings.TrimPrefix(url, "<"), ">")
+
+	return Link{URL: url, Content: parseInlines(text[1:closing])}, closing + 3 + end, true
+}
+
+// isAutolink reports whether text, found between angle brackets, is a URL
+// or an email address.
+func isAutolink(text string) bool {
+	if strings.ContainsAny(text, " <\n") {
+		return false
+	}
+	scheme, rest, found := strings.Cut(text, ":")
+	if found && len(scheme) >= 2 && rest != "" && isScheme(scheme) {
+		return true
+	}
+	user, domain, found := strings.Cut(text, "@")
+	return found && user != "" && strings.Contains(domain, ".")
+}
+
+func isScheme(scheme string) bool {
+	for i, r := range scheme {
+		if !(unicode.IsLetter(r) || i > 0 && (unicode.IsDigit(r) || strings.ContainsRune("+.-", r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseEmphasis parses emphasis or strong emphasis starting at text[start],
+// returning it and its width in bytes. The opening delimiter must be followed
+// by non-space text and the closing one preceded by it. Underscores only
+// delimit emphasis at word boundaries, so snake_case stays as it is.
+func parseEmphasis(text string, start int) (Inline, int, bool) {
+	delimiter := text[start : start+1]
+	if strings.HasPrefix(text[start:], delimiter+delimiter) {
+		if inner, width, ok := findClosing(text, start, delimiter+delimiter); ok {
+			return Strong(parseInlines(inner)), width, true
+		}
+	}
+	if inner, width, ok := findClosing(text, start, delimiter); ok {
+		return Emphasis(parseInlines(inner)), width, true
+	}
+	return nil, 0, false
+}
+
+// findClosing finds the delimiter closing the one at text[start], returning
+// the text between them and the width of the whole span.
+func findClosing(text string, start int, delimiter string) (string, int, bool) {
+	open := start + len(delimiter)
+	if open >= len(text) || isSpace(text[open:]) {
+		return "", 0, false
+	}
+	if delimiter[0] == '_' && start > 0 && isWordChar(lastRune(text[:start])) {
+		return "", 0, false
+	}
+
+	for offset := open; offset < len(text); {
+		end := strings.Index(text[offset:], delimiter)
+		if end < 0 {
+			return "", 0, false
+		}
+		end += offset
+		after := end + len(delimiter)
+		if len(delimiter) == 2 {
+			// Close at the end of a longer run, so "***both***" nests emphasis
+			for after < len(text) && text[after] == delimiter[0] {
+				end, after = end+1, after+1
+			}
+		}
+
+		switch {
+		case end == open || isSpace(text[end-1:end]):
+			offset = end + 1
+		case len(delimiter) == 1 && strings.HasPrefix(text[after:], delimiter):
+			// Part of a longer run, such as the "**" closing strong emphasis
+			offset = after + 1
+		case delimiter[0] == '_' && after < len(text) && isWordChar(firstRune(text[after:])):
+			offset = after
+		default:
+			return text[open:end], after - start, true
+		}
+	}
+	return "", 0, false
+}
+
+func isSpace(text string) bool {
+	return unicode.IsSpace(firstRune(text))
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func firstRune(text string) rune {
+	r, _ := utf8.DecodeRuneInString(text)
+	return r
+}
+
+func lastRune(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return r
+}
@@ -0,0 +1,148 @@
+// Package markdown parses the Markdown used in notes into a tree of blocks
+// and inlines, which renderers turn into styled terminal text.
+//
+// It supports the common subset of Markdown: ATX headings, paragraphs,
+// emphasis, strong emphasis, code spans, links, fenced and indented code
+// blocks, block quotes, bulleted, numbered and task lists, tables,
+// thematic breaks, and [[links]] between notes.
+package markdown
+
+import "github.com/rhysmah/CLI-Note-App/links"
+
+// Block is a block-level element: one of *Heading, *Paragraph, *List,
+// *CodeBlock, *Quote, *Table or *Rule.
+type Block interface {
+	block()
+}
+
+// Inline is an element within a block: one of Text, Emphasis, Strong,
+// Code, Link or NoteLink.
+type Inline interface {
+	inline()
+}
+
+// Heading is a "# Heading" of level 1 to 6.
+type Heading struct {
+	Level   int
+	Content []Inline
+}
+
+// Paragraph is a run of text lines.
+type Paragraph struct {
+	Content []Inline
+}
+
+// List is a bulleted or numbered list.
+type List struct {
+	Ordered bool
+	// Start is the number of the first item of a numbered list.
+	Start int
+	// Tight lists have no blank lines between or within their items.
+	Tight bool
+	Items []ListItem
+}
+
+// ListItem is an item of a list. Task items start with "[ ]" or "[x]".
+type ListItem struct {
+	Task    bool
+	Checked bool
+	Blocks  []Block
+}
+
+// CodeBlock is a fenced or indented block of code.
+type CodeBlock struct {
+	// Language is the info string after the opening fence, if any.
+	Language string
+	Code     string
+}
+
+// Quote is a "> quoted" block.
+type Quote struct {
+	Blocks []Block
+}
+
+// Alignment is the alignment of a table column.
+type Alignment int
+
+const (
+	AlignDefault Alignment = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// Table is a pipe table with a header row.
+type Table struct {
+	Align  []Alignment
+	Header [][]Inline
+	Rows   [][][]Inline
+}
+
+// Rule is a thematic break, such as "---".
+type Rule struct{}
+
+func (*Heading) block()   {}
+func (*Paragraph) block() {}
+func (*List) block()      {}
+func (*CodeBlock) block() {}
+func (*Quote) block()     {}
+func (*Table) block()     {}
+func (*Rule) block()      {}
+
+// Text is plain text.
+type Text string
+
+// Emphasis is "*emphasized*" text.
+type Emphasis []Inline
+
+// Strong is "**strongly emphasized**" text.
+type Strong []Inline
+
+// Code is a "`code span`".
+type Code string
+
+// Link is a "[label](url)" link.
+type Link struct {
+	URL     string
+	Content []Inline
+}
+
+// NoteLink is a [[link]] to another note.
+type NoteLink links.Link
+
+func (Text) inline()     {}
+func (Emphasis) inline() {}
+func (Strong) inline()   {}
+func (Code) inline()     {}
+func (Link) inline()     {}
+func (NoteLink) inline() {}
+
+// PlainText returns the text of inlines without any markup.
+func PlainText(inlines []Inline) string {
+	var text []byte
+	for _, inline := range inlines {
+		switch inline := inline.(type) {
+		case Text:
+			text = append(text, inline...)
+		case Code:
+			text = append(text, inline...)
+		case Emphasis:
+			text = append(text, PlainText(inline)...)
+		case Strong:
+			text = append(text, PlainText(inline)...)
+		case Link:
+			text = append(text, PlainText(inline.Content)...)
+		case NoteLink:
+			text = append(text, noteLinkLabel(inline)...)
+		}
+	}
+	return string(text)
+}
+
+// noteLinkLabel returns the text shown for a [[link]]: its label or its target.
+func noteLinkLabel(link NoteLink) string {
+	if link.Label != "" {
+		return link.Label
+	}
+	return links.Link(link).Target()
+}
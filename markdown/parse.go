@@ -0,0 +1,323 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fencePattern     = regexp.MustCompile("^(`{3,}|~{3,})[ \t]*([^`]*)$")
+	rulePattern      = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	bulletPattern    = regexp.MustCompile(`^([-*+])([ \t]+|$)`)
+	numberPattern    = regexp.MustCompile(`^(\d{1,9})([.)])([ \t]+|$)`)
+	taskPattern      = regexp.MustCompile(`^\[([ xX])\](?:[ \t]+|$)`)
+	delimiterPattern = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?$`)
+)
+
+// Parse parses Markdown source into blocks.
+func Parse(source string) []Block {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+	return parseBlocks(strings.Split(source, "\n"))
+}
+
+// parseBlocks parses lines into blocks, one block at a time.
+func parseBlocks(lines []string) []Block {
+	var blocks []Block
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case indentation(line) >= 4:
+			block, next := parseIndentedCode(lines, i)
+			blocks, i = append(blocks, block), next
+
+		case fencePattern.MatchString(trimmed):
+			block, next := parseFencedCode(lines, i)
+			blocks, i = append(blocks, block), next
+
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, &Heading{Level: len(match[1]), Content: parseInlines(match[2])})
+			i++
+
+		case rulePattern.MatchString(trimmed):
+			blocks = append(blocks, &Rule{})
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			block, next := parseQuote(lines, i)
+			blocks, i = append(blocks, block), next
+
+		case isListItem(line):
+			block, next := parseList(lines, i)
+			if next == i {
+				// Never loop on a line parseList doesn't consume
+				block, next = parseParagraph(lines, i)
+			}
+			blocks, i = append(blocks, block), next
+
+		case isTableStart(lines, i):
+			block, next := parseTable(lines, i)
+			blocks, i = append(blocks, block), next
+
+		default:
+			block, next := parseParagraph(lines, i)
+			blocks, i = append(blocks, block), next
+		}
+	}
+	return blocks
+}
+
+// indentation returns the number of leading spaces of line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// startsBlock reports whether a line would start a block other than a
+// paragraph, which ends the paragraph before it.
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" ||
+		fencePattern.MatchString(trimmed) ||
+		headingPattern.MatchString(trimmed) ||
+		rulePattern.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">") ||
+		isListItem(line)
+}
+
+func parseParagraph(lines []string, start int) (Block, int) {
+	i := start + 1
+	for i < len(lines) && !startsBlock(lines[i]) && !isTableStart(lines, i) {
+		i++
+	}
+
+	text := make([]string, 0, i-start)
+	for _, line := range lines[start:i] {
+		text = append(text, strings.TrimSpace(line))
+	}
+	return &Paragraph{Content: parseInlines(strings.Join(text, "\n"))}, i
+}
+
+func parseIndentedCode(lines []string, start int) (Block, int) {
+	var code []string
+	i := start
+	for i < len(lines) && (indentation(lines[i]) >= 4 || strings.TrimSpace(lines[i]) == "") {
+		code = append(code, strings.TrimPrefix(lines[i], "    "))
+		i++
+	}
+	// Blank lines after the code belong to whatever follows
+	for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+		code = code[:len(code)-1]
+	}
+	return &CodeBlock{Code: strings.Join(code, "\n")}, i
+}
+
+func parseFencedCode(lines []string, start int) (Block, int) {
+	indent := indentation(lines[start])
+	match := fencePattern.FindStringSubmatch(strings.TrimSpace(lines[start]))
+	fence := match[1]
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		line = line[min(indent, indentation(line)):]
+		code = append(code, line)
+	}
+	return &CodeBlock{Language: strings.TrimSpace(match[2]), Code: strings.Join(code, "\n")}, i
+}
+
+func parseQuote(lines []string, start int) (Block, int) {
+	var quoted []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			// A lazy continuation line continues the quoted paragraph
+			if trimmed == "" || startsBlock(lines[i]) || len(quoted) == 0 || strings.TrimSpace(quoted[len(quoted)-1]) == "" {
+				break
+			}
+			quoted = append(quoted, trimmed)
+			continue
+		}
+		line := strings.TrimPrefix(trimmed, ">")
+		quoted = append(quoted, strings.TrimPrefix(line, " "))
+	}
+	return &Quote{Blocks: parseBlocks(quoted)}, i
+}
+
+// isListItem reports whether a line starts a list item.
+// A rule such as "- - -" is not a list item.
+func isListItem(line string) bool {
+	_, _, _, ok := listMarker(line)
+	return ok
+}
+
+// listMarker returns the marker of a list item line and the width of the
+// marker and the spaces after it, or ok false if line isn't a list item.
+// Only spaces may indent the marker, as the width is counted from them.
+func listMarker(line string) (marker string, number int, width int, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if rulePattern.MatchString(strings.TrimSpace(trimmed)) {
+		return "", 0, 0, false
+	}
+	if match := bulletPattern.FindStringSubmatch(trimmed); match != nil {
+		return match[1], 0, len(match[0]), true
+	}
+	if match := numberPattern.FindStringSubmatch(trimmed); match != nil {
+		number, _ := strconv.Atoi(match[1])
+		return match[2], number, len(match[0]), true
+	}
+	return "", 0, 0, false
+}
+
+func parseList(lines []string, start int) (Block, int) {
+	baseIndent := indentation(lines[start])
+	marker, number, _, _ := listMarker(lines[start])
+	list := &List{Ordered: number > 0 || marker == "." || marker == ")", Start: number}
+	loose := false
+
+	i := start
+	for i < len(lines) {
+		itemMarker, _, width, ok := listMarker(lines[i])
+		if !ok || itemMarker != marker || indentation(lines[i]) != baseIndent {
+			break
+		}
+
+		// The item's content is indented past its marker
+		contentIndent := baseIndent + width
+		itemLines := []string{lines[i][contentIndent:]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line only continues the item if indented content follows
+				if i+1 < len(lines) && indentation(lines[i+1]) >= contentIndent && strings.TrimSpace(lines[i+1]) != "" {
+					itemLines = append(itemLines, "")
+					loose = true
+					i++
+					continue
+				}
+				break
+			}
+			if indentation(line) >= contentIndent {
+				itemLines = append(itemLines, line[contentIndent:])
+			} else if indentation(line) > baseIndent || !startsBlock(line) {
+				// Lazy continuation of the item's paragraph
+				itemLines = append(itemLines, strings.TrimSpace(line))
+			} else {
+				break
+			}
+			i++
+		}
+
+		item := ListItem{}
+		if match := taskPattern.FindStringSubmatch(itemLines[0]); match != nil {
+			item.Task = true
+			item.Checked = match[1] != " "
+			itemLines[0] = itemLines[0][len(match[0]):]
+		}
+		item.Blocks = parseBlocks(itemLines)
+		list.Items = append(list.Items, item)
+
+		// Blank lines between items keep the list going
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" &&
+			i+1 < len(lines) && indentation(lines[i+1]) == baseIndent {
+			if next, _, _, ok := listMarker(lines[i+1]); !ok || next != marker {
+				break
+			}
+			loose = true
+			i++
+		}
+	}
+	list.Tight = !loose
+	return list, i
+}
+
+// isTableStart reports whether lines[i] is a table header row followed by
+// a delimiter row with as many cells.
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") {
+		return false
+	}
+	delimiter := strings.TrimSpace(lines[i+1])
+	if !delimiterPattern.MatchString(delimiter) {
+		return false
+	}
+	return len(splitRow(lines[i])) == len(splitRow(delimiter))
+}
+
+func parseTable(lines []string, start int) (Block, int) {
+	table := &Table{}
+	for _, cell := range splitRow(lines[start+1]) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			table.Align = append(table.Align, AlignCenter)
+		case right:
+			table.Align = append(table.Align, AlignRight)
+		case left:
+			table.Align = append(table.Align, AlignLeft)
+		default:
+			table.Align = append(table.Align, AlignDefault)
+		}
+	}
+
+	for _, cell := range splitRow(lines[start]) {
+		table.Header = append(table.Header, parseInlines(cell))
+	}
+
+	i := start + 2
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" || !strings.Contains(lines[i], "|") {
+			break
+		}
+		cells := splitRow(lines[i])
+		row := make([][]Inline, len(table.Header))
+		for c := range row {
+			if c < len(cells) {
+				row[c] = parseInlines(cells[c])
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, i
+}
+
+// splitRow splits a table row into its trimmed cells. Escaped pipes ("\|")
+// don't separate cells.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
@@ -0,0 +1,139 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseInlines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []Inline
+	}{
+		{
+			text: "plain text",
+			want: []Inline{Text("plain text")},
+		},
+		{
+			text: "*some* **bold** `code`",
+			want: []Inline{Emphasis{Text("some")}, Text(" "), Strong{Text("bold")}, Text(" "), Code("code")},
+		},
+		{
+			text: "***both*** and snake_case_name",
+			want: []Inline{Strong{Emphasis{Text("both")}}, Text(" and snake_case_name")},
+		},
+		{
+			text: "see [the docs](https://example.com \"Docs\") or [[work/Standup|standup]]",
+			want: []Inline{
+				Text("see "),
+				Link{URL: "https://example.com", Content: []Inline{Text("the docs")}},
+				Text(" or "),
+				NoteLink{Notebook: "work", Title: "Standup", Label: "standup"},
+			},
+		},
+		{
+			text: `2 * 3 * 4 and \*literal\* <https://go.dev>`,
+			want: []Inline{
+				Text("2 * 3 * 4 and *literal* "),
+				Link{URL: "https://go.dev", Content: []Inline{Text("https://go.dev")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parseInlines(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseInlines(%q) =\n%#v\nwant\n%#v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseBlocks(t *testing.T) {
+	source := "# Plan\n" +
+		"\n" +
+		"Some text\ncontinued.\n" +
+		"\n" +
+		"- [x] done\n" +
+		"- [ ] open\n" +
+		"  - nested\n" +
+		"\n" +
+		"3. third\n" +
+		"4. fourth\n" +
+		"\n" +
+		"```go\nfmt.Println(\"hi\")\n```\n" +
+		"> quoted\n" +
+		"\n" +
+		"| Item | Qty |\n" +
+		"|------|----:|\n" +
+		"| Eggs | 12  |\n" +
+		"\n" +
+		"---\n"
+
+	want := []Block{
+		&Heading{Level: 1, Content: []Inline{Text("Plan")}},
+		&Paragraph{Content: []Inline{Text("Some text\ncontinued.")}},
+		&List{Tight: true, Items: []ListItem{
+			{Task: true, Checked: true, Blocks: []Block{&Paragraph{Content: []Inline{Text("done")}}}},
+			{Task: true, Blocks: []Block{
+				&Paragraph{Content: []Inline{Text("open")}},
+				&List{Tight: true, Items: []ListItem{{Blocks: []Block{&Paragraph{Content: []Inline{Text("nested")}}}}}},
+			}},
+		}},
+		&List{Ordered: true, Start: 3, Tight: true, Items: []ListItem{
+			{Blocks: []Block{&Paragraph{Content: []Inline{Text("third")}}}},
+			{Blocks: []Block{&Paragraph{Content: []Inline{Text("fourth")}}}},
+		}},
+		&CodeBlock{Language: "go", Code: "fmt.Println(\"hi\")"},
+		&Quote{Blocks: []Block{&Paragraph{Content: []Inline{Text("quoted")}}}},
+		&Table{
+			Align:  []Alignment{AlignDefault, AlignRight},
+			Header: [][]Inline{{Text("Item")}, {Text("Qty")}},
+			Rows:   [][][]Inline{{{Text("Eggs")}, {Text("12")}}},
+		},
+		&Rule{},
+	}
+
+	got := Parse(source)
+	if len(got) != len(want) {
+		t.Fatalf("Parse() returned %d blocks; want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("Block %d =\n%#v\nwant\n%#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseOddlyIndentedListMarkers(t *testing.T) {
+	// Markers after whitespace other than spaces start paragraphs
+	for _, source := range []string{" - item", "　- item", "\f1. one", "\v* x", "\r-", "- item\n - item"} {
+		done := make(chan []Block)
+		go func() { done <- Parse(source) }()
+		select {
+		case blocks := <-done:
+			if len(blocks) == 0 {
+				t.Errorf("Parse(%q) returned no blocks", source)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Parse(%q) didn't return", source)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"# Plan\n\n- [x] done\n  - nested\n\n3. third\n",
+		"> quoted\nlazy\n\n| a | b |\n|---|--:|\n| 1 | 2 |\n",
+		"```go\ncode\n```\n    indented\n---\n",
+		"*em* **strong** `code` [link](https://go.dev) [[work/Note|label]]",
+		" - item",
+		"\r-",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, source string) {
+		blocks := Parse(source)
+		RenderTerminal(blocks, TerminalOptions{Width: 20})
+		RenderHTML(blocks, HTMLOptions{})
+	})
+}
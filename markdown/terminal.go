@@ -0,0 +1,303 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rhysmah/CLI-Note-App/table"
+)
+
+// Style is a kind of text the terminal renderer can style.
+type Style int
+
+const (
+	StyleHeading Style = iota
+	StyleStrong
+	StyleEmphasis
+	StyleCode
+	StyleLink
+	StyleQuote
+)
+
+// TerminalOptions controls how Markdown is rendered for a terminal.
+type TerminalOptions struct {
+	// Width is the width text is wrapped to. Text isn't wrapped if it is 0.
+	Width int
+
+	// Style decorates text, e.g. with ANSI colors. Text is left plain if it is nil.
+	Style func(style Style, text string) string
+}
+
+// ruleWidth is the width of thematic breaks when text isn't wrapped.
+const ruleWidth = 40
+
+// RenderTerminal renders blocks as text for a terminal: markup is replaced
+// by styles, lists get bullets and checkboxes, tables are aligned, and
+// paragraphs are wrapped to the configured width.
+func RenderTerminal(blocks []Block, opts TerminalOptions) string {
+	r := terminalRenderer{opts}
+	lines := r.blocks(blocks, opts.Width, false)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+type terminalRenderer struct {
+	opts TerminalOptions
+}
+
+// style applies a style to text, if styling is enabled.
+func (r terminalRenderer) style(style Style, text string) string {
+	if r.opts.Style == nil || text == "" {
+		return text
+	}
+	return r.opts.Style(style, text)
+}
+
+// blocks renders blocks into lines no wider than width, where possible.
+// Blocks are separated by blank lines, unless tight is set.
+func (r terminalRenderer) blocks(blocks []Block, width int, tight bool) []string {
+	var lines []string
+	for i, block := range blocks {
+		if i > 0 && !tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.block(block, width)...)
+	}
+	return lines
+}
+
+func (r terminalRenderer) block(block Block, width int) []string {
+	switch block := block.(type) {
+	case *Heading:
+		prefix := strings.Repeat("#", block.Level) + " "
+		content := r.wrap(flatten(block.Content, []Style{StyleHeading}), shrink(width, len(prefix)))
+		return indent(content, r.style(StyleHeading, prefix), strings.Repeat(" ", len(prefix)))
+
+	case *Paragraph:
+		return r.wrap(flatten(block.Content, nil), width)
+
+	case *List:
+		return r.list(block, width)
+
+	case *CodeBlock:
+		var lines []string
+		for _, line := range strings.Split(block.Code, "\n") {
+			lines = append(lines, "    "+r.style(StyleCode, line))
+		}
+		return lines
+
+	case *Quote:
+		bar := r.style(StyleQuote, "│")
+		return indent(r.blocks(block.Blocks, shrink(width, 2), false), bar+" ", bar+" ")
+
+	case *Table:
+		return r.table(block, width)
+
+	case *Rule:
+		if width == 0 {
+			width = ruleWidth
+		}
+		return []string{r.style(StyleQuote, strings.Repeat("─", width))}
+	}
+	return nil
+}
+
+func (r terminalRenderer) list(list *List, width int) []string {
+	markers := make([]string, len(list.Items))
+	markerWidth := 0
+	for i, item := range list.Items {
+		switch {
+		case item.Task && item.Checked:
+			markers[i] = "[x]"
+		case item.Task:
+			markers[i] = "[ ]"
+		case list.Ordered:
+			markers[i] = fmt.Sprintf("%d.", list.Start+i)
+		default:
+			markers[i] = "•"
+		}
+		markerWidth = max(markerWidth, utf8.RuneCountInString(markers[i]))
+	}
+
+	var lines []string
+	for i, item := range list.Items {
+		marker := markers[i] + strings.Repeat(" ", markerWidth-utf8.RuneCountInString(markers[i])+1)
+		if list.Ordered {
+			// Right-align numbers so their text lines up
+			marker = strings.Repeat(" ", markerWidth-utf8.RuneCountInString(markers[i])) + markers[i] + " "
+		}
+		content := r.blocks(item.Blocks, shrink(width, markerWidth+1), list.Tight)
+		if len(content) == 0 {
+			content = []string{""}
+		}
+		if i > 0 && !list.Tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, indent(content, marker, strings.Repeat(" ", markerWidth+1))...)
+	}
+	return lines
+}
+
+func (r terminalRenderer) table(block *Table, width int) []string {
+	cells := table.Table{Separator: " │ ", MaxWidth: width}
+	for i, header := range block.Header {
+		column := table.Column{Header: PlainText(header), Truncate: table.TruncateEnd}
+		if i < len(block.Align) && block.Align[i] == AlignRight {
+			column.Align = table.AlignRight
+		}
+		cells.Columns = append(cells.Columns, column)
+	}
+	for _, row := range block.Rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = PlainText(cell)
+		}
+		cells.Rows = append(cells.Rows, values)
+	}
+	cells.Style = func(row, column int, text string) string {
+		if row < 0 {
+			return r.style(StyleStrong, text)
+		}
+		return text
+	}
+
+	var out strings.Builder
+	cells.Render(&out, "─")
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+// span is a run of text with the styles it is shown in.
+type span struct {
+	text   string
+	styles []Style
+}
+
+// flatten turns inlines into spans of styled text.
+func flatten(inlines []Inline, styles []Style) []span {
+	with := func(style Style) []Style {
+		return append(append([]Style(nil), styles...), style)
+	}
+
+	var spans []span
+	for _, inline := range inlines {
+		switch inline := inline.(type) {
+		case Text:
+			spans = append(spans, span{string(inline), styles})
+		case Code:
+			spans = append(spans, span{string(inline), with(StyleCode)})
+		case Emphasis:
+			spans = append(spans, flatten(inline, with(StyleEmphasis))...)
+		case Strong:
+			spans = append(spans, flatten(inline, with(StyleStrong))...)
+		case Link:
+			spans = append(spans, flatten(inline.Content, with(StyleLink))...)
+			if inline.URL != PlainText(inline.Content) {
+				spans = append(spans, span{" (" + inline.URL + ")", styles})
+			}
+		case NoteLink:
+			spans = append(spans, span{noteLinkLabel(inline), with(StyleLink)})
+		}
+	}
+	return spans
+}
+
+// word is a run of non-space text, possibly in several styles.
+type word []span
+
+// words splits spans into words at white space.
+func words(spans []span) []word {
+	var result []word
+	var current word
+	for _, s := range spans {
+		start := -1
+		for i, r := range s.text {
+			if unicode.IsSpace(r) {
+				if start >= 0 {
+					current = append(current, span{s.text[start:i], s.styles})
+					start = -1
+				}
+				if len(current) > 0 {
+					result = append(result, current)
+					current = nil
+				}
+			} else if start < 0 {
+				start = i
+			}
+		}
+		if start >= 0 {
+			current = append(current, span{s.text[start:], s.styles})
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// wrap lays out spans in lines no wider than width, breaking at white space.
+// Words longer than width get a line of their own.
+func (r terminalRenderer) wrap(spans []span, width int) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+
+	for _, w := range words(spans) {
+		wordWidth := 0
+		for _, s := range w {
+			wordWidth += utf8.RuneCountInString(s.text)
+		}
+
+		if lineWidth > 0 && width > 0 && lineWidth+1+wordWidth > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		if lineWidth > 0 {
+			line.WriteByte(' ')
+			lineWidth++
+		}
+		for _, s := range w {
+			text := s.text
+			for _, style := range s.styles {
+				text = r.style(style, text)
+			}
+			line.WriteString(text)
+		}
+		lineWidth += wordWidth
+	}
+
+	if lineWidth > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// indent prefixes the first line with first and the others with rest.
+// Blank lines aren't indented.
+func indent(lines []string, first, rest string) []string {
+	indented := make([]string, len(lines))
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" && i > 0 {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		indented[i] = prefix + line
+	}
+	return indented
+}
+
+// shrink returns width less by, keeping 0 (no wrapping) as it is and
+// leaving room for at least a few characters.
+func shrink(width, by int) int {
+	if width == 0 {
+		return 0
+	}
+	return max(width-by, 10)
+}
@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTerminalPlain(t *testing.T) {
+	source := "## Shopping list\n" +
+		"\n" +
+		"Things to buy for the *weekend* trip, see [[Trip]].\n" +
+		"\n" +
+		"- [x] eggs\n" +
+		"- [ ] a rather long item that needs wrapping\n" +
+		"\n" +
+		"```\n" +
+		"code stays   as it is\n" +
+		"```\n"
+
+	want := strings.Join([]string{
+		"## Shopping list",
+		"",
+		"Things to buy for the",
+		"weekend trip, see Trip.",
+		"",
+		"[x] eggs",
+		"[ ] a rather long item",
+		"    that needs wrapping",
+		"",
+		"    code stays   as it is",
+		"",
+	}, "\n")
+
+	if got := RenderTerminal(Parse(source), TerminalOptions{Width: 24}); got != want {
+		t.Errorf("RenderTerminal() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTerminalStyled(t *testing.T) {
+	style := func(style Style, text string) string {
+		switch style {
+		case StyleStrong:
+			return "<b>" + text + "</b>"
+		case StyleLink:
+			return "<u>" + text + "</u>"
+		}
+		return text
+	}
+
+	source := "1. **Read** [docs](https://go.dev)\n" +
+		"\n" +
+		"2. Write\n" +
+		"\n" +
+		"| Name | Qty |\n" +
+		"| ---- | --: |\n" +
+		"| Eggs | 12 |\n"
+
+	want := strings.Join([]string{
+		"1. <b>Read</b> <u>docs</u> (https://go.dev)",
+		"",
+		"2. Write",
+		"",
+		"<b>Name</b> │ <b>Qty</b>",
+		"──────────",
+		"Eggs │  12",
+		"",
+	}, "\n")
+
+	if got := RenderTerminal(Parse(source), TerminalOptions{Style: style}); got != want {
+		t.Errorf("RenderTerminal() =\n%s\nwant\n%s", got, want)
+	}
+}
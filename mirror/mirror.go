@@ -0,0 +1,651 @@
+// Package mirror keeps a directory of Markdown files, one per note, in sync
+// with the notes, so that grep, ripgrep and any editor can work on them.
+//
+// Notes are written to <notebook>/<title>.md. A state file in the directory
+// records what every file held when it was last synced, so that a sync can
+// tell which side changed: a note whose ModifiedAt, title or notebook
+// changed is written to its file, and a file whose content changed is
+// imported into its note. When both changed, the note wins and the file's
+// content is kept in a conflict copy next to it.
+//
+// Deleting a note removes its file. A removed file is written again unless
+// the sync is told to delete the notes of missing files.
+// New .md files are created as notes: at the top of the directory in the
+// mirror's default notebook, and in a notebook's directory in that notebook.
+// Conflict copies and hidden files are never imported.
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/models"
+	"github.com/rhysmah/CLI-Note-App/notefile"
+)
+
+// StateFile is the name of the file recording the state of a mirror.
+const StateFile = ".cli-note-mirror.json"
+
+const (
+	dirPermissions  = 0700
+	filePermissions = 0600
+)
+
+var (
+	// ErrNotMirror is returned when syncing a directory that isn't a mirror.
+	ErrNotMirror = errors.New("not a mirror directory")
+	// ErrAlreadyMirror is returned when creating a mirror in a directory
+	// that already is one.
+	ErrAlreadyMirror = errors.New("directory is already a mirror")
+)
+
+// conflictPattern matches the names of conflict copies.
+var conflictPattern = regexp.MustCompile(`\.conflict-\d{8}-\d{6}(-\d+)?\.md$`)
+
+// Kind is what a sync did about a note or file.
+type Kind int
+
+const (
+	// Written means a note was written to its file.
+	Written Kind = iota
+	// Removed means a file was removed because its note was deleted.
+	Removed
+	// Imported means a note was updated from its edited file.
+	Imported
+	// Created means a note was created from a new file.
+	Created
+	// Deleted means a note was deleted because its file was removed.
+	Deleted
+	// Restored means a removed file was written again from its note,
+	// because the sync wasn't told to delete notes.
+	Restored
+	// Conflict means both the note and its file changed. The note was
+	// written to the file, and the file's content kept in Copy, if any.
+	Conflict
+	// Skipped means a file couldn't be imported; Reason says why.
+	Skipped
+)
+
+var kindNames = map[Kind]string{
+	Written:  "wrote",
+	Removed:  "removed",
+	Imported: "imported",
+	Created:  "created",
+	Deleted:  "deleted",
+	Restored: "restored",
+	Conflict: "conflict",
+	Skipped:  "skipped",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Change is one thing a sync did.
+type Change struct {
+	Kind Kind
+	// Path is the slash-separated path of the file, relative to the mirror.
+	Path string
+	// Title is the title of the note.
+	Title string
+	// Copy is the path of the conflict copy, relative to the mirror.
+	Copy string
+	// Reason explains conflicts and skipped files.
+	Reason string
+}
+
+func (c Change) String() string {
+	s := fmt.Sprintf("%-9s %s", c.Kind, c.Path)
+	if c.Reason != "" {
+		s += ": " + c.Reason
+	}
+	if c.Copy != "" {
+		s += fmt.Sprintf("; your edits are in %s", c.Copy)
+	}
+	return s
+}
+
+// state is the content of the state file.
+type state struct {
+	// Notes maps note IDs to their files.
+	Notes map[string]entry `json:"notes"`
+}
+
+// entry is a note's file as of the last sync.
+type entry struct {
+	Path       string    `json:"path"`
+	Title      string    `json:"title"`
+	Notebook   string    `json:"notebook"`
+	ModifiedAt time.Time `json:"modified_at"`
+	// Hash is the SHA-256 of the file's content. FileModTime and Size let
+	// a sync skip hashing files that weren't touched.
+	Hash        string    `json:"hash"`
+	FileModTime time.Time `json:"file_mod_time"`
+	Size        int64     `json:"size"`
+}
+
+// SyncOptions change what a sync does.
+type SyncOptions struct {
+	// DeleteMissing deletes the notes whose files were removed, instead of
+	// writing the files again.
+	DeleteMissing bool
+}
+
+// Mirror is a directory mirroring the notes of a Store.
+type Mirror struct {
+	dir   string
+	store api.Store
+	// notebook is the notebook of new files at the top of the directory.
+	notebook string
+	// now returns the time used to name conflict copies.
+	now func() time.Time
+}
+
+// New returns the mirror of store in dir. New files at the top of dir are
+// created as notes in notebook.
+func New(dir string, store api.Store, notebook string) *Mirror {
+	return &Mirror{dir: dir, store: store, notebook: notebook, now: time.Now}
+}
+
+// Init makes the directory a mirror, creating it if needed, and writes
+// every note to it. Files already in it are created as notes.
+func (m *Mirror) Init() ([]Change, error) {
+	if _, err := os.Stat(m.statePath()); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyMirror, m.dir)
+	}
+	if err := os.MkdirAll(m.dir, dirPermissions); err != nil {
+		return nil, fmt.Errorf("error creating mirror directory: %w", err)
+	}
+	if err := m.saveState(state{Notes: map[string]entry{}}); err != nil {
+		return nil, err
+	}
+	return m.Sync(SyncOptions{})
+}
+
+// Sync applies the changes made on either side since the last sync.
+func (m *Mirror) Sync(opts SyncOptions) ([]Change, error) {
+	previous, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := m.store.Notes("")
+	if err != nil {
+		return nil, fmt.Errorf("error listing notes: %w", err)
+	}
+	files, err := m.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &syncer{
+		Mirror:   m,
+		opts:     opts,
+		previous: previous,
+		next:     state{Notes: make(map[string]entry)},
+		files:    files,
+		tracked:  make(map[string]bool),
+		claimed:  make(map[string]string),
+	}
+	if err := s.run(notes); err != nil {
+		return s.changes, err
+	}
+	if err := m.saveState(s.next); err != nil {
+		return s.changes, err
+	}
+	return s.changes, nil
+}
+
+func (m *Mirror) statePath() string {
+	return filepath.Join(m.dir, StateFile)
+}
+
+// abs returns the file path of a slash-separated path relative to the mirror.
+func (m *Mirror) abs(rel string) string {
+	return filepath.Join(m.dir, filepath.FromSlash(rel))
+}
+
+func (m *Mirror) loadState() (state, error) {
+	data, err := os.ReadFile(m.statePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return state{}, fmt.Errorf("%w: %s", ErrNotMirror, m.dir)
+	}
+	if err != nil {
+		return state{}, fmt.Errorf("error reading mirror state: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state{}, fmt.Errorf("error parsing mirror state %s: %w", m.statePath(), err)
+	}
+	if st.Notes == nil {
+		st.Notes = make(map[string]entry)
+	}
+	return st, nil
+}
+
+func (m *Mirror) saveState(st state) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding mirror state: %w", err)
+	}
+	if err := os.WriteFile(m.statePath(), append(data, '\n'), filePermissions); err != nil {
+		return fmt.Errorf("error writing mirror state: %w", err)
+	}
+	return nil
+}
+
+// scan returns the note files in the mirror by relative path, leaving out
+// hidden files and conflict copies.
+func (m *Mirror) scan() (map[string]fs.FileInfo, error) {
+	files := make(map[string]fs.FileInfo)
+	err := filepath.WalkDir(m.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != m.dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || filepath.Ext(p) != notefile.Extension || conflictPattern.MatchString(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(m.dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading mirror directory: %w", err)
+	}
+	return files, nil
+}
+
+// fileState is what a note's file holds now.
+type fileState struct {
+	exists  bool
+	changed bool
+	content string
+}
+
+// syncer holds the state of one sync.
+type syncer struct {
+	*Mirror
+	opts           SyncOptions
+	previous, next state
+	// files are the note files found before the sync changed any.
+	files map[string]fs.FileInfo
+	// tracked are the paths of notes as of the last sync.
+	tracked map[string]bool
+	// claimed maps the paths given to notes in this sync to their IDs.
+	claimed map[string]string
+	// stale are the paths of notes that moved, removed at the end unless
+	// another note claimed them.
+	stale   []string
+	changes []Change
+}
+
+func (s *syncer) report(change Change) {
+	s.changes = append(s.changes, change)
+}
+
+func (s *syncer) run(notes []models.Note) error {
+	byID := make(map[string]models.Note, len(notes))
+	for _, note := range notes {
+		byID[note.ID] = note
+	}
+
+	ids := make([]string, 0, len(s.previous.Notes))
+	for id, e := range s.previous.Notes {
+		ids = append(ids, id)
+		s.tracked[e.Path] = true
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return s.previous.Notes[ids[i]].Path < s.previous.Notes[ids[j]].Path
+	})
+
+	// Read every file before writing any, and keep the paths of notes
+	// that weren't renamed or moved, so that renames can't take them.
+	current := make(map[string]fileState, len(ids))
+	for _, id := range ids {
+		e := s.previous.Notes[id]
+		fileState, err := s.inspect(e)
+		if err != nil {
+			return err
+		}
+		current[id] = fileState
+		if note, ok := byID[id]; ok && note.Title == e.Title && note.Notebook == e.Notebook {
+			s.claimed[e.Path] = id
+		}
+	}
+
+	for _, id := range ids {
+		note, exists := byID[id]
+		var err error
+		if exists {
+			err = s.syncNote(note, s.previous.Notes[id], current[id])
+		} else {
+			err = s.syncDeletedNote(s.previous.Notes[id], current[id])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notefile.Path(notes[i]) < notefile.Path(notes[j])
+	})
+	for _, note := range notes {
+		if _, tracked := s.previous.Notes[note.ID]; !tracked {
+			if err := s.syncNewNote(note); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.importNewFiles(notes); err != nil {
+		return err
+	}
+
+	for _, rel := range s.stale {
+		if _, ok := s.claimed[rel]; ok {
+			continue
+		}
+		if err := os.Remove(s.abs(rel)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error removing %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// inspect tells whether a note's file still exists and whether its content
+// changed since the last sync.
+func (s *syncer) inspect(e entry) (fileState, error) {
+	info, ok := s.files[e.Path]
+	if !ok {
+		return fileState{}, nil
+	}
+	if info.ModTime().Equal(e.FileModTime) && info.Size() == e.Size {
+		return fileState{exists: true}, nil
+	}
+	data, err := os.ReadFile(s.abs(e.Path))
+	if err != nil {
+		return fileState{}, fmt.Errorf("error reading %s: %w", e.Path, err)
+	}
+	return fileState{exists: true, changed: hash(string(data)) != e.Hash, content: string(data)}, nil
+}
+
+// syncNote syncs a note that was in the mirror and still exists.
+func (s *syncer) syncNote(note models.Note, e entry, file fileState) error {
+	noteChanged := !note.ModifiedAt.Equal(e.ModifiedAt) || note.Title != e.Title || note.Notebook != e.Notebook
+
+	switch {
+	case !file.exists && !noteChanged && !s.opts.DeleteMissing:
+		rel, err := s.writeNote(note, e.Path)
+		if err != nil {
+			return err
+		}
+		s.report(Change{Kind: Restored, Path: rel, Title: note.Title, Reason: "removed from the mirror; remove it again and sync with --delete to delete the note"})
+		return nil
+
+	case !file.exists && !noteChanged:
+		if _, err := s.store.DeleteNote(note.Notebook, note.ID, api.ETag(note)); err != nil {
+			s.next.Notes[note.ID] = e
+			s.report(Change{Kind: Skipped, Path: e.Path, Title: note.Title, Reason: fmt.Sprintf("error deleting note: %v", err)})
+			return nil
+		}
+		s.report(Change{Kind: Deleted, Path: e.Path, Title: note.Title})
+		return nil
+
+	case !file.exists:
+		rel, err := s.writeNote(note, e.Path)
+		if err != nil {
+			return err
+		}
+		s.report(Change{Kind: Conflict, Path: rel, Title: note.Title, Reason: "removed from the mirror but changed in notes, so it was restored"})
+		return nil
+
+	case !file.changed && !noteChanged:
+		// The file may have been touched; remember when, to skip hashing it
+		info := s.files[e.Path]
+		e.FileModTime, e.Size = info.ModTime(), info.Size()
+		s.next.Notes[note.ID] = e
+		return nil
+
+	case !file.changed:
+		rel, err := s.writeNote(note, e.Path)
+		if err != nil {
+			return err
+		}
+		s.report(Change{Kind: Written, Path: rel, Title: note.Title})
+		return nil
+
+	case !noteChanged:
+		updated, err := s.store.UpdateNote(note.Notebook, note.ID, api.ETag(note), api.NoteInput{Content: &file.content})
+		if err != nil {
+			s.next.Notes[note.ID] = e
+			s.report(Change{Kind: Skipped, Path: e.Path, Title: note.Title, Reason: fmt.Sprintf("error updating note: %v", err)})
+			return nil
+		}
+		if err := s.track(updated, e.Path); err != nil {
+			return err
+		}
+		s.report(Change{Kind: Imported, Path: e.Path, Title: note.Title})
+		return nil
+
+	case file.content == note.Content:
+		// Both sides made the same change
+		return s.track(note, e.Path)
+
+	default:
+		copied, err := s.conflictCopy(e.Path, file.content)
+		if err != nil {
+			return err
+		}
+		rel, err := s.writeNote(note, e.Path)
+		if err != nil {
+			return err
+		}
+		s.report(Change{Kind: Conflict, Path: rel, Title: note.Title, Copy: copied, Reason: "changed both in notes and in the mirror"})
+		return nil
+	}
+}
+
+// syncDeletedNote syncs a note that was in the mirror and was deleted.
+func (s *syncer) syncDeletedNote(e entry, file fileState) error {
+	switch {
+	case !file.exists:
+		return nil
+	case file.changed:
+		copied, err := s.conflictCopy(e.Path, file.content)
+		if err != nil {
+			return err
+		}
+		s.stale = append(s.stale, e.Path)
+		s.report(Change{Kind: Conflict, Path: e.Path, Title: e.Title, Copy: copied, Reason: "deleted from notes but changed in the mirror"})
+	default:
+		s.stale = append(s.stale, e.Path)
+		s.report(Change{Kind: Removed, Path: e.Path, Title: e.Title})
+	}
+	return nil
+}
+
+// syncNewNote writes a note that isn't in the mirror yet. A new file
+// already at its path is kept as a conflict copy unless it holds the same
+// content; the files of other notes were dealt with when syncing them.
+func (s *syncer) syncNewNote(note models.Note) error {
+	rel := s.claim(note)
+	if _, exists := s.files[rel]; exists && !s.tracked[rel] {
+		data, err := os.ReadFile(s.abs(rel))
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", rel, err)
+		}
+		if string(data) == note.Content {
+			return s.track(note, rel)
+		}
+		copied, err := s.conflictCopy(rel, string(data))
+		if err != nil {
+			return err
+		}
+		if err := s.write(note, rel); err != nil {
+			return err
+		}
+		s.report(Change{Kind: Conflict, Path: rel, Title: note.Title, Copy: copied, Reason: "a file with a different content was in the way"})
+		return nil
+	}
+
+	if err := s.write(note, rel); err != nil {
+		return err
+	}
+	s.report(Change{Kind: Written, Path: rel, Title: note.Title})
+	return nil
+}
+
+// importNewFiles creates notes from files that no note was written to.
+func (s *syncer) importNewFiles(notes []models.Note) error {
+	notebooks := map[string]string{notefile.Name(s.notebook): s.notebook}
+	for _, note := range notes {
+		notebooks[notefile.Name(note.Notebook)] = note.Notebook
+	}
+	paths := make([]string, 0, len(s.files))
+	for rel := range s.files {
+		if _, claimed := s.claimed[rel]; !claimed && !s.tracked[rel] {
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		title := strings.TrimSuffix(path.Base(rel), notefile.Extension)
+		notebook := s.notebook
+		switch dir := path.Dir(rel); {
+		case strings.Contains(dir, "/"):
+			s.report(Change{Kind: Skipped, Path: rel, Reason: "notes must be at the top of the mirror or in a notebook's directory"})
+			continue
+		case dir != ".":
+			notebook = dir
+			if name, ok := notebooks[dir]; ok {
+				notebook = name
+			}
+		}
+
+		data, err := os.ReadFile(s.abs(rel))
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", rel, err)
+		}
+		content := string(data)
+		note, err := s.store.CreateNote(api.NoteInput{Title: &title, Notebook: &notebook, Content: &content})
+		if err != nil {
+			reason := err.Error()
+			if errors.Is(err, db.ErrNotebookNotFound) {
+				reason = fmt.Sprintf("notebook %q doesn't exist; create it with 'cli-note notebook create'", notebook)
+			}
+			s.report(Change{Kind: Skipped, Path: rel, Title: title, Reason: reason})
+			continue
+		}
+		s.claimed[rel] = note.ID
+		if err := s.track(note, rel); err != nil {
+			return err
+		}
+		s.report(Change{Kind: Created, Path: rel, Title: note.Title})
+	}
+	return nil
+}
+
+// claim returns the path of a note's file, made unique with the start of
+// the note's ID if another note has the same file name.
+func (s *syncer) claim(note models.Note) string {
+	rel := notefile.Path(note)
+	if owner, ok := s.claimed[rel]; ok && owner != note.ID {
+		rel = strings.TrimSuffix(rel, notefile.Extension) + " (" + note.ID[:min(8, len(note.ID))] + ")" + notefile.Extension
+	}
+	s.claimed[rel] = note.ID
+	return rel
+}
+
+// writeNote writes a note to its file, which is previous unless the note
+// was renamed or moved, and returns the path written.
+func (s *syncer) writeNote(note models.Note, previous string) (string, error) {
+	rel := previous
+	if s.claimed[previous] != note.ID {
+		rel = s.claim(note)
+		if rel != previous {
+			s.stale = append(s.stale, previous)
+		}
+	}
+	return rel, s.write(note, rel)
+}
+
+// write writes a note's content to rel and records it in the state.
+func (s *syncer) write(note models.Note, rel string) error {
+	p := s.abs(rel)
+	if err := os.MkdirAll(filepath.Dir(p), dirPermissions); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", rel, err)
+	}
+	if err := os.WriteFile(p, []byte(note.Content), filePermissions); err != nil {
+		return fmt.Errorf("error writing %s: %w", rel, err)
+	}
+	return s.track(note, rel)
+}
+
+// track records that rel holds note as it is now.
+func (s *syncer) track(note models.Note, rel string) error {
+	info, err := os.Stat(s.abs(rel))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", rel, err)
+	}
+	s.next.Notes[note.ID] = entry{
+		Path:        rel,
+		Title:       note.Title,
+		Notebook:    note.Notebook,
+		ModifiedAt:  note.ModifiedAt,
+		Hash:        hash(note.Content),
+		FileModTime: info.ModTime(),
+		Size:        info.Size(),
+	}
+	return nil
+}
+
+// conflictCopy writes content next to rel, named after the time of the
+// sync, and returns its path.
+func (s *syncer) conflictCopy(rel, content string) (string, error) {
+	base := strings.TrimSuffix(rel, notefile.Extension) + ".conflict-" + s.now().Format("20060102-150405")
+	copied := base + notefile.Extension
+	for i := 2; ; i++ {
+		if _, err := os.Stat(s.abs(copied)); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		copied = fmt.Sprintf("%s-%d%s", base, i, notefile.Extension)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.abs(copied)), dirPermissions); err != nil {
+		return "", fmt.Errorf("error creating directory for %s: %w", copied, err)
+	}
+	if err := os.WriteFile(s.abs(copied), []byte(content), filePermissions); err != nil {
+		return "", fmt.Errorf("error writing conflict copy %s: %w", copied, err)
+	}
+	return copied, nil
+}
+
+func hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
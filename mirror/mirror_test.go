@@ -0,0 +1,218 @@
+package mirror
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/api"
+	"github.com/rhysmah/CLI-Note-App/api/store"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/testutil"
+)
+
+// newTestMirror returns a mirror in a new directory of a store with two
+// notes, after its first sync.
+func newTestMirror(t *testing.T) (*Mirror, api.Store) {
+	t.Helper()
+	testDB, _ := testutil.SetupTestDB(t)
+	noteStore := store.New(testDB, db.DefaultNotebook)
+	for _, title := range []string{"Groceries", "Ideas"} {
+		content := title + " content"
+		if _, err := noteStore.CreateNote(api.NoteInput{Title: &title, Content: &content}); err != nil {
+			t.Fatalf("CreateNote() error = %v", err)
+		}
+	}
+
+	m := New(filepath.Join(t.TempDir(), "mirror"), noteStore, db.DefaultNotebook)
+	m.now = func() time.Time { return time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC) }
+	changes, err := m.Init()
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if len(changes) != 2 || changes[0].Kind != Written || changes[0].Path != "default/Groceries.md" {
+		t.Fatalf("Init() changes = %v; want both notes written", changes)
+	}
+	return m, noteStore
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// sync syncs m and returns the changes as strings.
+func sync(t *testing.T, m *Mirror) []string {
+	t.Helper()
+	return syncWith(t, m, SyncOptions{})
+}
+
+// syncWith syncs m with opts and returns the changes as strings.
+func syncWith(t *testing.T, m *Mirror, opts SyncOptions) []string {
+	t.Helper()
+	changes, err := m.Sync(opts)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	return lines
+}
+
+// editFile writes content to a file of the mirror, with a modification time
+// that sync can't mistake for the one it recorded.
+func editFile(t *testing.T, m *Mirror, rel, content string) {
+	t.Helper()
+	path := m.abs(rel)
+	os.MkdirAll(filepath.Dir(path), 0700)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+}
+
+func readFile(t *testing.T, m *Mirror, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(m.abs(rel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestInitTwice(t *testing.T) {
+	m, _ := newTestMirror(t)
+	if _, err := m.Init(); !errors.Is(err, ErrAlreadyMirror) {
+		t.Errorf("second Init() error = %v; want ErrAlreadyMirror", err)
+	}
+	if got := sync(t, m); len(got) != 0 {
+		t.Errorf("Sync() without changes = %v", got)
+	}
+}
+
+func TestSyncNotMirror(t *testing.T) {
+	m := New(t.TempDir(), nil, db.DefaultNotebook)
+	if _, err := m.Sync(SyncOptions{}); !errors.Is(err, ErrNotMirror) {
+		t.Errorf("Sync() error = %v; want ErrNotMirror", err)
+	}
+}
+
+func TestSyncBothWays(t *testing.T) {
+	m, store := newTestMirror(t)
+
+	editFile(t, m, "default/Groceries.md", "milk")
+	if _, err := store.UpdateNote("", "Ideas", "", api.NoteInput{Title: ptr("Big Ideas"), Content: ptr("rockets")}); err != nil {
+		t.Fatal(err)
+	}
+	editFile(t, m, "Todo.md", "call mum")
+
+	got := strings.Join(sync(t, m), "\n")
+	want := strings.Join([]string{
+		"imported  default/Groceries.md",
+		"wrote     default/Big Ideas.md",
+		"created   Todo.md",
+	}, "\n")
+	if got != want {
+		t.Fatalf("Sync() changes:\n%s\nwant:\n%s", got, want)
+	}
+
+	if note, _ := store.Note("", "Groceries"); note.Content != "milk" {
+		t.Errorf("Groceries content = %q; want the file's", note.Content)
+	}
+	if content := readFile(t, m, "default/Big Ideas.md"); content != "rockets" {
+		t.Errorf("Big Ideas file = %q; want the note's content", content)
+	}
+	if _, err := os.Stat(m.abs("default/Ideas.md")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file of the renamed note still exists: %v", err)
+	}
+	if note, err := store.Note(db.DefaultNotebook, "Todo"); err != nil || note.Content != "call mum" {
+		t.Errorf("Todo note = %+v, %v; want it created from the file", note, err)
+	}
+
+	if got := sync(t, m); len(got) != 0 {
+		t.Errorf("second Sync() changes = %v; want none", got)
+	}
+}
+
+func TestSyncDeletions(t *testing.T) {
+	m, store := newTestMirror(t)
+
+	os.Remove(m.abs("default/Groceries.md"))
+	if _, err := store.DeleteNote("", "Ideas", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(syncWith(t, m, SyncOptions{DeleteMissing: true}), "\n")
+	want := "deleted   default/Groceries.md\nremoved   default/Ideas.md"
+	if got != want {
+		t.Fatalf("Sync() changes:\n%s\nwant:\n%s", got, want)
+	}
+	if _, err := store.Note("", "Groceries"); !errors.Is(err, db.ErrNoteNotFound) {
+		t.Errorf("Groceries lookup error = %v; want it deleted", err)
+	}
+	if _, err := os.Stat(m.abs("default/Ideas.md")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file of the deleted note still exists: %v", err)
+	}
+}
+
+func TestSyncRestoresRemovedFiles(t *testing.T) {
+	m, store := newTestMirror(t)
+
+	os.Remove(m.abs("default/Groceries.md"))
+	got := sync(t, m)
+	if len(got) != 1 || !strings.HasPrefix(got[0], "restored  default/Groceries.md") {
+		t.Fatalf("Sync() changes = %v; want the file restored", got)
+	}
+	if got := readFile(t, m, "default/Groceries.md"); got != "Groceries content" {
+		t.Errorf("restored file = %q; want the note's content", got)
+	}
+	if _, err := store.Note("", "Groceries"); err != nil {
+		t.Errorf("Groceries lookup error = %v; want the note kept", err)
+	}
+	if got := sync(t, m); len(got) != 0 {
+		t.Errorf("second Sync() changes = %v; want none", got)
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	m, store := newTestMirror(t)
+
+	editFile(t, m, "default/Groceries.md", "mine")
+	if _, err := store.UpdateNote("", "Groceries", "", api.NoteInput{Content: ptr("theirs")}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := m.Sync(SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Kind != Conflict || changes[0].Copy != "default/Groceries.conflict-20250301-093000.md" {
+		t.Fatalf("Sync() changes = %v; want a conflict with a copy", changes)
+	}
+	if content := readFile(t, m, "default/Groceries.md"); content != "theirs" {
+		t.Errorf("file = %q; want the note's content", content)
+	}
+	if content := readFile(t, m, changes[0].Copy); content != "mine" {
+		t.Errorf("conflict copy = %q; want the file's content", content)
+	}
+
+	// The conflict copy isn't imported as a note
+	if got := sync(t, m); len(got) != 0 {
+		t.Errorf("second Sync() changes = %v; want none", got)
+	}
+}
+
+func TestSyncSkipsUnknownNotebook(t *testing.T) {
+	m, _ := newTestMirror(t)
+	editFile(t, m, "nowhere/Plan.md", "plan")
+
+	got := sync(t, m)
+	if len(got) != 1 || !strings.Contains(got[0], `notebook "nowhere" doesn't exist`) {
+		t.Errorf("Sync() changes = %v; want the file skipped", got)
+	}
+}
@@ -4,18 +4,55 @@ import "time"
 
 // Note represents a single note entry.
 // It contains simple data: a title, content, and tags.
+// It belongs to exactly one notebook; titles are unique within a notebook.
 // It contains metadata: an identifier, creation, and modification timestamps.
+// Pinned notes are listed first; archived notes are hidden from list and search.
+// A note may have a due date, shown by the agenda.
+// EditCount is the number of times the note's content was changed.
 // The Note struct implements JSON serialization through struct tags.
 type Note struct {
-	ID         string    `json:"id"`
-	Title      string    `json:"title"`
-	Content    string    `json:"content"`
-	CreatedAt  time.Time `json:"created_at"`
-	ModifiedAt time.Time `json:"modified_at"`
-	Tags       []string  `json:"tags"`
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Notebook   string     `json:"notebook"`
+	Content    string     `json:"content"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ModifiedAt time.Time  `json:"modified_at"`
+	Tags       []string   `json:"tags"`
+	Pinned     bool       `json:"pinned"`
+	Archived   bool       `json:"archived"`
+	Due        *time.Time `json:"due,omitempty"`
+	EditCount  int        `json:"edit_count"`
 }
 
 type NoteTitle struct {
 	Title string `json:"title"`
 	ID    string `json:"id"`
 }
+
+// Notebook represents a named collection of notes.
+type Notebook struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Template is reusable content for new notes.
+// Its content is a text/template rendered when a note is created from it.
+type Template struct {
+	Name       string    `json:"name"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// Recurrence creates notes from a template on a schedule.
+// Its spec is a recurrence rule (see package recur) anchored to Start.
+// LastGenerated is the day of the last note created, or zero if none has been.
+type Recurrence struct {
+	Name          string    `json:"name"`
+	Template      string    `json:"template"`
+	Spec          string    `json:"spec"`
+	Notebook      string    `json:"notebook"`
+	Start         time.Time `json:"start"`
+	LastGenerated time.Time `json:"last_generated"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+// Package notefile names the files notes are written to outside the
+// database, such as by 'cli-note mirror' and 'cli-note lsp': one Markdown
+// file per note, in a directory per notebook.
+package notefile
+
+import (
+	"path"
+	"strings"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// Extension is the extension of note files.
+const Extension = ".md"
+
+// unsafeRunes are replaced in file names, so that any title makes a valid
+// file name on every platform.
+const unsafeRunes = `/\:*?"<>|`
+
+// Name returns a file name for a note title or notebook name, replacing
+// characters that aren't allowed in file names with '_'.
+func Name(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(unsafeRunes, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if safe == "" || safe == "." || safe == ".." {
+		return "_"
+	}
+	return safe
+}
+
+// Path returns the slash-separated path of a note's file relative to the
+// directory notes are written to: <notebook>/<title>.md.
+func Path(note models.Note) string {
+	return path.Join(Name(note.Notebook), Name(note.Title)+Extension)
+}
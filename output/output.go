@@ -0,0 +1,203 @@
+// Package output styles terminal output with ANSI colors.
+//
+// Text is styled by role, such as Header or Success, using a Theme that maps
+// roles to styles like "bold red". Colors are only used when Configure
+// enabled them; until then, and whenever they are disabled, Paint returns
+// text unchanged. Standard output and standard error are decided separately:
+// Paint styles text for the first, PaintErr for the second.
+package output
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Role is the purpose of a piece of text, which decides how it is styled.
+type Role string
+
+const (
+	Header  Role = "header"
+	Pinned  Role = "pinned"
+	Overdue Role = "overdue"
+	Tag     Role = "tag"
+	Success Role = "success"
+	Warning Role = "warning"
+	Error   Role = "error"
+
+	// Roles of rendered Markdown, used by 'show --render'.
+	Heading  Role = "heading"
+	Strong   Role = "strong"
+	Emphasis Role = "emphasis"
+	Code     Role = "code"
+	Link     Role = "link"
+	Quote    Role = "quote"
+)
+
+// Roles lists every role, in the order they are documented.
+var Roles = []Role{
+	Header, Pinned, Overdue, Tag, Success, Warning, Error,
+	Heading, Strong, Emphasis, Code, Link, Quote,
+}
+
+// Theme maps roles to styles: space-separated attributes (bold, dim,
+// italic, underline), foreground colors (red, bright-red, ...) and
+// background colors (on-red, ...). The style "none" leaves text plain.
+type Theme map[Role]string
+
+// DefaultTheme is used for roles the configured theme doesn't set.
+var DefaultTheme = Theme{
+	Header:  "bold",
+	Pinned:  "yellow",
+	Overdue: "bold red",
+	Tag:     "cyan",
+	Success: "green",
+	Warning: "yellow",
+	Error:   "bold red",
+
+	Heading:  "bold blue",
+	Strong:   "bold",
+	Emphasis: "italic",
+	Code:     "yellow",
+	Link:     "underline cyan",
+	Quote:    "dim",
+}
+
+// Mode chooses when colors are used.
+type Mode string
+
+const (
+	// ModeAuto uses colors on standard output and standard error when they
+	// are terminals and NO_COLOR isn't set.
+	ModeAuto Mode = "auto"
+	// ModeAlways always uses colors.
+	ModeAlways Mode = "always"
+	// ModeNever never uses colors.
+	ModeNever Mode = "never"
+)
+
+// ParseMode parses "auto", "always" or "never". An empty string is ModeAuto.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return ModeAuto, nil
+	case ModeAuto, ModeAlways, ModeNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid color mode %q: use auto, always or never", value)
+	}
+}
+
+// Colored reports whether Configure enabled colors on standard output.
+func Colored() bool {
+	return styles != nil
+}
+
+// Enabled reports whether colors are used in mode, given whether the output
+// is a terminal and the value of the NO_COLOR environment variable.
+func Enabled(mode Mode, isTerminal bool, noColor string) bool {
+	switch mode {
+	case ModeAlways:
+		return true
+	case ModeNever:
+		return false
+	default:
+		return isTerminal && noColor == ""
+	}
+}
+
+// styles and errStyles hold the escape sequence of every role while colors
+// are enabled on standard output and standard error. They are nil when
+// colors are disabled.
+var styles, errStyles map[Role]string
+
+// Configure enables or disables colors on standard output and standard
+// error according to mode, whether each is a terminal and NO_COLOR, and
+// sets the styles used for each role. The theme overrides DefaultTheme;
+// its keys are role names.
+func Configure(mode Mode, theme map[string]string) error {
+	return configure(mode, theme, term.IsTerminal(int(os.Stdout.Fd())), term.IsTerminal(int(os.Stderr.Fd())))
+}
+
+func configure(mode Mode, theme map[string]string, stdoutTerminal, stderrTerminal bool) error {
+	resolved, err := resolveTheme(theme)
+	if err != nil {
+		return err
+	}
+
+	styles, errStyles = nil, nil
+	noColor := os.Getenv("NO_COLOR")
+	if Enabled(mode, stdoutTerminal, noColor) {
+		styles = resolved
+	}
+	if Enabled(mode, stderrTerminal, noColor) {
+		errStyles = resolved
+	}
+	return nil
+}
+
+// resolveTheme merges theme into DefaultTheme and parses every style.
+func resolveTheme(theme map[string]string) (map[Role]string, error) {
+	specs := make(Theme, len(DefaultTheme))
+	for role, spec := range DefaultTheme {
+		specs[role] = spec
+	}
+	for name, spec := range theme {
+		role := Role(strings.ToLower(name))
+		if !slices.Contains(Roles, role) {
+			return nil, fmt.Errorf("unknown theme role %q", name)
+		}
+		specs[role] = spec
+	}
+
+	resolved := make(map[Role]string, len(specs))
+	for role, spec := range specs {
+		sequence, err := ParseStyle(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid style for %q: %w", role, err)
+		}
+		resolved[role] = sequence
+	}
+	return resolved, nil
+}
+
+// Paint styles text for role. It returns text unchanged when colors are
+// disabled or the role has no style.
+func Paint(role Role, text string) string {
+	return paint(styles, role, text)
+}
+
+// PaintErr is like Paint for text written to standard error.
+func PaintErr(role Role, text string) string {
+	return paint(errStyles, role, text)
+}
+
+func paint(styles map[Role]string, role Role, text string) string {
+	sequence := styles[role]
+	if sequence == "" || text == "" {
+		return text
+	}
+	return sequence + text + reset
+}
+
+// Sprintf formats according to a format specifier and styles the result for role.
+func Sprintf(role Role, format string, args ...any) string {
+	return Paint(role, fmt.Sprintf(format, args...))
+}
+
+// SprintfErr is like Sprintf for text written to standard error.
+func SprintfErr(role Role, format string, args ...any) string {
+	return PaintErr(role, fmt.Sprintf(format, args...))
+}
+
+// Pluralize returns a count with its noun, e.g. "1 note" or "3 notes".
+// The plural is the noun with an "s" appended.
+func Pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
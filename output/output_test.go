@@ -0,0 +1,124 @@
+package output
+
+import "testing"
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "", want: ""},
+		{spec: "none", want: ""},
+		{spec: "bold red", want: "\x1b[1;31m"},
+		{spec: "Bright-Cyan on-blue", want: "\x1b[96;44m"},
+		{spec: "underline on-bright-white", want: "\x1b[4;107m"},
+		{spec: "sparkly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStyle(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseStyle(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseStyle(%q) = %q; want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		mode       Mode
+		isTerminal bool
+		noColor    string
+		want       bool
+	}{
+		{mode: ModeAuto, isTerminal: true, want: true},
+		{mode: ModeAuto, isTerminal: false, want: false},
+		{mode: ModeAuto, isTerminal: true, noColor: "1", want: false},
+		{mode: ModeAlways, isTerminal: false, noColor: "1", want: true},
+		{mode: ModeNever, isTerminal: true, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Enabled(tt.mode, tt.isTerminal, tt.noColor); got != tt.want {
+			t.Errorf("Enabled(%q, %v, %q) = %v; want %v", tt.mode, tt.isTerminal, tt.noColor, got, tt.want)
+		}
+	}
+}
+
+func TestPaintWithTheme(t *testing.T) {
+	t.Cleanup(func() { styles, errStyles = nil, nil })
+
+	if err := Configure(ModeAlways, map[string]string{"tag": "magenta", "header": "none"}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := Paint(Tag, "work"); got != "\x1b[35mwork\x1b[0m" {
+		t.Errorf("Paint(Tag) = %q", got)
+	}
+	if got := Paint(Header, "Notes"); got != "Notes" {
+		t.Errorf("Paint(Header) = %q; want plain text", got)
+	}
+	if got := Paint(Error, "Error:"); got != "\x1b[1;31mError:\x1b[0m" {
+		t.Errorf("Paint(Error) = %q; want the default style", got)
+	}
+
+	if err := Configure(ModeNever, nil); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if got := Paint(Tag, "work"); got != "work" {
+		t.Errorf("Paint() with colors disabled = %q", got)
+	}
+
+	if err := Configure(ModeAlways, map[string]string{"sidebar": "red"}); err == nil {
+		t.Error("Expected error for an unknown role")
+	}
+}
+
+func TestPaintErrDecidedSeparately(t *testing.T) {
+	t.Cleanup(func() { styles, errStyles = nil, nil })
+	t.Setenv("NO_COLOR", "")
+
+	// Standard output piped to a file, standard error still a terminal
+	if err := configure(ModeAuto, nil, false, true); err != nil {
+		t.Fatalf("configure() error = %v", err)
+	}
+	if got := Paint(Warning, "warning"); got != "warning" {
+		t.Errorf("Paint() with standard output piped = %q; want plain text", got)
+	}
+	if got := PaintErr(Warning, "warning"); got != "\x1b[33mwarning\x1b[0m" {
+		t.Errorf("PaintErr() with standard error a terminal = %q", got)
+	}
+
+	// Standard error redirected, standard output a terminal
+	if err := configure(ModeAuto, nil, true, false); err != nil {
+		t.Fatalf("configure() error = %v", err)
+	}
+	if got := PaintErr(Error, "Error:"); got != "Error:" {
+		t.Errorf("PaintErr() with standard error redirected = %q; want plain text", got)
+	}
+	if got := Paint(Error, "Error:"); got == "Error:" {
+		t.Error("Paint() with standard output a terminal returned plain text")
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if mode, err := ParseMode(""); err != nil || mode != ModeAuto {
+		t.Errorf("ParseMode(\"\") = %q, %v; want auto", mode, err)
+	}
+	if mode, err := ParseMode("Always"); err != nil || mode != ModeAlways {
+		t.Errorf("ParseMode(\"Always\") = %q, %v; want always", mode, err)
+	}
+	if _, err := ParseMode("sometimes"); err == nil {
+		t.Error("Expected error for an unknown mode")
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	for count, want := range map[int]string{0: "0 notes", 1: "1 note", 12: "12 notes"} {
+		if got := Pluralize(count, "note"); got != want {
+			t.Errorf("Pluralize(%d, \"note\") = %q; want %q", count, got, want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const reset = "\x1b[0m"
+
+var attributes = map[string]int{
+	"bold":      1,
+	"dim":       2,
+	"italic":    3,
+	"underline": 4,
+}
+
+var colors = map[string]int{
+	"black":   0,
+	"red":     1,
+	"green":   2,
+	"yellow":  3,
+	"blue":    4,
+	"magenta": 5,
+	"cyan":    6,
+	"white":   7,
+}
+
+// ParseStyle turns a style such as "bold bright-red on-black" into an ANSI
+// escape sequence. The styles "" and "none" yield an empty sequence.
+func ParseStyle(spec string) (string, error) {
+	var codes []string
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		code, err := styleCode(word)
+		if err != nil {
+			return "", err
+		}
+		if code >= 0 {
+			codes = append(codes, strconv.Itoa(code))
+		}
+	}
+	if len(codes) == 0 {
+		return "", nil
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m", nil
+}
+
+// styleCode returns the SGR code of one word of a style, or -1 for "none".
+func styleCode(word string) (int, error) {
+	if word == "none" {
+		return -1, nil
+	}
+	if code, ok := attributes[word]; ok {
+		return code, nil
+	}
+
+	base := 30
+	if color, ok := strings.CutPrefix(word, "on-"); ok {
+		base, word = 40, color
+	}
+	if color, ok := strings.CutPrefix(word, "bright-"); ok {
+		base, word = base+60, color
+	}
+	if code, ok := colors[word]; ok {
+		return base + code, nil
+	}
+	return 0, fmt.Errorf("unknown style %q", word)
+}
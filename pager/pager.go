@@ -0,0 +1,61 @@
+// Package pager shows long text one screen at a time in the user's pager.
+package pager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Page writes text to standard output. When standard output is a terminal
+// and text is taller than it, text is shown through the user's pager instead.
+func Page(text string) error {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		_, err := fmt.Print(text)
+		return err
+	}
+	_, height, err := term.GetSize(fd)
+	if err != nil || strings.Count(text, "\n") < height {
+		_, err := fmt.Print(text)
+		return err
+	}
+
+	pagerCommand := strings.Fields(determinePager())
+	if len(pagerCommand) == 0 {
+		_, err := fmt.Print(text)
+		return err
+	}
+	if _, err := exec.LookPath(pagerCommand[0]); err != nil {
+		_, err := fmt.Print(text)
+		return err
+	}
+
+	command := exec.Command(pagerCommand[0], pagerCommand[1:]...)
+	command.Stdin = strings.NewReader(text)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("error running pager: %w", err)
+	}
+	return nil
+}
+
+// determinePager returns the pager command: $PAGER, or less (which is told
+// to pass colors through), or more.
+func determinePager() string {
+	if pager, ok := os.LookupEnv("PAGER"); ok {
+		return pager
+	}
+	if runtime.GOOS != "windows" {
+		if _, err := exec.LookPath("less"); err == nil {
+			return "less -R"
+		}
+	}
+	return "more"
+}
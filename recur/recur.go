@@ -0,0 +1,186 @@
+// Package recur parses recurrence rules and computes the days they fall on.
+//
+// A rule is either one of the shorthands "daily", "weekly", "monthly" and
+// "yearly", or a subset of the iCalendar RRULE syntax:
+//
+//	FREQ=WEEKLY;BYDAY=FR             every Friday
+//	FREQ=WEEKLY;INTERVAL=2;BYDAY=MO  every other Monday
+//	FREQ=MONTHLY;BYMONTHDAY=1        the first of every month
+//	FREQ=MONTHLY;BYMONTHDAY=-1       the last day of every month
+//
+// Rules are anchored to a start day: intervals count from it, and a rule
+// without BYDAY or BYMONTHDAY repeats on the start day's weekday or day of
+// the month.
+package recur
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/dates"
+)
+
+// Frequency is how often a rule repeats.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// byDayNames maps RRULE weekday codes to weekdays.
+var byDayNames = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Rule is a parsed recurrence rule.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Parse parses a recurrence rule.
+func Parse(spec string) (Rule, error) {
+	spec = strings.ToUpper(strings.TrimSpace(spec))
+	spec = strings.TrimPrefix(spec, "RRULE:")
+
+	switch Frequency(spec) {
+	case Daily, Weekly, Monthly, Yearly:
+		return Rule{Freq: Frequency(spec), Interval: 1}, nil
+	}
+
+	rule := Rule{Interval: 1}
+	for _, part := range strings.Split(spec, ";") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return Rule{}, fmt.Errorf("invalid rule part %q: expected KEY=VALUE", part)
+		}
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(value)
+			default:
+				return Rule{}, fmt.Errorf("unsupported FREQ %q: use DAILY, WEEKLY, MONTHLY or YEARLY", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return Rule{}, fmt.Errorf("invalid INTERVAL %q: must be a positive number", value)
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, name := range strings.Split(value, ",") {
+				weekday, ok := byDayNames[name]
+				if !ok {
+					return Rule{}, fmt.Errorf("invalid BYDAY %q: use MO, TU, WE, TH, FR, SA or SU", name)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, number := range strings.Split(value, ",") {
+				day, err := strconv.Atoi(number)
+				if err != nil || day == 0 || day < -31 || day > 31 {
+					return Rule{}, fmt.Errorf("invalid BYMONTHDAY %q: use 1 to 31, or -1 for the last day", number)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+		default:
+			return Rule{}, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("rule %q has no FREQ", spec)
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return Rule{}, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return Rule{}, fmt.Errorf("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+// Occurrences returns the days the rule falls on from day from to day until,
+// inclusive, for a rule anchored to start. Only days on or after start count.
+// The returned times are the start of each day, in start's location.
+func (r Rule) Occurrences(start, from, until time.Time) []time.Time {
+	start = dates.StartOfDay(start)
+	day := dates.StartOfDay(from.In(start.Location()))
+	if day.Before(start) {
+		day = start
+	}
+	last := dates.StartOfDay(until.In(start.Location()))
+
+	var occurrences []time.Time
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if r.matches(start, day) {
+			occurrences = append(occurrences, day)
+		}
+	}
+	return occurrences
+}
+
+// matches reports whether the rule anchored to start falls on day.
+// Both must be the start of a day in the same location.
+func (r Rule) matches(start, day time.Time) bool {
+	switch r.Freq {
+	case Daily:
+		return daysBetween(start, day)%r.Interval == 0
+
+	case Weekly:
+		weeks := daysBetween(startOfWeek(start), startOfWeek(day)) / 7
+		if weeks%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		return slices.Contains(r.ByDay, day.Weekday())
+
+	case Monthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+		if months%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		for _, monthDay := range r.ByMonthDay {
+			if monthDay == day.Day() || daysInMonth+monthDay+1 == day.Day() {
+				return true
+			}
+		}
+		return false
+
+	case Yearly:
+		years := day.Year() - start.Year()
+		return years%r.Interval == 0 && day.Month() == start.Month() && day.Day() == start.Day()
+	}
+	return false
+}
+
+// daysBetween returns the number of calendar days from a to b.
+// Dates are compared in UTC so that daylight saving changes don't matter.
+func daysBetween(a, b time.Time) int {
+	utcA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	utcB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(utcB.Sub(utcA) / dates.Day)
+}
+
+// startOfWeek returns the Monday of day's week.
+func startOfWeek(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,98 @@
+package recur
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Rule
+		wantErr bool
+	}{
+		{spec: "weekly", want: Rule{Freq: Weekly, Interval: 1}},
+		{spec: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", want: Rule{Freq: Weekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Friday}}},
+		{spec: "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1", want: Rule{Freq: Monthly, Interval: 1, ByMonthDay: []int{-1}}},
+		{spec: "FREQ=HOURLY", wantErr: true},
+		{spec: "INTERVAL=2", wantErr: true},
+		{spec: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{spec: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{spec: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{spec: "every day", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Freq != tt.want.Freq || got.Interval != tt.want.Interval ||
+				len(got.ByDay) != len(tt.want.ByDay) || len(got.ByMonthDay) != len(tt.want.ByMonthDay) {
+				t.Errorf("Parse(%q) = %+v; want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	// A Monday
+	start := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) string {
+		return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	}
+
+	tests := []struct {
+		spec  string
+		until time.Time
+		want  []string
+	}{
+		{
+			spec:  "weekly",
+			until: start.AddDate(0, 0, 14),
+			want:  []string{day(time.October, 19), day(time.October, 26), day(time.November, 2)},
+		},
+		{
+			spec:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR",
+			until: start.AddDate(0, 0, 20),
+			want:  []string{day(time.October, 23), day(time.November, 6)},
+		},
+		{
+			spec:  "FREQ=DAILY;INTERVAL=3",
+			until: start.AddDate(0, 0, 7),
+			want:  []string{day(time.October, 19), day(time.October, 22), day(time.October, 25)},
+		},
+		{
+			spec:  "FREQ=MONTHLY;BYMONTHDAY=1,-1",
+			until: time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC),
+			want:  []string{day(time.October, 31), day(time.November, 1), day(time.November, 30), day(time.December, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			rule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+			}
+
+			var got []string
+			for _, occurrence := range rule.Occurrences(start, start, tt.until) {
+				got = append(got, occurrence.Format("2006-01-02"))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v; want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Occurrences() = %v; want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+// Package site generates a static HTML site from notes: an index page, one
+// page per note with its Markdown rendered and its [[links]] resolved, and
+// one page per tag.
+//
+// Pages are rendered with html/template from a theme. The default theme is
+// built in; a theme directory can override any of its files (see ThemeFiles).
+// Templates receive an IndexPage, a NotePage or a TagPage, and layout.html
+// defines the "header", "footer", "tags" and "notes" templates they share.
+package site
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/db"
+	"github.com/rhysmah/CLI-Note-App/links"
+	"github.com/rhysmah/CLI-Note-App/markdown"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+//go:embed theme
+var defaultTheme embed.FS
+
+const (
+	notesDirectory = "notes"
+	tagsDirectory  = "tags"
+	indexFile      = "index.html"
+	styleFile      = "style.css"
+
+	directoryPermissions = 0755
+	filePermissions      = 0644
+)
+
+// ThemeFiles are the files of a theme. The .html files are templates.
+var ThemeFiles = []string{"layout.html", "index.html", "note.html", "tag.html", styleFile}
+
+// Options controls how a site is generated.
+type Options struct {
+	// Title is the title of the site, shown on every page.
+	Title string
+
+	// Sort describes the order of the notes on the index page,
+	// e.g. "TITLE (A - Z)".
+	Sort string
+
+	// ThemeDirectory is a directory whose files override the default theme.
+	ThemeDirectory string
+
+	// Dates formats the created and modified dates of notes.
+	Dates dates.Formatter
+}
+
+// Page holds what every page knows about itself.
+type Page struct {
+	// Title is the title of the page.
+	Title string
+	// Site is the title of the site.
+	Site string
+	// Root is the relative path from the page to the root of the site, e.g. "../".
+	Root string
+}
+
+// NoteInfo describes a note in lists and on its own page.
+type NoteInfo struct {
+	Title    string
+	Notebook string
+	// URL is the note's page, relative to the page it is shown on.
+	URL      string
+	Tags     []TagInfo
+	Created  string
+	Modified string
+	Pinned   bool
+}
+
+// TagInfo describes a tag.
+type TagInfo struct {
+	Name string
+	// URL is the tag's page, relative to the page it is shown on.
+	URL string
+	// Count is the number of notes with the tag.
+	Count int
+}
+
+// IndexPage is the data of index.html.
+type IndexPage struct {
+	Page
+	Sort  string
+	Notes []NoteInfo
+	Tags  []TagInfo
+}
+
+// NotePage is the data of note.html.
+type NotePage struct {
+	Page
+	Note      NoteInfo
+	Content   template.HTML
+	Backlinks []NoteInfo
+}
+
+// TagPage is the data of tag.html.
+type TagPage struct {
+	Page
+	Tag   TagInfo
+	Notes []NoteInfo
+}
+
+// site holds the notes being exported and where their pages go.
+type site struct {
+	opts      Options
+	notes     []models.Note
+	byID      map[string]models.Note
+	noteFiles map[string]string   // note ID to page path
+	titles    map[string]string   // notebook and title key to note ID
+	tagFiles  map[string]string   // tag to page path
+	tagged    map[string][]int    // tag to indexes of notes
+	backlinks map[string][]string // note ID to IDs of notes linking to it
+}
+
+// Build generates the site for notes in directory, creating it if needed and
+// overwriting existing pages. The index lists the notes in the order given.
+func Build(directory string, notes []models.Note, opts Options) error {
+	templates, style, err := loadTheme(opts.ThemeDirectory)
+	if err != nil {
+		return err
+	}
+
+	s := newSite(notes, opts)
+
+	for _, sub := range []string{notesDirectory, tagsDirectory} {
+		if err := os.MkdirAll(filepath.Join(directory, sub), directoryPermissions); err != nil {
+			return fmt.Errorf("error creating directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(directory, styleFile), style, filePermissions); err != nil {
+		return fmt.Errorf("error writing %s: %w", styleFile, err)
+	}
+
+	write := func(file, name string, data any) error {
+		var page strings.Builder
+		if err := templates.ExecuteTemplate(&page, name, data); err != nil {
+			return fmt.Errorf("error rendering %s: %w", file, err)
+		}
+		if err := os.WriteFile(filepath.Join(directory, filepath.FromSlash(file)), []byte(page.String()), filePermissions); err != nil {
+			return fmt.Errorf("error writing %s: %w", file, err)
+		}
+		return nil
+	}
+
+	if err := write(indexFile, "index.html", s.indexPage()); err != nil {
+		return err
+	}
+	for _, note := range notes {
+		if err := write(s.noteFiles[note.ID], "note.html", s.notePage(note)); err != nil {
+			return err
+		}
+	}
+	for _, tag := range s.tags() {
+		if err := write(s.tagFiles[tag], "tag.html", s.tagPage(tag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTheme parses the theme's templates and reads its style sheet.
+// Files missing from directory come from the default theme.
+func loadTheme(directory string) (*template.Template, []byte, error) {
+	templates := template.New("")
+	var style []byte
+
+	for _, name := range ThemeFiles {
+		content, err := themeFile(directory, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name == styleFile {
+			style = content
+			continue
+		}
+		if _, err := templates.New(name).Parse(string(content)); err != nil {
+			return nil, nil, fmt.Errorf("error parsing theme template %s: %w", name, err)
+		}
+	}
+	return templates, style, nil
+}
+
+// themeFile reads a theme file from directory, or from the default theme
+// if directory is empty or doesn't have the file.
+func themeFile(directory, name string) ([]byte, error) {
+	if directory != "" {
+		content, err := os.ReadFile(filepath.Join(directory, name))
+		if err == nil {
+			return content, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error reading theme file: %w", err)
+		}
+	}
+	return defaultTheme.ReadFile(path.Join("theme", name))
+}
+
+func newSite(notes []models.Note, opts Options) *site {
+	s := &site{
+		opts:      opts,
+		notes:     notes,
+		byID:      make(map[string]models.Note, len(notes)),
+		noteFiles: make(map[string]string, len(notes)),
+		titles:    make(map[string]string, len(notes)),
+		tagFiles:  make(map[string]string),
+		tagged:    make(map[string][]int),
+		backlinks: make(map[string][]string),
+	}
+
+	for i, note := range notes {
+		s.byID[note.ID] = note
+		s.noteFiles[note.ID] = path.Join(notesDirectory, note.ID+".html")
+		s.titles[titleKey(note.Notebook, note.Title)] = note.ID
+		for _, tag := range note.Tags {
+			s.tagged[tag] = append(s.tagged[tag], i)
+		}
+	}
+
+	// Tags whose names slugify alike get numbered files
+	used := make(map[string]bool)
+	for _, tag := range s.tags() {
+		slug := slugify(tag)
+		for n := 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", slugify(tag), n)
+		}
+		used[slug] = true
+		s.tagFiles[tag] = path.Join(tagsDirectory, slug+".html")
+	}
+
+	for _, note := range notes {
+		for _, link := range links.Parse(note.Content, db.NormalizeTitle) {
+			targetID, ok := s.resolve(note, markdown.NoteLink(link))
+			if ok && targetID != note.ID {
+				s.backlinks[targetID] = append(s.backlinks[targetID], note.ID)
+			}
+		}
+	}
+	return s
+}
+
+// titleKey identifies a note by its notebook and title, the way links do.
+func titleKey(notebook, title string) string {
+	return db.NormalizeTitle(notebook) + "/" + db.NormalizeTitle(title)
+}
+
+// resolve returns the ID of the exported note a link from note points at.
+func (s *site) resolve(note models.Note, link markdown.NoteLink) (string, bool) {
+	notebook := link.Notebook
+	if notebook == "" {
+		notebook = note.Notebook
+	}
+	id, ok := s.titles[titleKey(notebook, link.Title)]
+	return id, ok
+}
+
+// tags returns every tag, sorted.
+func (s *site) tags() []string {
+	tags := make([]string, 0, len(s.tagged))
+	for tag := range s.tagged {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// page returns the Page of a page stored at file.
+func (s *site) page(title, file string) Page {
+	return Page{
+		Title: title,
+		Site:  s.opts.Title,
+		Root:  strings.Repeat("../", strings.Count(file, "/")),
+	}
+}
+
+// noteInfo describes a note as seen from a page with the given root.
+func (s *site) noteInfo(note models.Note, root string) NoteInfo {
+	info := NoteInfo{
+		Title:    note.Title,
+		Notebook: note.Notebook,
+		URL:      root + s.noteFiles[note.ID],
+		Created:  s.opts.Dates.Format(note.CreatedAt),
+		Modified: s.opts.Dates.Format(note.ModifiedAt),
+		Pinned:   note.Pinned,
+	}
+	for _, tag := range note.Tags {
+		info.Tags = append(info.Tags, s.tagInfo(tag, root))
+	}
+	return info
+}
+
+// tagInfo describes a tag as seen from a page with the given root.
+func (s *site) tagInfo(tag, root string) TagInfo {
+	return TagInfo{Name: tag, URL: root + s.tagFiles[tag], Count: len(s.tagged[tag])}
+}
+
+func (s *site) indexPage() IndexPage {
+	page := IndexPage{Page: s.page(s.opts.Title, indexFile), Sort: s.opts.Sort}
+	for _, note := range s.notes {
+		page.Notes = append(page.Notes, s.noteInfo(note, page.Root))
+	}
+	for _, tag := range s.tags() {
+		page.Tags = append(page.Tags, s.tagInfo(tag, page.Root))
+	}
+	return page
+}
+
+func (s *site) notePage(note models.Note) NotePage {
+	file := s.noteFiles[note.ID]
+	page := NotePage{Page: s.page(note.Title, file)}
+	page.Note = s.noteInfo(note, page.Root)
+
+	opts := markdown.HTMLOptions{
+		NoteLinkURL: func(link markdown.NoteLink) (string, bool) {
+			targetID, ok := s.resolve(note, link)
+			if !ok {
+				return "", false
+			}
+			return page.Root + s.noteFiles[targetID], true
+		},
+	}
+	page.Content = template.HTML(markdown.RenderHTML(markdown.Parse(note.Content), opts))
+
+	for _, sourceID := range s.backlinks[note.ID] {
+		page.Backlinks = append(page.Backlinks, s.noteInfo(s.byID[sourceID], page.Root))
+	}
+	return page
+}
+
+func (s *site) tagPage(tag string) TagPage {
+	page := TagPage{Page: s.page(tag, s.tagFiles[tag])}
+	page.Tag = s.tagInfo(tag, page.Root)
+	for _, i := range s.tagged[tag] {
+		page.Notes = append(page.Notes, s.noteInfo(s.notes[i], page.Root))
+	}
+	return page
+}
+
+// slugify turns a tag into a file name: lower case letters and digits
+// separated by dashes.
+func slugify(text string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if slug.Len() == 0 {
+		return "tag"
+	}
+	return slug.String()
+}
@@ -0,0 +1,112 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+func readPage(t *testing.T, directory, file string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(directory, filepath.FromSlash(file)))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", file, err)
+	}
+	return string(content)
+}
+
+func TestBuild(t *testing.T) {
+	directory := t.TempDir()
+	notes := []models.Note{
+		{ID: "a1", Title: "Roadmap", Notebook: "work", Tags: []string{"Q3 plans"}, Content: "# Roadmap\n\nShip **it**."},
+		{ID: "b2", Title: "Standup", Notebook: "work", Tags: []string{"Q3 plans", "daily"}, Content: "See [[Roadmap]] and [[Missing]]."},
+		{ID: "c3", Title: "Groceries", Notebook: "home", Content: "Unrelated to [[work/Roadmap|the roadmap]] <script>"},
+	}
+
+	if err := Build(directory, notes, Options{Title: "My Notes", Sort: "TITLE (A - Z)"}); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	index := readPage(t, directory, "index.html")
+	for _, want := range []string{
+		`<title>My Notes</title>`,
+		`3 notes sorted by TITLE (A - Z)`,
+		`<a href="notes/b2.html">Standup</a>`,
+		`<a href="tags/q3-plans.html">Q3 plans</a> <span class="count">2</span>`,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html doesn't contain %q", want)
+		}
+	}
+	if strings.Index(index, "Roadmap") > strings.Index(index, "Groceries") {
+		t.Error("index.html should list notes in the order given")
+	}
+
+	standup := readPage(t, directory, "notes/b2.html")
+	for _, want := range []string{
+		`<link rel="stylesheet" href="../style.css">`,
+		`<a class="note-link" href="../notes/a1.html">Roadmap</a>`,
+		`<span class="note-link dangling">Missing</span>`,
+		`<a href="../tags/daily.html">daily</a>`,
+	} {
+		if !strings.Contains(standup, want) {
+			t.Errorf("notes/b2.html doesn't contain %q", want)
+		}
+	}
+
+	roadmap := readPage(t, directory, "notes/a1.html")
+	if !strings.Contains(roadmap, "<strong>it</strong>") {
+		t.Error("notes/a1.html should render Markdown")
+	}
+	for _, backlink := range []string{`href="../notes/b2.html">Standup</a>`, `href="../notes/c3.html">Groceries</a>`} {
+		if !strings.Contains(roadmap, backlink) {
+			t.Errorf("notes/a1.html should have backlink %q", backlink)
+		}
+	}
+
+	if groceries := readPage(t, directory, "notes/c3.html"); strings.Contains(groceries, "<script>") {
+		t.Error("Note content should be escaped")
+	}
+
+	tag := readPage(t, directory, "tags/q3-plans.html")
+	if !strings.Contains(tag, `<a href="../notes/a1.html">Roadmap</a>`) || strings.Contains(tag, "Groceries") {
+		t.Error("tags/q3-plans.html should list exactly the tagged notes")
+	}
+}
+
+func TestBuildWithThemeOverride(t *testing.T) {
+	theme := t.TempDir()
+	if err := os.WriteFile(filepath.Join(theme, "style.css"), []byte("body { color: red; }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	note := `{{template "header" .}}<h1 class="custom">{{.Note.Title}}</h1>{{.Content}}{{template "footer" .}}`
+	if err := os.WriteFile(filepath.Join(theme, "note.html"), []byte(note), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	directory := t.TempDir()
+	notes := []models.Note{{ID: "a1", Title: "Roadmap", Notebook: "work"}}
+	if err := Build(directory, notes, Options{Title: "Notes", ThemeDirectory: theme}); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if style := readPage(t, directory, "style.css"); style != "body { color: red; }" {
+		t.Errorf("style.css = %q; want the theme's style sheet", style)
+	}
+	if page := readPage(t, directory, "notes/a1.html"); !strings.Contains(page, `<h1 class="custom">Roadmap</h1>`) {
+		t.Errorf("notes/a1.html should use the theme's template; got\n%s", page)
+	}
+	if index := readPage(t, directory, "index.html"); !strings.Contains(index, "notes sorted by") {
+		t.Error("index.html should fall back to the default template")
+	}
+
+	if err := os.WriteFile(filepath.Join(theme, "tag.html"), []byte("{{.Broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Build(directory, notes, Options{ThemeDirectory: theme}); err == nil {
+		t.Error("Expected error for an invalid theme template")
+	}
+}
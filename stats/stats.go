@@ -0,0 +1,165 @@
+// Package stats computes statistics about notes.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rhysmah/CLI-Note-App/dates"
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+// WordsPerMinute is the reading speed used to estimate reading time.
+const WordsPerMinute = 200
+
+// Words returns the number of whitespace-separated words in content.
+func Words(content string) int {
+	return len(strings.Fields(content))
+}
+
+// Characters returns the number of characters (runes) in content.
+func Characters(content string) int {
+	return utf8.RuneCountInString(content)
+}
+
+// Lines returns the number of lines in content. Empty content has no lines,
+// and a trailing newline doesn't start a new line.
+func Lines(content string) int {
+	if content == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+}
+
+// ReadingTime estimates how long content takes to read, rounded up to
+// the minute. Content with no words takes no time.
+func ReadingTime(content string) time.Duration {
+	words := Words(content)
+	if words == 0 {
+		return 0
+	}
+	minutes := math.Ceil(float64(words) / WordsPerMinute)
+	return time.Duration(minutes) * time.Minute
+}
+
+// Count is a named count, such as the number of notes with a tag.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// Week is the number of notes created and modified in a week.
+type Week struct {
+	// Start is the Monday the week starts on.
+	Start    time.Time
+	Created  int
+	Modified int
+}
+
+// Summary holds statistics about a set of notes.
+type Summary struct {
+	Notes      int
+	Words      int
+	Characters int
+	Weeks      []Week
+	TopTags    []Count
+	Largest    []Count
+}
+
+// AverageWords returns the average number of words per note.
+func (s Summary) AverageWords() float64 {
+	if s.Notes == 0 {
+		return 0
+	}
+	return float64(s.Words) / float64(s.Notes)
+}
+
+// AverageCharacters returns the average number of characters per note.
+func (s Summary) AverageCharacters() float64 {
+	if s.Notes == 0 {
+		return 0
+	}
+	return float64(s.Characters) / float64(s.Notes)
+}
+
+// Summarize computes statistics about notes. Activity is counted for the
+// given number of weeks up to and including the week of now, and the top
+// tags and largest notes (by words) are limited to top entries each.
+func Summarize(notes []models.Note, now time.Time, weeks, top int) Summary {
+	summary := Summary{Notes: len(notes)}
+
+	thisWeek := startOfWeek(now)
+	for i := weeks - 1; i >= 0; i-- {
+		summary.Weeks = append(summary.Weeks, Week{Start: thisWeek.AddDate(0, 0, -7*i)})
+	}
+	weekIndex := func(t time.Time) int {
+		days := int(thisWeek.Sub(startOfWeek(t.In(now.Location()))).Round(dates.Day) / dates.Day)
+		if days < 0 {
+			return -1
+		}
+		return weeks - 1 - days/7
+	}
+
+	tags := make(map[string]int)
+	var sizes []Count
+	for _, note := range notes {
+		words := Words(note.Content)
+		summary.Words += words
+		summary.Characters += Characters(note.Content)
+		sizes = append(sizes, Count{Name: note.Title, Count: words})
+
+		if i := weekIndex(note.CreatedAt); i >= 0 {
+			summary.Weeks[i].Created++
+		}
+		if i := weekIndex(note.ModifiedAt); i >= 0 {
+			summary.Weeks[i].Modified++
+		}
+		for _, tag := range note.Tags {
+			tags[tag]++
+		}
+	}
+
+	for tag, count := range tags {
+		summary.TopTags = append(summary.TopTags, Count{Name: tag, Count: count})
+	}
+	summary.TopTags = topCounts(summary.TopTags, top)
+	summary.Largest = topCounts(sizes, top)
+	return summary
+}
+
+// topCounts returns the n largest counts, largest first; ties are ordered by name.
+func topCounts(counts []Count, n int) []Count {
+	sort.Slice(counts, func(a, b int) bool {
+		if counts[a].Count != counts[b].Count {
+			return counts[a].Count > counts[b].Count
+		}
+		return counts[a].Name < counts[b].Name
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// startOfWeek returns midnight on the Monday of t's week.
+func startOfWeek(t time.Time) time.Time {
+	day := dates.StartOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// Bar returns an ASCII bar of '#' for value, scaled so that max fills width.
+// Non-zero values always get at least one '#'.
+func Bar(value, max, width int) string {
+	if value <= 0 || max <= 0 {
+		return ""
+	}
+	length := value * width / max
+	if length == 0 {
+		length = 1
+	}
+	return strings.Repeat("#", length)
+}
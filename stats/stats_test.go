@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rhysmah/CLI-Note-App/models"
+)
+
+func TestCounts(t *testing.T) {
+	tests := []struct {
+		content   string
+		words     int
+		chars     int
+		lines     int
+		readingIn time.Duration
+	}{
+		{content: "", words: 0, chars: 0, lines: 0, readingIn: 0},
+		{content: "héllo world\n", words: 2, chars: 12, lines: 1, readingIn: time.Minute},
+		{content: "one\ntwo\n\nthree", words: 3, chars: 14, lines: 4, readingIn: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Words(tt.content); got != tt.words {
+			t.Errorf("Words(%q) = %d; want %d", tt.content, got, tt.words)
+		}
+		if got := Characters(tt.content); got != tt.chars {
+			t.Errorf("Characters(%q) = %d; want %d", tt.content, got, tt.chars)
+		}
+		if got := Lines(tt.content); got != tt.lines {
+			t.Errorf("Lines(%q) = %d; want %d", tt.content, got, tt.lines)
+		}
+		if got := ReadingTime(tt.content); got != tt.readingIn {
+			t.Errorf("ReadingTime(%q) = %v; want %v", tt.content, got, tt.readingIn)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, time.October, 21, 12, 0, 0, 0, time.UTC)
+	notes := []models.Note{
+		{Title: "a", Content: "one two three", CreatedAt: now, ModifiedAt: now, Tags: []string{"work", "idea"}},
+		{Title: "b", Content: "one", CreatedAt: now.AddDate(0, 0, -7), ModifiedAt: now, Tags: []string{"work"}},
+		{Title: "c", Content: "", CreatedAt: now.AddDate(0, -6, 0), ModifiedAt: now.AddDate(0, -6, 0)},
+	}
+
+	summary := Summarize(notes, now, 4, 1)
+
+	if summary.Notes != 3 || summary.Words != 4 {
+		t.Errorf("Notes, words = %d, %d; want 3, 4", summary.Notes, summary.Words)
+	}
+	if len(summary.Weeks) != 4 {
+		t.Fatalf("Got %d weeks; want 4", len(summary.Weeks))
+	}
+	thisWeek, lastWeek := summary.Weeks[3], summary.Weeks[2]
+	if thisWeek.Start.Weekday() != time.Monday || thisWeek.Created != 1 || thisWeek.Modified != 2 {
+		t.Errorf("This week = %+v; want Monday start, 1 created, 2 modified", thisWeek)
+	}
+	if lastWeek.Created != 1 || lastWeek.Modified != 0 {
+		t.Errorf("Last week = %+v; want 1 created, 0 modified", lastWeek)
+	}
+	if len(summary.TopTags) != 1 || summary.TopTags[0] != (Count{Name: "work", Count: 2}) {
+		t.Errorf("Top tags = %v; want [work 2]", summary.TopTags)
+	}
+	if len(summary.Largest) != 1 || summary.Largest[0].Name != "a" {
+		t.Errorf("Largest = %v; want [a]", summary.Largest)
+	}
+}
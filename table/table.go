@@ -0,0 +1,201 @@
+// Package table lays out rows of text in aligned columns, shrinking and
+// truncating columns so the table fits a maximum width.
+package table
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Ellipsis marks where text was cut from a truncated cell.
+const Ellipsis = "…"
+
+// Align is the horizontal alignment of the text in a column.
+type Align int
+
+const (
+	// AlignLeft pads cells on the right.
+	AlignLeft Align = iota
+	// AlignRight pads cells on the left.
+	AlignRight
+)
+
+// Truncate is how a column's cells are shortened when the table is too wide.
+type Truncate int
+
+const (
+	// TruncateNone never shortens the column.
+	TruncateNone Truncate = iota
+	// TruncateEnd keeps the start of the text: "a long ti…".
+	TruncateEnd
+	// TruncateMiddle keeps the start and the end of the text: "a lon…itle".
+	TruncateMiddle
+)
+
+// Column describes one column of a table.
+type Column struct {
+	Header   string
+	Align    Align
+	Truncate Truncate
+}
+
+// Table is a list of columns and the rows of cells to show in them.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+
+	// Separator is printed between columns.
+	Separator string
+
+	// MaxWidth is the width the table must fit in, if it is positive.
+	// Columns that can be truncated are shrunk, widest first, until the table
+	// fits or they are as narrow as their headers.
+	MaxWidth int
+
+	// Style, if set, decorates the text of each cell after it is shortened,
+	// e.g. with colors. The row is -1 for the header row. Padding is added
+	// outside the styled text, so styling doesn't affect the layout.
+	Style func(row, column int, text string) string
+}
+
+// Widths returns the width of every column after fitting the table into MaxWidth.
+func (t Table) Widths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = utf8.RuneCountInString(column.Header)
+	}
+	for _, row := range t.Rows {
+		for i := range widths {
+			if i < len(row) {
+				widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+			}
+		}
+	}
+
+	if t.MaxWidth <= 0 {
+		return widths
+	}
+	for excess := t.width(widths) - t.MaxWidth; excess > 0; excess-- {
+		widest := -1
+		for i, column := range t.Columns {
+			if column.Truncate == TruncateNone || widths[i] <= minWidth(column) {
+				continue
+			}
+			if widest == -1 || widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widest == -1 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// Width returns the total width of the table, including separators.
+func (t Table) Width() int {
+	return t.width(t.Widths())
+}
+
+func (t Table) width(widths []int) int {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	if len(widths) > 1 {
+		total += (len(widths) - 1) * utf8.RuneCountInString(t.Separator)
+	}
+	return total
+}
+
+// minWidth returns the narrowest a truncatable column may become:
+// the width of its header, but never less than a few characters.
+func minWidth(column Column) int {
+	return max(utf8.RuneCountInString(column.Header), 3)
+}
+
+// Render writes the header row, a divider made of line, and every row.
+// Trailing spaces are trimmed from each line.
+func (t Table) Render(w io.Writer, line string) error {
+	widths := t.Widths()
+
+	headers := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		headers[i] = column.Header
+	}
+	if err := t.renderRow(w, -1, headers, widths); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, strings.Repeat(line, t.width(widths))); err != nil {
+		return err
+	}
+	for i, row := range t.Rows {
+		if err := t.renderRow(w, i, row, widths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Table) renderRow(w io.Writer, rowIndex int, row []string, widths []int) error {
+	cells := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		var cell string
+		if i < len(row) {
+			cell = Shorten(row[i], widths[i], column.Truncate)
+		}
+
+		padding := strings.Repeat(" ", max(widths[i]-utf8.RuneCountInString(cell), 0))
+		if t.Style != nil && cell != "" {
+			cell = t.Style(rowIndex, i, cell)
+		}
+		if column.Align == AlignRight {
+			cells[i] = padding + cell
+		} else {
+			cells[i] = cell + padding
+		}
+	}
+
+	_, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, t.Separator), " "))
+	return err
+}
+
+// Shorten truncates text to at most width characters, marking the cut with
+// Ellipsis. Text is never shortened when mode is TruncateNone.
+func Shorten(text string, width int, mode Truncate) string {
+	runes := []rune(text)
+	if mode == TruncateNone || len(runes) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	keep := width - utf8.RuneCountInString(Ellipsis)
+	if mode == TruncateMiddle {
+		head := (keep + 1) / 2
+		tail := keep - head
+		return string(runes[:head]) + Ellipsis + string(runes[len(runes)-tail:])
+	}
+	return string(runes[:keep]) + Ellipsis
+}
+
+// TerminalWidth returns the width of the terminal standard output is
+// connected to, or 0 if it isn't a terminal.
+func TerminalWidth() int {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return 0
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0
+	}
+	return width
+}
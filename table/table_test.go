@@ -0,0 +1,100 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShorten(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		mode  Truncate
+		want  string
+	}{
+		{text: "Shopping List", width: 20, mode: TruncateEnd, want: "Shopping List"},
+		{text: "Shopping List", width: 8, mode: TruncateEnd, want: "Shoppin…"},
+		{text: "Shopping List", width: 8, mode: TruncateMiddle, want: "Shop…ist"},
+		{text: "Shopping List", width: 8, mode: TruncateNone, want: "Shopping List"},
+		{text: "Café au lait", width: 5, mode: TruncateEnd, want: "Café…"},
+	}
+
+	for _, tt := range tests {
+		if got := Shorten(tt.text, tt.width, tt.mode); got != tt.want {
+			t.Errorf("Shorten(%q, %d, %v) = %q; want %q", tt.text, tt.width, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestRenderFitsMaxWidth(t *testing.T) {
+	tbl := Table{
+		Columns: []Column{
+			{Header: "Title", Truncate: TruncateMiddle},
+			{Header: "Words", Align: AlignRight},
+			{Header: "Tags", Truncate: TruncateEnd},
+		},
+		Rows: [][]string{
+			{"A rather long note title", "1200", "work, meetings, planning"},
+			{"Short", "7", ""},
+		},
+		Separator: " | ",
+		MaxWidth:  40,
+	}
+
+	if got := tbl.Width(); got != 40 {
+		t.Errorf("Width() = %d; want 40", got)
+	}
+
+	var out strings.Builder
+	if err := tbl.Render(&out, "-"); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"Title          | Words | Tags",
+		"----------------------------------------",
+		"A rathe… title |  1200 | work, meetings…",
+		"Short          |     7 |",
+		"",
+	}, "\n")
+	if out.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestWidthsStopAtHeaders(t *testing.T) {
+	tbl := Table{
+		Columns:   []Column{{Header: "Title", Truncate: TruncateEnd}, {Header: "ID"}},
+		Rows:      [][]string{{"A rather long note title", "4f2a9c"}},
+		Separator: "  ",
+		MaxWidth:  5,
+	}
+
+	widths := tbl.Widths()
+	if widths[0] != len("Title") || widths[1] != len("4f2a9c") {
+		t.Errorf("Widths() = %v; want [5 6]", widths)
+	}
+}
+
+func TestStyleKeepsLayout(t *testing.T) {
+	tbl := Table{
+		Columns:   []Column{{Header: "Title"}, {Header: "Words", Align: AlignRight}},
+		Rows:      [][]string{{"Groceries", "12"}},
+		Separator: " | ",
+		Style: func(row, column int, text string) string {
+			if row == -1 {
+				return "<" + text + ">"
+			}
+			return text
+		},
+	}
+
+	var out strings.Builder
+	if err := tbl.Render(&out, "-"); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "<Title>     | <Words>\n-----------------\nGroceries |    12\n"
+	if out.String() != want {
+		t.Errorf("Render() =\n%q\nwant\n%q", out.String(), want)
+	}
+}
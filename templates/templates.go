@@ -0,0 +1,78 @@
+// Package templates renders note templates.
+//
+// Templates use text/template syntax. The following variables are always
+// available, alongside any custom variables passed with --var key=value:
+//
+//	{{.Title}}     the title of the new note
+//	{{.Notebook}}  the notebook the note is created in
+//	{{.Date}}      the date, e.g. 2026-10-19
+//	{{.Time}}      the time, e.g. 14:05
+//	{{.Weekday}}   the day of the week, e.g. Monday
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	dateFormat = "2006-01-02"
+	timeFormat = "15:04"
+)
+
+// Variables returns the variables available to a template rendered for a
+// note with the given title and notebook at the given time.
+// Custom variables override the built-in ones.
+func Variables(title, notebook string, at time.Time, custom map[string]string) map[string]string {
+	variables := map[string]string{
+		"Title":    title,
+		"Notebook": notebook,
+		"Date":     at.Format(dateFormat),
+		"Time":     at.Format(timeFormat),
+		"Weekday":  at.Weekday().String(),
+	}
+	for key, value := range custom {
+		variables[key] = value
+	}
+	return variables
+}
+
+// Parse checks that content is a valid template.
+func Parse(content string) (*template.Template, error) {
+	tmpl, err := template.New("note").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render renders the template content with the given variables.
+// Referring to a variable that isn't defined is an error.
+func Render(content string, variables map[string]string) (string, error) {
+	tmpl, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, variables); err != nil {
+		return "", fmt.Errorf("error rendering template: %w", err)
+	}
+	return rendered.String(), nil
+}
+
+// ParseVars converts "key=value" pairs into a map of custom variables.
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid variable %q: expected key=value", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	at := time.Date(2026, time.October, 19, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		content string
+		custom  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Built-in Variables",
+			content: "# {{.Title}} ({{.Weekday}} {{.Date}} {{.Time}})",
+			want:    "# Retro (Monday 2026-10-19 09:30)",
+		},
+		{
+			name:    "Custom Variables",
+			content: "Client: {{.client}}",
+			custom:  map[string]string{"client": "ACME"},
+			want:    "Client: ACME",
+		},
+		{
+			name:    "Custom Variables Override Built-ins",
+			content: "{{.Date}}",
+			custom:  map[string]string{"Date": "tomorrow"},
+			want:    "tomorrow",
+		},
+		{
+			name:    "Missing Variable",
+			content: "Client: {{.client}}",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Syntax",
+			content: "{{.Title",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.content, Variables("Retro", "work", at, tt.custom))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"client=ACME", "topic=a=b"})
+	if err != nil {
+		t.Fatalf("ParseVars() error = %v", err)
+	}
+	if vars["client"] != "ACME" || vars["topic"] != "a=b" {
+		t.Errorf("ParseVars() = %v", vars)
+	}
+
+	if _, err := ParseVars([]string{"novalue"}); err == nil {
+		t.Error("Expected error for variable without '='")
+	}
+}
@@ -26,6 +26,7 @@ func CreateTestNote() models.Note {
 	return models.Note{
 		ID:         uuid.New().String(),
 		Title:      TestValidNoteTitle,
+		Notebook:   db.DefaultNotebook,
 		Content:    TestNoteContent,
 		CreatedAt:  time.Now(),
 		ModifiedAt: time.Now(),
@@ -67,20 +68,20 @@ func TestNoteContentSaved(t *testing.T, note models.Note, database *bolt.DB) {
 }
 
 // TestNoteTitleSaved verifies that a note's title mapping was correctly saved in the database.
-// It checks that the note's title maps to the correct ID in the title bucket.
+// It checks that the note's title maps to the correct ID in the title bucket of its notebook.
 func TestNoteTitleSaved(t *testing.T, note models.Note, database *bolt.DB) {
 	var retrievedNoteID string
 
 	err := database.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(db.NotesTitleBucket))
+		bucket, err := db.NotebookTitles(tx, note.Notebook)
 
-		if bucket == nil {
-			t.Errorf("Bucket not found; expected %s", db.NotesTitleBucket)
-			return fmt.Errorf("bucket not found; expected %s", db.NotesTitleBucket)
+		if err != nil {
+			t.Errorf("Notebook not found; expected %s", note.Notebook)
+			return err
 		}
 
 		// retrieving the Note ID associated with the Note Title
-		retrievedNote := bucket.Get([]byte(note.Title))
+		retrievedNote := bucket.Get(db.TitleKey(note.Title))
 		if retrievedNote == nil {
 			t.Errorf("Note not found; expected %s", note.Title)
 			return fmt.Errorf("note not found; expected %s", note.Title)
@@ -143,5 +144,18 @@ func SetupTestDB(t *testing.T) (*bolt.DB, string) {
 		t.Fatalf("Couldn't create %v bucket: %v", db.NotesTitleBucket, err)
 	}
 
+	err = testDB.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(db.TemplatesBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Couldn't create %v bucket: %v", db.TemplatesBucket, err)
+	}
+
+	// Bring the schema up to date, which also creates the default notebook
+	if _, err := db.Migrate(testDB); err != nil {
+		t.Fatalf("Couldn't migrate test database: %v", err)
+	}
+
 	return testDB, testTempDir
 }
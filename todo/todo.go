@@ -0,0 +1,71 @@
+// Package todo parses Markdown-style checklist items from note content.
+// An item is a line starting with "- [ ] " when open or "- [x] " when done;
+// the bullet may also be '*' or '+', and the line may be indented.
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// itemPattern matches a checklist line, capturing everything up to the
+// checkbox's mark, the mark itself, and the item's text.
+var itemPattern = regexp.MustCompile(`^(\s*[-*+] \[)([ xX])\](?:\s+(.*))?$`)
+
+// Item is a single checklist item.
+type Item struct {
+	// Line is the 1-based line number of the item in the note's content.
+	Line int
+	Text string
+	Done bool
+}
+
+// Parse returns the checklist items in content, in order.
+func Parse(content string) []Item {
+	var items []Item
+	for i, line := range strings.Split(content, "\n") {
+		match := itemPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+		items = append(items, Item{
+			Line: i + 1,
+			Text: strings.TrimSpace(match[3]),
+			Done: match[2] != " ",
+		})
+	}
+	return items
+}
+
+// Progress returns the number of done items and the total number of items in content.
+func Progress(content string) (done, total int) {
+	for _, item := range Parse(content) {
+		if item.Done {
+			done++
+		}
+		total++
+	}
+	return done, total
+}
+
+// MarkDone ticks the checklist item on the given 1-based line of content.
+// It returns the updated content and whether the item was open.
+func MarkDone(content string, line int) (string, bool, error) {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		return "", false, fmt.Errorf("line %d does not exist", line)
+	}
+
+	match := itemPattern.FindStringSubmatchIndex(strings.TrimRight(lines[line-1], "\r"))
+	if match == nil {
+		return "", false, fmt.Errorf("line %d is not a checklist item", line)
+	}
+
+	markStart := match[4]
+	if lines[line-1][markStart] != ' ' {
+		return content, false, nil
+	}
+	lines[line-1] = lines[line-1][:markStart] + "x" + lines[line-1][markStart+1:]
+	return strings.Join(lines, "\n"), true, nil
+}
@@ -0,0 +1,69 @@
+package todo
+
+import "testing"
+
+const checklist = `# Groceries
+- [ ] milk
+- [x] bread
+  * [X] eggs
++ [ ]
+not - [ ] an item
+-[ ] nor this`
+
+func TestParse(t *testing.T) {
+	want := []Item{
+		{Line: 2, Text: "milk", Done: false},
+		{Line: 3, Text: "bread", Done: true},
+		{Line: 4, Text: "eggs", Done: true},
+		{Line: 5, Text: "", Done: false},
+	}
+
+	got := Parse(checklist)
+	if len(got) != len(want) {
+		t.Fatalf("Parse() = %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Parse()[%d] = %+v; want %+v", i, got[i], want[i])
+		}
+	}
+
+	done, total := Progress(checklist)
+	if done != 2 || total != 4 {
+		t.Errorf("Progress() = %d/%d; want 2/4", done, total)
+	}
+}
+
+func TestMarkDone(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        int
+		wantChanged bool
+		wantErr     bool
+	}{
+		{name: "Open Item", line: 2, wantChanged: true},
+		{name: "Done Item", line: 3, wantChanged: false},
+		{name: "Not An Item", line: 1, wantErr: true},
+		{name: "Past The End", line: 20, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, changed, err := MarkDone(checklist, tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MarkDone() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("MarkDone() changed = %v; want %v", changed, tt.wantChanged)
+			}
+			for _, item := range Parse(content) {
+				if item.Line == tt.line && !item.Done {
+					t.Errorf("Item on line %d is still open", tt.line)
+				}
+			}
+		})
+	}
+}
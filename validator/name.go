@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// IllegalNameChars are the characters note titles and notebook names
+	// can't contain.
+	IllegalNameChars = "\\/:*?\"<>|."
+
+	// MinNameLength and MaxNameLength bound the length of note titles and
+	// notebook names, in characters.
+	MinNameLength = 1
+	MaxNameLength = 20
+)
+
+// ValidateName checks that name is a valid note title or notebook name.
+// kind, such as "note" or "notebook", names it in errors.
+func ValidateName(kind, name string) error {
+	if err := ValidateNameLength(kind, name); err != nil {
+		return err
+	}
+	return ValidateNameCharacters(name)
+}
+
+// ValidateNameLength checks that name is neither empty nor longer than
+// MaxNameLength. Lengths are counted in characters (runes) of the NFC form,
+// not bytes, so accented names are not penalized.
+func ValidateNameLength(kind, name string) error {
+	nameTrimmed := norm.NFC.String(strings.TrimSpace(name))
+	nameLength := utf8.RuneCountInString(nameTrimmed)
+
+	if nameLength < MinNameLength {
+		return fmt.Errorf("%s name cannot be empty", kind)
+	}
+	if nameLength > MaxNameLength {
+		return fmt.Errorf("%s name %q must be less than %d characters", kind, nameTrimmed, MaxNameLength)
+	}
+	return nil
+}
+
+// ValidateNameCharacters checks that name doesn't contain any of the
+// IllegalNameChars. The error lists the illegal characters found.
+func ValidateNameCharacters(name string) error {
+	var illegalCharsFound []rune
+
+	for _, char := range name {
+		if strings.ContainsRune(IllegalNameChars, char) {
+			illegalCharsFound = append(illegalCharsFound, char)
+		}
+	}
+	if len(illegalCharsFound) > 0 {
+		return fmt.Errorf("name contains illegal characters: %q", string(illegalCharsFound))
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+// The notes UI: a list of notes on the left and the selected note, shown or
+// edited, on the right. Pages are addressed by the URL fragment:
+//
+//   #/                  no note selected
+//   #/notes/<id>        show a note
+//   #/notes/<id>/edit   edit a note
+//
+// Everything goes through the JSON API served alongside this page.
+"use strict";
+
+const tokenKey = "cli-note-token";
+
+const $ = (id) => document.getElementById(id);
+
+const state = {
+  notes: [],   // every note, from GET /notes
+  note: null,  // the selected note
+  etag: "",    // the ETag of the selected note, sent back as If-Match
+};
+
+// ApiError is a failed API request.
+class ApiError extends Error {
+  constructor(status, message) {
+    super(message);
+    this.status = status;
+  }
+}
+
+// api sends a request to the server, asking for the bearer token if the
+// server wants one, and returns the response.
+async function api(method, path, { body, ifMatch } = {}) {
+  const headers = {};
+  const token = sessionStorage.getItem(tokenKey);
+  if (token) headers["Authorization"] = "Bearer " + token;
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+  if (ifMatch) headers["If-Match"] = ifMatch;
+
+  const response = await fetch(path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+
+  if (response.status === 401) {
+    const entered = prompt("This server needs a token (see 'cli-note serve --token'):");
+    if (entered) {
+      sessionStorage.setItem(tokenKey, entered);
+      return api(method, path, { body, ifMatch });
+    }
+  }
+  if (!response.ok) {
+    let message = response.statusText;
+    try {
+      message = (await response.json()).error || message;
+    } catch (e) {
+      // Not a JSON error; keep the status text
+    }
+    throw new ApiError(response.status, message);
+  }
+  return response;
+}
+
+function notePath(id) {
+  return "/notes/" + encodeURIComponent(id);
+}
+
+// flashed is a notice to show once the next page has opened.
+let flashed = "";
+
+// showMessage shows a notice above the note, or hides it if text is empty.
+function showMessage(text, isError) {
+  const message = $("message");
+  message.textContent = text || "";
+  message.hidden = !text;
+  message.classList.toggle("error", Boolean(isError));
+}
+
+function formatDate(value) {
+  return new Date(value).toLocaleString(undefined, { dateStyle: "medium", timeStyle: "short" });
+}
+
+// loadNotes fetches every note and refreshes the list and notebook filter.
+async function loadNotes() {
+  state.notes = await (await api("GET", "/notes")).json();
+
+  const select = $("notebook");
+  const selected = select.value;
+  const notebooks = [...new Set(state.notes.map((note) => note.notebook))].sort();
+  select.replaceChildren(new Option("All notebooks", ""), ...notebooks.map((name) => new Option(name, name)));
+  select.value = notebooks.includes(selected) ? selected : "";
+
+  await renderList();
+}
+
+// renderList shows the notes matching the search box and filters, pinned
+// notes first, then the most recently modified.
+async function renderList() {
+  const query = $("search").value.trim();
+  const notebook = $("notebook").value;
+  const showArchived = $("show-archived").checked;
+
+  let notes = state.notes;
+  if (query) {
+    notes = await (await api("GET", "/search?q=" + encodeURIComponent(query))).json();
+  }
+  notes = notes
+    .filter((note) => (!notebook || note.notebook === notebook) && (showArchived || !note.archived))
+    .sort((a, b) => (b.pinned - a.pinned) || (new Date(b.modified_at) - new Date(a.modified_at)));
+
+  const list = $("notes");
+  list.replaceChildren(...notes.map((note) => {
+    const link = document.createElement("a");
+    link.href = "#" + notePath(note.id);
+    link.textContent = note.title;
+    link.classList.toggle("pinned", note.pinned);
+    link.classList.toggle("archived", note.archived);
+    link.classList.toggle("active", state.note !== null && state.note.id === note.id);
+
+    const where = document.createElement("div");
+    where.className = "notebook";
+    where.textContent = note.notebook + " · " + formatDate(note.modified_at);
+    link.append(where);
+
+    const item = document.createElement("li");
+    item.append(link);
+    return item;
+  }));
+  $("no-notes").hidden = notes.length > 0;
+}
+
+// show displays one of the panes: "view", "editor" or "empty".
+function show(pane) {
+  for (const id of ["view", "editor", "empty"]) {
+    $(id).hidden = id !== pane;
+  }
+}
+
+// openNote fetches a note, remembering its ETag.
+async function openNote(id) {
+  const response = await api("GET", notePath(id));
+  state.note = await response.json();
+  state.etag = response.headers.get("ETag") || "";
+}
+
+async function viewNote(id) {
+  await openNote(id);
+  const note = state.note;
+  const html = await (await api("GET", notePath(id) + "/html")).text();
+
+  document.title = note.title + " · Notes";
+  $("view-title").textContent = note.title;
+  $("view-meta").textContent = [
+    note.notebook,
+    "created " + formatDate(note.created_at),
+    "modified " + formatDate(note.modified_at),
+    note.pinned ? "pinned" : "",
+    note.archived ? "archived" : "",
+  ].filter(Boolean).join(" · ");
+  $("view-tags").replaceChildren(...(note.tags || []).map((tag) => {
+    const item = document.createElement("li");
+    item.textContent = tag;
+    return item;
+  }));
+  // The server renders Markdown with text escaped and unsafe links removed
+  $("view-content").innerHTML = html;
+  show("view");
+}
+
+async function editNote(id) {
+  await openNote(id);
+  const note = state.note;
+
+  document.title = "Editing " + note.title + " · Notes";
+  $("edit-title").value = note.title;
+  $("edit-tags").value = (note.tags || []).join(", ");
+  $("edit-pinned").checked = note.pinned;
+  $("edit-archived").checked = note.archived;
+  $("edit-content").value = note.content;
+  show("editor");
+  $("edit-content").focus();
+}
+
+async function saveNote(event) {
+  event.preventDefault();
+  const note = state.note;
+  const input = {
+    title: $("edit-title").value.trim(),
+    content: $("edit-content").value,
+    tags: $("edit-tags").value.split(",").map((tag) => tag.trim()).filter(Boolean),
+    pinned: $("edit-pinned").checked,
+    archived: $("edit-archived").checked,
+  };
+
+  try {
+    await api("PUT", notePath(note.id), { body: input, ifMatch: state.etag });
+  } catch (error) {
+    if (error.status === 412) {
+      showMessage("This note was changed elsewhere since you opened it. " +
+        "Copy your changes, then reload the page to see the latest version.", true);
+      return;
+    }
+    throw error;
+  }
+
+  flashed = "Saved.";
+  await loadNotes();
+  location.hash = "#" + notePath(note.id);
+}
+
+async function deleteNote() {
+  const note = state.note;
+  if (!confirm("Delete \"" + note.title + "\"? This cannot be undone.")) return;
+
+  try {
+    await api("DELETE", notePath(note.id), { ifMatch: state.etag });
+  } catch (error) {
+    if (error.status === 412) {
+      showMessage("This note was changed elsewhere since you opened it, so it wasn't deleted.", true);
+      return;
+    }
+    throw error;
+  }
+
+  flashed = "Deleted \"" + note.title + "\".";
+  state.note = null;
+  await loadNotes();
+  location.hash = "#/";
+}
+
+async function newNote() {
+  const title = prompt("Title of the new note:");
+  if (!title) return;
+
+  const input = { title: title.trim() };
+  if ($("notebook").value) input.notebook = $("notebook").value;
+
+  const note = await (await api("POST", "/notes", { body: input })).json();
+  await loadNotes();
+  location.hash = "#" + notePath(note.id) + "/edit";
+}
+
+// route shows the page the URL fragment asks for.
+async function route() {
+  const match = location.hash.match(/^#\/notes\/([^/]+)(\/edit)?$/);
+  if (!match) {
+    state.note = null;
+    document.title = "Notes";
+    show("empty");
+  } else if (match[2]) {
+    await editNote(decodeURIComponent(match[1]));
+  } else {
+    await viewNote(decodeURIComponent(match[1]));
+  }
+  await renderList();
+}
+
+// report shows errors from event handlers instead of losing them.
+function report(handler) {
+  return (event) => handler(event).catch((error) => showMessage(error.message, true));
+}
+
+let searchTimer;
+$("search").addEventListener("input", () => {
+  clearTimeout(searchTimer);
+  searchTimer = setTimeout(report(renderList), 200);
+});
+$("notebook").addEventListener("change", report(renderList));
+$("show-archived").addEventListener("change", report(renderList));
+$("new-note").addEventListener("click", report(newNote));
+$("edit").addEventListener("click", () => { location.hash = "#" + notePath(state.note.id) + "/edit"; });
+$("delete").addEventListener("click", report(deleteNote));
+$("cancel").addEventListener("click", () => { location.hash = "#" + notePath(state.note.id); });
+$("editor").addEventListener("submit", report(saveNote));
+window.addEventListener("hashchange", () => {
+  showMessage(flashed);
+  flashed = "";
+  report(route)();
+});
+
+report(async () => {
+  await loadNotes();
+  await route();
+})();
@@ -0,0 +1,24 @@
+// Package web is the browser UI served by 'cli-note serve': a single page
+// that lists, searches, shows and edits notes through the JSON API.
+//
+// The page and its script and style sheet are embedded in the binary and
+// load nothing from elsewhere, so the UI works offline.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed ui
+var files embed.FS
+
+// Handler serves the UI's files, with index.html at the root.
+func Handler() http.Handler {
+	ui, err := fs.Sub(files, "ui")
+	if err != nil {
+		panic(err) // the embedded directory is always there
+	}
+	return http.FileServerFS(ui)
+}
@@ -0,0 +1,38 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerServesUI(t *testing.T) {
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/", "text/html", `<script src="app.js">`},
+		{"/app.js", "javascript", "function route()"},
+		{"/style.css", "text/css", ".note-list"},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d; want %d", tt.path, recorder.Code, http.StatusOK)
+			continue
+		}
+		if got := recorder.Header().Get("Content-Type"); !strings.Contains(got, tt.contentType) {
+			t.Errorf("GET %s Content-Type = %q; want %q", tt.path, got, tt.contentType)
+		}
+		body, _ := io.ReadAll(recorder.Body)
+		if !strings.Contains(string(body), tt.contains) {
+			t.Errorf("GET %s body doesn't contain %q", tt.path, tt.contains)
+		}
+	}
+}